@@ -0,0 +1,177 @@
+package main
+
+import (
+	"armiariyan/attendances-system/config"
+	"armiariyan/attendances-system/helper"
+	"armiariyan/attendances-system/logger"
+	"armiariyan/attendances-system/migration"
+	"armiariyan/attendances-system/service"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// runCommand runs the subcommand named by args instead of the http server,
+// ok is false when args don't name a subcommand
+func runCommand(args []string) (ok bool, err error) {
+	if len(args) == 0 {
+		return false, nil
+	}
+
+	switch args[0] {
+	case "export-payroll":
+		return true, exportPayroll(args[1:], os.Stdout)
+	case "detect-absences":
+		return true, detectAbsences(args[1:], os.Stdout)
+	case "migrate":
+		return true, migrate(args[1:], os.Stdout)
+	default:
+		return false, nil
+	}
+}
+
+// printConfig writes the effective config with secrets redacted, then tells whether it is valid
+func printConfig(cfg config.Config, stdout io.Writer) error {
+	if err := config.Print(stdout, cfg); err != nil {
+		return err
+	}
+	return cfg.Validate()
+}
+
+// migrate applies or reverts the schema migrations, or lists them with status
+func migrate(args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("migrate needs up, down or status")
+	}
+
+	flags := flag.NewFlagSet("migrate "+args[0], flag.ContinueOnError)
+	switch args[0] {
+	case "up":
+		to := flags.Int("to", 0, "version to migrate to, default to the latest")
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+		applied, err := migration.Up(db, *to)
+		for _, done := range applied {
+			fmt.Fprintf(stdout, "applied %d %s\n", done.Version, done.Name)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Fprintln(stdout, "nothing to apply")
+		}
+		return err
+	case "down":
+		steps := flags.Int("steps", 1, "number of migrations to revert")
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+		reverted, err := migration.Down(db, *steps)
+		for _, done := range reverted {
+			fmt.Fprintf(stdout, "reverted %d %s\n", done.Version, done.Name)
+		}
+		return err
+	case "status":
+		statuses, err := migration.Statuses(db)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			switch {
+			case status.Name == "":
+				fmt.Fprintf(stdout, "%4d unknown to this build, applied %s\n", status.Version, time.UnixMilli(status.AppliedAt).Format(time.RFC3339))
+			case status.AppliedAt == 0:
+				fmt.Fprintf(stdout, "%4d %-20s pending\n", status.Version, status.Name)
+			default:
+				fmt.Fprintf(stdout, "%4d %-20s applied %s\n", status.Version, status.Name, time.UnixMilli(status.AppliedAt).Format(time.RFC3339))
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("migrate needs up, down or status, not %q", args[0])
+	}
+}
+
+// migrateOnStart applies the pending migrations when the config asks for it,
+// otherwise it only warns that the schema is behind
+func migrateOnStart(cfg config.DatabaseConfig) error {
+	if cfg.MigrateOnStart {
+		applied, err := migration.Up(db, 0)
+		for _, done := range applied {
+			logger.Log.WithFields(logrus.Fields{"version": done.Version, "name": done.Name}).Info("applied migration")
+		}
+		return err
+	}
+
+	pending, err := migration.Pending(db)
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		logger.Log.WithField("pending", len(pending)).Warn("migrations are pending, run the migrate up command")
+	}
+	return nil
+}
+
+// exportPayroll writes the payroll file of a date range to --out or stdout
+func exportPayroll(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("export-payroll", flag.ContinueOnError)
+	startDate := flags.String("from", "", "first day of the range, formatted as 2006-01-02")
+	endDate := flags.String("to", "", "last day of the range, formatted as 2006-01-02")
+	format := flags.String("format", service.PayrollFormatCSV, "csv or fixed")
+	out := flags.String("out", "", "file to write, default to stdout")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	timeRange, err := helper.ParseClosedTimeRange(helper.RangeQuery{Start: *startDate, End: *endDate}, time.Now())
+	if err != nil {
+		return fmt.Errorf("invalid range --from %q --to %q: %w", *startDate, *endDate, err)
+	}
+	_, end := timeRange.UnixMilli()
+
+	w := stdout
+	if *out != "" {
+		file, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+	return payrollService.Export(context.Background(), w, timeRange.From, time.UnixMilli(end), *format)
+}
+
+// detectAbsences runs the absence detection for every day of a range, default to yesterday
+func detectAbsences(args []string, stdout io.Writer) error {
+	yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+	flags := flag.NewFlagSet("detect-absences", flag.ContinueOnError)
+	startDate := flags.String("from", yesterday, "first day to check, formatted as 2006-01-02")
+	endDate := flags.String("to", "", "last day to check, default to --from")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *endDate == "" {
+		*endDate = *startDate
+	}
+
+	timeRange, err := helper.ParseClosedTimeRange(helper.RangeQuery{Start: *startDate, End: *endDate}, time.Now())
+	if err != nil {
+		return fmt.Errorf("invalid range --from %q --to %q: %w", *startDate, *endDate, err)
+	}
+	if today, _ := helper.DayRange(time.Now()); timeRange.To.UnixMilli() > today {
+		return fmt.Errorf("invalid range: --to must be before today")
+	}
+
+	for day := timeRange.From; day.Before(timeRange.To); day = day.AddDate(0, 0, 1) {
+		absences, err := absenceService.DetectAbsences(context.Background(), day)
+		if err != nil {
+			return fmt.Errorf("%s: %w", day.Format("2006-01-02"), err)
+		}
+		fmt.Fprintf(stdout, "%s: %d absences\n", day.Format("2006-01-02"), len(absences))
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package config
+
+import (
+	"armiariyan/attendances-system/export"
+	"armiariyan/attendances-system/job"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// Config is every setting of the app. Each field is taken from its default, then the
+// config file under key, then the env var, then the command-line flag, the last one set
+// wins. Secret fields may also be read from a file named by <key>_file, <ENV>_FILE or
+// --<flag>-file, and are redacted when printed
+type Config struct {
+	Server   ServerConfig   `key:"server"`
+	Log      LogConfig      `key:"log"`
+	Database DatabaseConfig `key:"database"`
+	Session  SessionConfig  `key:"session"`
+	Kiosk    KioskConfig    `key:"kiosk"`
+	Work     WorkConfig     `key:"work"`
+	Payroll  PayrollConfig  `key:"payroll"`
+}
+
+type ServerConfig struct {
+	Port           int      `key:"port" env:"PORT" flag:"port" default:"8080" usage:"port the http server listens on"`
+	TrustedProxies []string `key:"trusted_proxies" env:"TRUSTED_PROXIES" flag:"trusted-proxies" usage:"comma separated proxies allowed to set X-Forwarded-For"`
+	// Timeouts are durations like 30s, 0 means no timeout
+	ReadHeaderTimeout time.Duration `key:"read_header_timeout" env:"SERVER_READ_HEADER_TIMEOUT" flag:"read-header-timeout" default:"10s" usage:"time to read the headers of a request"`
+	ReadTimeout       time.Duration `key:"read_timeout" env:"SERVER_READ_TIMEOUT" flag:"read-timeout" default:"30s" usage:"time to read a whole request, body included"`
+	WriteTimeout      time.Duration `key:"write_timeout" env:"SERVER_WRITE_TIMEOUT" flag:"write-timeout" default:"60s" usage:"time to handle a request and write its response"`
+	IdleTimeout       time.Duration `key:"idle_timeout" env:"SERVER_IDLE_TIMEOUT" flag:"idle-timeout" default:"120s" usage:"time a keep-alive connection waits for the next request"`
+	// On SIGINT or SIGTERM the server stops being ready, waits DrainDelay so load
+	// balancers stop sending requests, then waits up to ShutdownTimeout for the
+	// requests in flight before it stops the jobs, waits up to ShutdownTimeout
+	// again for them and closes the database
+	DrainDelay      time.Duration `key:"drain_delay" env:"SERVER_DRAIN_DELAY" flag:"drain-delay" default:"0s" usage:"time the server keeps taking requests while not ready before shutting down"`
+	ShutdownTimeout time.Duration `key:"shutdown_timeout" env:"SERVER_SHUTDOWN_TIMEOUT" flag:"shutdown-timeout" default:"30s" usage:"time requests in flight get to finish on shutdown"`
+	// /readyz answers 503 when the database or the session store take longer than HealthCheckTimeout
+	HealthCheckTimeout time.Duration `key:"health_check_timeout" env:"SERVER_HEALTH_CHECK_TIMEOUT" flag:"health-check-timeout" default:"2s" usage:"time each dependency checked by /readyz gets to answer"`
+}
+
+// LogConfig is json at info level for production, text at debug level reads better in development
+type LogConfig struct {
+	Level  string `key:"level" env:"LOG_LEVEL" flag:"log-level" default:"info" usage:"lowest level logged, debug, info, warn or error"`
+	Format string `key:"format" env:"LOG_FORMAT" flag:"log-format" default:"json" usage:"format of the log lines, json or text"`
+}
+
+type DatabaseConfig struct {
+	Driver   string `key:"driver" env:"DB_DRIVER" flag:"db-driver" default:"mysql" usage:"database driver, mysql, postgres or sqlite"`
+	Host     string `key:"host" env:"DB_HOST" flag:"db-host" default:"localhost" usage:"database host"`
+	Port     int    `key:"port" env:"DB_PORT" flag:"db-port" usage:"database port, 3306 for mysql and 5432 for postgres when empty"`
+	User     string `key:"user" env:"DB_USER" flag:"db-user" usage:"database user"`
+	Password string `key:"password" env:"DB_PASS" flag:"db-password" secret:"true" usage:"database password"`
+	Name     string `key:"name" env:"DB_NAME" flag:"db-name" usage:"database name, the file path for sqlite"`
+	SSLMode  string `key:"ssl_mode" env:"DB_SSL_MODE" flag:"db-ssl-mode" usage:"postgres sslmode like disable or verify-full, prefer when empty"`
+	// The schema is only changed by the migrate command unless MigrateOnStart is set
+	MigrateOnStart bool `key:"migrate_on_start" env:"DB_MIGRATE_ON_START" flag:"db-migrate-on-start" default:"false" usage:"apply pending migrations before the server starts, true or false"`
+}
+
+type SessionConfig struct {
+	// session_secret is the name of the first version, still read so deployments keep their sessions
+	Secret string `key:"secret" env:"SESSION_SECRET,session_secret" flag:"session-secret" secret:"true" usage:"key signing the session cookie, at least 16 characters"`
+}
+
+type KioskConfig struct {
+	Secret string `key:"secret" env:"KIOSK_SECRET" flag:"kiosk-secret" secret:"true" usage:"key signing kiosk tokens, random when empty so tokens don't survive a restart"`
+	Key    string `key:"key" env:"KIOSK_KEY" flag:"kiosk-key" secret:"true" usage:"key the kiosk sends to get tokens"`
+}
+
+type WorkConfig struct {
+	Days                 []string `key:"days" env:"WORK_DAYS" flag:"work-days" default:"mon,tue,wed,thu,fri" usage:"comma separated days of the work week"`
+	DailyHours           float64  `key:"daily_hours" env:"DAILY_WORK_HOURS" flag:"daily-work-hours" default:"8" usage:"hours of a work day, more is overtime"`
+	AbsenceDetectionTime string   `key:"absence_detection_time" env:"ABSENCE_DETECTION_TIME" flag:"absence-detection-time" default:"00:30" usage:"time of day absences of the day before are detected, formatted as 15:04"`
+}
+
+type PayrollConfig struct {
+	FixedWidthLayout string `key:"fixed_width_layout" env:"PAYROLL_FIXED_WIDTH_LAYOUT" flag:"payroll-fixed-width-layout" usage:"json layout of the fixed width payroll file, the default layout when empty"`
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// Weekdays returns the days of the work week
+func (work WorkConfig) Weekdays() []time.Weekday {
+	var days []time.Weekday
+	for _, name := range work.Days {
+		if day, ok := weekdayNames[strings.ToLower(name)]; ok {
+			days = append(days, day)
+		}
+	}
+	return days
+}
+
+// AbsenceDetectionAt is the time of day the absence detection runs
+func (work WorkConfig) AbsenceDetectionAt() time.Duration {
+	at, _ := job.ParseTimeOfDay(work.AbsenceDetectionTime)
+	return at
+}
+
+// Validate returns every problem of the config at once, one per line
+func (cfg Config) Validate() error {
+	var problems []string
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			problems = append(problems, fmt.Sprintf(format, args...))
+		}
+	}
+
+	check(cfg.Server.Port > 0 && cfg.Server.Port < 65536, "server.port %d must be between 1 and 65535", cfg.Server.Port)
+	for _, proxy := range cfg.Server.TrustedProxies {
+		_, _, cidrErr := net.ParseCIDR(proxy)
+		check(cidrErr == nil || net.ParseIP(proxy) != nil, "server.trusted_proxies %q must be an ip or a cidr", proxy)
+	}
+
+	for name, timeout := range map[string]time.Duration{
+		"read_header_timeout": cfg.Server.ReadHeaderTimeout,
+		"read_timeout":        cfg.Server.ReadTimeout,
+		"write_timeout":       cfg.Server.WriteTimeout,
+		"idle_timeout":        cfg.Server.IdleTimeout,
+		"drain_delay":         cfg.Server.DrainDelay,
+	} {
+		check(timeout >= 0, "server.%s %s must not be negative", name, timeout)
+	}
+	check(cfg.Server.ShutdownTimeout > 0, "server.shutdown_timeout %s must be more than 0", cfg.Server.ShutdownTimeout)
+	check(cfg.Server.HealthCheckTimeout > 0, "server.health_check_timeout %s must be more than 0", cfg.Server.HealthCheckTimeout)
+
+	switch cfg.Log.Level {
+	case "debug", "info", "warn", "error":
+	default:
+		check(false, "log.level %q must be debug, info, warn or error", cfg.Log.Level)
+	}
+	check(cfg.Log.Format == "json" || cfg.Log.Format == "text", "log.format %q must be json or text", cfg.Log.Format)
+
+	switch cfg.Database.Driver {
+	case DriverMySQL, DriverPostgres:
+		check(cfg.Database.Host != "", "database.host is required (DB_HOST)")
+		check(cfg.Database.Port >= 0 && cfg.Database.Port < 65536, "database.port %d must be between 1 and 65535", cfg.Database.Port)
+		check(cfg.Database.User != "", "database.user is required (DB_USER)")
+		check(cfg.Database.Name != "", "database.name is required (DB_NAME)")
+	case DriverSQLite:
+		check(cfg.Database.Name != "", "database.name is required, the path of the sqlite file (DB_NAME)")
+	default:
+		check(false, "database.driver %q must be mysql, postgres or sqlite", cfg.Database.Driver)
+	}
+
+	check(len(cfg.Session.Secret) >= 16, "session.secret must be at least 16 characters (SESSION_SECRET)")
+
+	check(len(cfg.Work.Days) > 0, "work.days must name at least one day")
+	for _, name := range cfg.Work.Days {
+		_, ok := weekdayNames[strings.ToLower(name)]
+		check(ok, "work.days %q must be one of sun, mon, tue, wed, thu, fri or sat", name)
+	}
+	check(cfg.Work.DailyHours > 0 && cfg.Work.DailyHours <= 24, "work.daily_hours %v must be more than 0 and at most 24", cfg.Work.DailyHours)
+	_, err := job.ParseTimeOfDay(cfg.Work.AbsenceDetectionTime)
+	check(err == nil, "work.absence_detection_time %q must be formatted as 15:04", cfg.Work.AbsenceDetectionTime)
+
+	if cfg.Payroll.FixedWidthLayout != "" {
+		_, err := export.LoadFixedWidthLayout(cfg.Payroll.FixedWidthLayout)
+		check(err == nil, "payroll.fixed_width_layout: %v", err)
+	}
+
+	if len(problems) > 0 {
+		return errors.New("invalid config:\n  " + strings.Join(problems, "\n  "))
+	}
+	return nil
+}
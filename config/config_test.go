@@ -0,0 +1,188 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func lookupIn(env map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLayersOverrideEachOther(t *testing.T) {
+	file := writeFile(t, "config.yaml", `
+server:
+  port: 9000
+database:
+  host: db.internal
+  port: 3307
+  user: file
+work:
+  days: [mon, tue]
+`)
+	env := map[string]string{"CONFIG_FILE": file, "DB_USER": "env", "DB_PORT": "3308"}
+
+	cfg, args, err := load([]string{"--db-port", "3309", "--shutdown-timeout", "1m30s", "print-config"}, lookupIn(env), &bytes.Buffer{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Server.Port != 9000 || cfg.Database.Host != "db.internal" {
+		t.Errorf("file values not applied: %+v", cfg)
+	}
+	if cfg.Database.User != "env" {
+		t.Errorf("database.user = %q, want the env over the file", cfg.Database.User)
+	}
+	if cfg.Database.Port != 3309 {
+		t.Errorf("database.port = %d, want the flag over the env", cfg.Database.Port)
+	}
+	if cfg.Server.ShutdownTimeout != 90*time.Second || cfg.Server.ReadTimeout != 30*time.Second {
+		t.Errorf("server timeouts = %+v, want the flag and default durations", cfg.Server)
+	}
+	if strings.Join(cfg.Work.Days, ",") != "mon,tue" || cfg.Work.DailyHours != 8 {
+		t.Errorf("work = %+v, want file days and default hours", cfg.Work)
+	}
+	if len(args) != 1 || args[0] != "print-config" {
+		t.Errorf("args = %v, want the command left", args)
+	}
+}
+
+func TestTomlFile(t *testing.T) {
+	file := writeFile(t, "config.toml", "[session]\nsecret = \"0123456789abcdef\"\n\n[work]\ndaily_hours = 7.5\n")
+
+	cfg, _, err := load([]string{"--config", file}, lookupIn(nil), &bytes.Buffer{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Session.Secret != "0123456789abcdef" || cfg.Work.DailyHours != 7.5 {
+		t.Errorf("got %+v %+v", cfg.Session, cfg.Work)
+	}
+}
+
+func TestSecretsFromFiles(t *testing.T) {
+	password := writeFile(t, "password", "from-file\n")
+	kioskKey := writeFile(t, "kiosk", "kiosk-from-file")
+	file := writeFile(t, "config.yaml", "kiosk:\n  key_file: "+kioskKey+"\n")
+	env := map[string]string{"DB_PASS_FILE": password}
+
+	cfg, _, err := load([]string{"--config", file}, lookupIn(env), &bytes.Buffer{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Database.Password != "from-file" {
+		t.Errorf("database.password = %q, want the file content without newline", cfg.Database.Password)
+	}
+	if cfg.Kiosk.Key != "kiosk-from-file" {
+		t.Errorf("kiosk.key = %q", cfg.Kiosk.Key)
+	}
+}
+
+func TestDeprecatedEnvName(t *testing.T) {
+	var output bytes.Buffer
+	cfg, _, err := load(nil, lookupIn(map[string]string{"session_secret": "old-name-secret!"}), &output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Session.Secret != "old-name-secret!" || !strings.Contains(output.String(), "use SESSION_SECRET") {
+		t.Errorf("secret %q, output %q", cfg.Session.Secret, output.String())
+	}
+}
+
+func TestLoadReportsEveryProblem(t *testing.T) {
+	file := writeFile(t, "config.yaml", "server:\n  prot: 80\n")
+	env := map[string]string{"DB_PORT": "abc", "SERVER_IDLE_TIMEOUT": "forever"}
+
+	_, _, err := load([]string{"--config", file, "--daily-work-hours", "eight"}, lookupIn(env), &bytes.Buffer{})
+	if err == nil {
+		t.Fatal("want an error")
+	}
+	for _, want := range []string{"unknown key server.prot", `DB_PORT: "abc" is not a whole number`, `--daily-work-hours: "eight" is not a number`, `SERVER_IDLE_TIMEOUT: "forever" is not a duration`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q doesn't contain %q", err, want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cfg, _, err := load(nil, lookupIn(map[string]string{
+		"DB_USER":         "app",
+		"DB_NAME":         "attendances",
+		"SESSION_SECRET":  "short",
+		"WORK_DAYS":       "mon,funday",
+		"TRUSTED_PROXIES": "10.0.0.0/8,proxy",
+		"LOG_LEVEL":       "verbose",
+	}), &bytes.Buffer{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = cfg.Validate()
+	if err == nil {
+		t.Fatal("want an error")
+	}
+	for _, want := range []string{"session.secret", `work.days "funday"`, `server.trusted_proxies "proxy"`, `log.level "verbose"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q doesn't contain %q", err, want)
+		}
+	}
+	if strings.Contains(err.Error(), "database") {
+		t.Errorf("error %q reports a valid database", err)
+	}
+}
+
+func TestPrintRedactsSecrets(t *testing.T) {
+	cfg, _, err := load(nil, lookupIn(map[string]string{"DB_PASS": "hunter2", "DB_USER": "app"}), &bytes.Buffer{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var output bytes.Buffer
+	if err := Print(&output, cfg); err != nil {
+		t.Fatal(err)
+	}
+	printed := output.String()
+	if strings.Contains(printed, "hunter2") || !strings.Contains(printed, "password: '[redacted]'") {
+		t.Errorf("password not redacted:\n%s", printed)
+	}
+	if !strings.Contains(printed, "user: app") || !strings.Contains(printed, "secret: \"\"") {
+		t.Errorf("plain values or empty secrets missing:\n%s", printed)
+	}
+}
+
+func TestDSN(t *testing.T) {
+	tests := []struct {
+		cfg  DatabaseConfig
+		want string
+	}{
+		{DatabaseConfig{Driver: DriverMySQL, Host: "db", User: "app", Password: "p@ss/word", Name: "attendances"},
+			"app:p@ss/word@tcp(db:3306)/attendances?clientFoundRows=true&loc=Local&parseTime=true&charset=utf8"},
+		{DatabaseConfig{Driver: DriverPostgres, Host: "db", Port: 6543, User: "app", Password: "p@ss/word", Name: "attendances", SSLMode: "disable"},
+			"postgres://app:p%40ss%2Fword@db:6543/attendances?sslmode=disable"},
+		{DatabaseConfig{Driver: DriverPostgres, Host: "::1", User: "app", Name: "attendances"},
+			"postgres://app:@[::1]:5432/attendances"},
+		{DatabaseConfig{Driver: DriverSQLite, Name: "data/attendances.db"},
+			"data/attendances.db?_busy_timeout=5000&_foreign_keys=on"},
+		{DatabaseConfig{Driver: DriverSQLite, Name: "file:attendances.db?mode=rwc"},
+			"file:attendances.db?mode=rwc&_busy_timeout=5000&_foreign_keys=on"},
+	}
+	for _, test := range tests {
+		if got := test.cfg.DSN(); got != test.want {
+			t.Errorf("%s DSN = %s, want %s", test.cfg.Driver, got, test.want)
+		}
+	}
+}
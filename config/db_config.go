@@ -1,41 +1,47 @@
 package config
 
 import (
-	"armiariyan/attendances-system/entity"
-	"fmt"
-	"os"
+	"armiariyan/attendances-system/logger"
+	"context"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
+	mysqldriver "github.com/go-sql-driver/mysql"
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
 
-//SetupDatabaseConnection is creating a new connection to our database
-func SetupDatabaseConnection() *gorm.DB {
-
-	/* For env at localhost */
-	// errEnv := godotenv.Load()
-	// if errEnv != nil {
-	// 	panic("Failed to load env file")
-	// }
-
-	dbUser := os.Getenv("DB_USER")
-	dbPass := os.Getenv("DB_PASS")
-	dbHost := os.Getenv("DB_HOST")
-	dbName := os.Getenv("DB_NAME")
-	// dbPort, _ := strconv.Atoi(os.Getenv("PORT"))
-
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:3306)/%s?charset=utf8&parseTime=True&loc=Local", dbUser, dbPass, dbHost, dbName)
+// Drivers database.driver may name
+const (
+	DriverMySQL    = "mysql"
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
 
-	DB, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
+//SetupDatabaseConnection is creating a new connection to our database
+func SetupDatabaseConnection(cfg DatabaseConfig) *gorm.DB {
+	DB, err := gorm.Open(cfg.Dialector(), &gorm.Config{Logger: logger.Gorm()})
 	if err != nil {
-		panic("Failed to create a connection to database")
+		panic("Failed to create a connection to database: " + err.Error())
 	}
 
-	DB.AutoMigrate(&entity.Attendance{}, &entity.Activity{}, &entity.User{})
-
 	return DB
 }
 
+// PingDatabase checks the database answers before ctx ends
+func PingDatabase(ctx context.Context, db *gorm.DB) error {
+	dbSQL, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return dbSQL.PingContext(ctx)
+}
+
 func CloseDatabaseConnection(db *gorm.DB) {
 	dbSQL, err := db.DB()
 	if err != nil {
@@ -43,3 +49,65 @@ func CloseDatabaseConnection(db *gorm.DB) {
 	}
 	dbSQL.Close()
 }
+
+// Dialector opens the database of the configured driver
+func (cfg DatabaseConfig) Dialector() gorm.Dialector {
+	switch cfg.Driver {
+	case DriverPostgres:
+		return postgres.Open(cfg.DSN())
+	case DriverSQLite:
+		return sqlite.Open(cfg.DSN())
+	default:
+		return mysql.Open(cfg.DSN())
+	}
+}
+
+// DSN is the data source name of the configured driver, every part is escaped
+// so a password may hold any character
+func (cfg DatabaseConfig) DSN() string {
+	switch cfg.Driver {
+	case DriverPostgres:
+		dsn := url.URL{
+			Scheme: "postgres",
+			User:   url.UserPassword(cfg.User, cfg.Password),
+			Host:   net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.port())),
+			Path:   "/" + cfg.Name,
+		}
+		if cfg.SSLMode != "" {
+			dsn.RawQuery = url.Values{"sslmode": {cfg.SSLMode}}.Encode()
+		}
+		return dsn.String()
+	case DriverSQLite:
+		// Waits for the lock instead of failing a concurrent write, and enforces
+		// foreign keys like the other drivers do
+		separator := "?"
+		if strings.Contains(cfg.Name, "?") {
+			separator = "&"
+		}
+		return cfg.Name + separator + "_busy_timeout=5000&_foreign_keys=on"
+	default:
+		dsn := mysqldriver.NewConfig()
+		dsn.User = cfg.User
+		dsn.Passwd = cfg.Password
+		dsn.Net = "tcp"
+		dsn.Addr = net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.port()))
+		dsn.DBName = cfg.Name
+		dsn.ParseTime = true
+		// Rows affected counts the matched rows like the other drivers, not only the changed ones
+		dsn.ClientFoundRows = true
+		dsn.Loc = time.Local
+		dsn.Params = map[string]string{"charset": "utf8"}
+		return dsn.FormatDSN()
+	}
+}
+
+func (cfg DatabaseConfig) port() int {
+	switch {
+	case cfg.Port != 0:
+		return cfg.Port
+	case cfg.Driver == DriverPostgres:
+		return 5432
+	default:
+		return 3306
+	}
+}
@@ -0,0 +1,259 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// field is a setting of Config with the tags naming it in every layer
+type field struct {
+	path   string
+	env    []string
+	flag   string
+	def    string
+	usage  string
+	secret bool
+	value  reflect.Value
+}
+
+// Load builds the config from its defaults, the file named by --config or CONFIG_FILE,
+// the env and the flags of args. It returns the arguments left after the flags, which
+// name the command to run. The config isn't validated so it can still be printed
+func Load(args []string) (Config, []string, error) {
+	return load(args, os.LookupEnv, os.Stderr)
+}
+
+func load(args []string, lookupEnv func(string) (string, bool), output io.Writer) (Config, []string, error) {
+	var cfg Config
+	fields := collectFields(reflect.ValueOf(&cfg).Elem(), "")
+
+	var problems []string
+	apply := func(field field, source, raw string) {
+		if err := field.set(raw); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", source, err))
+		}
+	}
+	setFromFile := func(field field, source, path string) {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", source, err))
+			return
+		}
+		apply(field, source, strings.TrimRight(string(content), "\r\n"))
+	}
+
+	// Defaults
+	for _, field := range fields {
+		if field.def != "" {
+			apply(field, "default of "+field.path, field.def)
+		}
+	}
+
+	// Flags are parsed first to find the config file, they are applied last
+	flags := flag.NewFlagSet("attendances-system", flag.ContinueOnError)
+	flags.SetOutput(output)
+	configFile := flags.String("config", "", "YAML or TOML config file, default to CONFIG_FILE")
+	flagValues := map[string]*string{}
+	for _, field := range fields {
+		flagValues[field.flag] = flags.String(field.flag, "", field.usage)
+		if field.secret {
+			flagValues[field.flag+"-file"] = flags.String(field.flag+"-file", "", "file to read --"+field.flag+" from")
+		}
+	}
+	if err := flags.Parse(args); err != nil {
+		return cfg, nil, err
+	}
+
+	// Config file
+	if *configFile == "" {
+		*configFile, _ = lookupEnv("CONFIG_FILE")
+	}
+	if *configFile != "" {
+		values, err := readConfigFile(*configFile)
+		if err != nil {
+			return cfg, nil, err
+		}
+		for _, field := range fields {
+			if raw, ok := values[field.path]; ok {
+				apply(field, *configFile+" "+field.path, raw)
+				delete(values, field.path)
+			}
+			if raw, ok := values[field.path+"_file"]; ok && field.secret {
+				setFromFile(field, *configFile+" "+field.path+"_file", raw)
+				delete(values, field.path+"_file")
+			}
+		}
+		for _, key := range sortedKeys(values) {
+			problems = append(problems, fmt.Sprintf("%s: unknown key %s", *configFile, key))
+		}
+	}
+
+	// Env
+	for _, field := range fields {
+		for _, name := range field.env {
+			if raw, ok := lookupEnv(name); ok {
+				if name != field.env[0] {
+					fmt.Fprintf(output, "%s is deprecated, use %s\n", name, field.env[0])
+				}
+				apply(field, name, raw)
+				break
+			}
+			if path, ok := lookupEnv(name + "_FILE"); ok && field.secret {
+				setFromFile(field, name+"_FILE", path)
+				break
+			}
+		}
+	}
+
+	// Flags
+	flagFields := map[string]field{}
+	for _, field := range fields {
+		flagFields[field.flag] = field
+	}
+	flags.Visit(func(given *flag.Flag) {
+		if field, ok := flagFields[given.Name]; ok {
+			apply(field, "--"+given.Name, *flagValues[given.Name])
+		} else if field, ok := flagFields[strings.TrimSuffix(given.Name, "-file")]; ok {
+			setFromFile(field, "--"+given.Name, *flagValues[given.Name])
+		}
+	})
+
+	if len(problems) > 0 {
+		return cfg, nil, errors.New("invalid config:\n  " + strings.Join(problems, "\n  "))
+	}
+	return cfg, flags.Args(), nil
+}
+
+// collectFields walks the sections of value, a field is named by the keys of its section and itself
+func collectFields(value reflect.Value, prefix string) []field {
+	var fields []field
+	for i := 0; i < value.NumField(); i++ {
+		structField := value.Type().Field(i)
+		path := prefix + structField.Tag.Get("key")
+		if structField.Type.Kind() == reflect.Struct {
+			fields = append(fields, collectFields(value.Field(i), path+".")...)
+			continue
+		}
+		fields = append(fields, field{
+			path:   path,
+			env:    strings.Split(structField.Tag.Get("env"), ","),
+			flag:   structField.Tag.Get("flag"),
+			def:    structField.Tag.Get("default"),
+			usage:  structField.Tag.Get("usage"),
+			secret: structField.Tag.Get("secret") == "true",
+			value:  value.Field(i),
+		})
+	}
+	return fields
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func (field field) set(raw string) error {
+	if field.value.Type() == durationType {
+		duration, err := time.ParseDuration(strings.TrimSpace(raw))
+		if err != nil {
+			return fmt.Errorf("%q is not a duration like 30s or 1m", raw)
+		}
+		field.value.SetInt(int64(duration))
+		return nil
+	}
+
+	switch field.value.Kind() {
+	case reflect.String:
+		field.value.SetString(raw)
+	case reflect.Int:
+		number, err := strconv.Atoi(strings.TrimSpace(raw))
+		if err != nil {
+			return fmt.Errorf("%q is not a whole number", raw)
+		}
+		field.value.SetInt(int64(number))
+	case reflect.Float64:
+		number, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", raw)
+		}
+		field.value.SetFloat(number)
+	case reflect.Bool:
+		value, err := strconv.ParseBool(strings.TrimSpace(raw))
+		if err != nil {
+			return fmt.Errorf("%q is not true or false", raw)
+		}
+		field.value.SetBool(value)
+	case reflect.Slice:
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		field.value.Set(reflect.ValueOf(items))
+	}
+	return nil
+}
+
+// readConfigFile flattens the sections of a YAML or TOML file into "section.key" values,
+// lists are joined with commas like in the env
+func readConfigFile(path string) (map[string]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	document := map[string]interface{}{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(content, &document)
+	case ".toml":
+		err = toml.Unmarshal(content, &document)
+	default:
+		return nil, fmt.Errorf("config file %s must end with .yaml, .yml or .toml", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("config file %s: %w", path, err)
+	}
+
+	values := map[string]string{}
+	flatten(document, "", values)
+	return values, nil
+}
+
+func flatten(document map[string]interface{}, prefix string, values map[string]string) {
+	for key, value := range document {
+		switch value := value.(type) {
+		case map[string]interface{}:
+			flatten(value, prefix+key+".", values)
+		case []interface{}:
+			items := make([]string, len(value))
+			for i, item := range value {
+				items[i] = fmt.Sprint(item)
+			}
+			values[prefix+key] = strings.Join(items, ",")
+		case nil:
+			values[prefix+key] = ""
+		default:
+			values[prefix+key] = fmt.Sprint(value)
+		}
+	}
+}
+
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const redacted = "[redacted]"
+
+// Print writes the config as a YAML config file with secrets redacted, an empty
+// secret stays empty to tell it isn't set
+func Print(w io.Writer, cfg Config) error {
+	root := &yaml.Node{Kind: yaml.MappingNode}
+	sections := map[string]*yaml.Node{}
+	for _, field := range collectFields(reflect.ValueOf(&cfg).Elem(), "") {
+		section, key := "", field.path
+		if dot := strings.LastIndex(field.path, "."); dot >= 0 {
+			section, key = field.path[:dot], field.path[dot+1:]
+		}
+		if sections[section] == nil {
+			sections[section] = &yaml.Node{Kind: yaml.MappingNode}
+			root.Content = append(root.Content, scalar(section), sections[section])
+		}
+
+		value := field.node()
+		if field.secret && field.value.String() != "" {
+			value = scalar(redacted)
+		}
+		sections[section].Content = append(sections[section].Content, scalar(key), value)
+	}
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(root); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+func (field field) node() *yaml.Node {
+	if field.value.Type() == durationType {
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: time.Duration(field.value.Int()).String()}
+	}
+
+	// Numbers and booleans are left untagged so 8 isn't printed as !!float 8
+	switch field.value.Kind() {
+	case reflect.Int:
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: strconv.FormatInt(field.value.Int(), 10)}
+	case reflect.Float64:
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: strconv.FormatFloat(field.value.Float(), 'f', -1, 64)}
+	case reflect.Bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: strconv.FormatBool(field.value.Bool())}
+	case reflect.Slice:
+		list := &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+		for i := 0; i < field.value.Len(); i++ {
+			list.Content = append(list.Content, scalar(field.value.Index(i).String()))
+		}
+		return list
+	default:
+		return scalar(field.value.String())
+	}
+}
+
+func scalar(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
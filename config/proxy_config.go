@@ -0,0 +1,15 @@
+package config
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// SetupTrustedProxies tells gin which proxies may set X-Forwarded-For,
+// without it any client could spoof an office ip
+func SetupTrustedProxies(r *gin.Engine, proxies []string) {
+	// nil means no proxy is trusted and ClientIP falls back to the remote address
+	err := r.SetTrustedProxies(proxies)
+	if err != nil {
+		panic("Failed to set trusted proxies: " + err.Error())
+	}
+}
@@ -1,18 +1,38 @@
 package config
 
 import (
-	"os"
+	"armiariyan/attendances-system/logger"
+	"armiariyan/attendances-system/metrics"
+	"context"
 
 	"github.com/gin-contrib/sessions"
 	gormsessions "github.com/gin-contrib/sessions/gorm"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
-func InitWithSession() (r *gin.Engine) {
-	r = gin.Default()
+// sessionTable is the table the gorm store keeps the sessions in
+const sessionTable = "sessions"
+
+// InitWithSession keeps the sessions in db, signed with the session secret
+func InitWithSession(cfg Config, db *gorm.DB) *gin.Engine {
+	return InitWithStore(cfg, gormsessions.NewStore(db, true, []byte(cfg.Session.Secret)))
+}
+
+// PingSessionStore checks the sessions kept in db can be read before ctx ends
+func PingSessionStore(ctx context.Context, db *gorm.DB) error {
+	var ids []string
+	return db.WithContext(ctx).Table(sessionTable).Limit(1).Pluck("id", &ids).Error
+}
+
+// InitWithStore keeps the sessions in store. Requests are logged and counted once
+// answered, after the session middleware ran so the log line knows the user, and
+// after a panic was recovered so it counts as a 500
+func InitWithStore(cfg Config, store sessions.Store) (r *gin.Engine) {
+	r = gin.New()
+	SetupTrustedProxies(r, cfg.Server.TrustedProxies)
+	r.Use(logger.Middleware(), metrics.Middleware(), logger.Recovery())
 
-	ss := os.Getenv("session_secret")
-	store := gormsessions.NewStore(SetupDatabaseConnection(), true, []byte(ss))
 	r.Use(sessions.Sessions("session_id", store)) // set session name
 
 	return
@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"armiariyan/attendances-system/helper"
+	"armiariyan/attendances-system/service"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AbsenceController interface {
+	GetAbsenceReport(context *gin.Context)
+	DetectAbsences(context *gin.Context)
+}
+
+type absenceController struct {
+	absenceService    service.AbsenceService
+	departmentService service.DepartmentService
+	userService       service.UserService
+}
+
+func NewAbsenceController(absence service.AbsenceService, department service.DepartmentService, user service.UserService) AbsenceController {
+	return &absenceController{
+		absenceService:    absence,
+		departmentService: department,
+		userService:       user,
+	}
+}
+
+// RegisterAbsenceRoutes registers the absence report routes
+func RegisterAbsenceRoutes(r *gin.Engine, c AbsenceController) {
+	r.GET("api/absences", c.GetAbsenceReport)
+	r.POST("api/absences/detect", c.DetectAbsences)
+}
+
+func (c *absenceController) GetAbsenceReport(context *gin.Context) {
+	user_ids, department_id, ok := authorizeTeam(context, c.userService, c.departmentService)
+	if !ok {
+		return
+	}
+
+	// Take the date range from querry, the days are those of its time zone
+	timeRange, ok := bindClosedRange(context)
+	if !ok {
+		return
+	}
+	_, endDate := timeRange.UnixMilli()
+	startDay := timeRange.From.Format("2006-01-02")
+	endDay := time.UnixMilli(endDate).In(timeRange.From.Location()).Format("2006-01-02")
+
+	report, err := c.absenceService.GetAbsenceReport(context, user_ids, department_id, startDay, endDay)
+	if err != nil {
+		abortWithError(context, err, http.StatusInternalServerError, helper.CodeInternal)
+		return
+	}
+
+	// Build response if success
+	res := helper.BuildResponse(true, "Successfully get absence report!", report)
+	context.JSON(http.StatusOK, res)
+}
+
+func (c *absenceController) DetectAbsences(context *gin.Context) {
+	if !authorizeAdmin(context, c.userService) {
+		return
+	}
+
+	// Take date from query, default to yesterday like the daily job
+	date, errDate := time.ParseInLocation("2006-01-02", context.DefaultQuery("date", time.Now().AddDate(0, 0, -1).Format("2006-01-02")), time.Local)
+	if errDate != nil {
+		response := helper.BuildErrorResponse(helper.CodeInvalidParameter, "Failed to process request", "date must be formatted as 2006-01-02", helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// Only finished days can be checked
+	if date.Format("2006-01-02") >= time.Now().Format("2006-01-02") {
+		response := helper.BuildErrorResponse(helper.CodeInvalidParameter, "Failed to process request", "date must be before today", helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	absences, err := c.absenceService.DetectAbsences(context, date)
+	if err != nil {
+		abortWithError(context, err, http.StatusInternalServerError, helper.CodeInternal)
+		return
+	}
+
+	// Build response if success
+	res := helper.BuildResponse(true, "Successfully detect absences!", absences)
+	context.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"armiariyan/attendances-system/entity"
+	"armiariyan/attendances-system/helper"
+	"armiariyan/attendances-system/service"
+	"errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+var errActivityNotFound = errors.New("Activity not found")
+
+// requireCheckIn returns errNotCheckedIn unless the user checked in today
+func (c *activityController) requireCheckIn(context *gin.Context, user_id int) error {
+	history, err := c.attendanceService.GetAttendancesHistory(context, user_id)
+	if err != nil {
+		return err
+	}
+	if _, checkedIn := helper.TodayCheckIn(history); !checkedIn {
+		return errNotCheckedIn
+	}
+	return nil
+}
+
+func (c *activityController) createActivity(context *gin.Context, user_id int, description string) (entity.Activity, error) {
+	return c.activityService.CreateActivity(context, entity.Activity{
+		Id:          helper.GenerateIdActivity(),
+		UserId:      user_id,
+		Description: description,
+		DateCreated: time.Now().UnixMilli(),
+		TimeCreated: time.Now().UnixMilli(),
+	})
+}
+
+// findActivity returns errActivityNotFound for activities of other users too
+func (c *activityController) findActivity(context *gin.Context, user_id int, act_id string) (entity.Activity, error) {
+	activity, err := c.activityService.GetActivityById(context, act_id)
+	if errors.Is(err, service.ErrNotFound) || (err == nil && activity.UserId != user_id) {
+		return entity.Activity{}, errActivityNotFound
+	}
+	return activity, err
+}
+
+func (c *activityController) updateActivity(context *gin.Context, activity entity.Activity, description string) (entity.Activity, error) {
+	return c.activityService.UpdateActivity(context, entity.Activity{
+		Id:          activity.Id,
+		UserId:      activity.UserId,
+		Description: description,
+		DateCreated: activity.DateCreated,
+		TimeCreated: activity.TimeCreated,
+	})
+}
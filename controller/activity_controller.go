@@ -0,0 +1,292 @@
+package controller
+
+import (
+	"armiariyan/attendances-system/dto"
+	"armiariyan/attendances-system/entity"
+	"armiariyan/attendances-system/helper"
+	"armiariyan/attendances-system/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+)
+
+type ActivityController interface {
+	CreateActivity(context *gin.Context)
+	UpdateActivity(context *gin.Context)
+	DeleteActivity(context *gin.Context)
+	GetActivityHistoryByDate(context *gin.Context)
+	GetMyActivities(context *gin.Context)
+	CreateMyActivity(context *gin.Context)
+	GetMyActivity(context *gin.Context)
+	UpdateMyActivity(context *gin.Context)
+	DeleteMyActivity(context *gin.Context)
+	GetUserActivities(context *gin.Context)
+}
+
+type activityController struct {
+	activityService   service.ActivityService
+	attendanceService service.AttendanceService
+	userService       service.UserService
+	departmentService service.DepartmentService
+}
+
+func NewActivityController(activity service.ActivityService, attendance service.AttendanceService, user service.UserService, department service.DepartmentService) ActivityController {
+	return &activityController{
+		activityService:   activity,
+		attendanceService: attendance,
+		userService:       user,
+		departmentService: department,
+	}
+}
+
+// RegisterActivityRoutes registers the activity routes of v1 and v2
+func RegisterActivityRoutes(r *gin.Engine, c ActivityController) {
+	v1Routes := r.Group("api/")
+	{
+		v1Routes.POST("/activity/:id", deprecated("/api/v2/me/activities"), c.CreateActivity)
+		v1Routes.PUT("/activity/:id/:id_activity", deprecated("/api/v2/me/activities"), c.UpdateActivity)
+		v1Routes.DELETE("/activity/:id/:id_activity", deprecated("/api/v2/me/activities"), c.DeleteActivity)
+		v1Routes.GET("/activity/:id", deprecated("/api/v2/me/activities"), c.GetActivityHistoryByDate)
+	}
+
+	v2Routes := r.Group("api/v2")
+	{
+		v2Routes.GET("/me/activities", c.GetMyActivities)
+		v2Routes.POST("/me/activities", c.CreateMyActivity)
+		v2Routes.GET("/me/activities/:id", c.GetMyActivity)
+		v2Routes.PUT("/me/activities/:id", c.UpdateMyActivity)
+		v2Routes.DELETE("/me/activities/:id", c.DeleteMyActivity)
+		v2Routes.GET("/users/:id/activities", c.GetUserActivities)
+	}
+}
+
+func (c *activityController) CreateActivity(context *gin.Context) {
+
+	// Take id from parameter and convert to int
+	user_id, errConv := strconv.Atoi(context.Param("id"))
+	if errConv != nil {
+		response := helper.BuildErrorResponse(helper.CodeInvalidParameter, "Failed to process request", errConv.Error(), helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// Check if user exist and logged in using session
+	session := sessions.Default(context)
+	if !helper.IsLogin(session.Get("loggedIn")) {
+		response := helper.BuildErrorResponse(helper.CodeAuthRequired, "Failed to process request", "Please login first!", helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusUnauthorized, response)
+		return
+	}
+
+	// Check if user authorized to access data
+	if !helper.IsAuthorize(session.Get("user_id"), user_id) {
+		response := helper.BuildErrorResponse(helper.CodeForbidden, "Failed to process request", "Unauthorized!", helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusForbidden, response)
+		return
+	}
+
+	// Cek if user already check in today
+	if err := c.requireCheckIn(context, user_id); err != nil {
+		//Build response error because user not check in today
+		abortWithError(context, err, http.StatusForbidden, helper.CodeNotCheckedIn)
+		return
+	}
+
+	var createActivityData entity.Activity
+	// Fill the createActivityData
+	errDTO := context.ShouldBind(&createActivityData)
+	if errDTO != nil {
+		response := helper.BuildValidationErrorResponse(errDTO, helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// Create activity
+	activity, err := c.createActivity(context, user_id, createActivityData.Description)
+	if err != nil {
+		abortWithError(context, err, http.StatusInternalServerError, helper.CodeInternal)
+		return
+	}
+	result := helper.CreateActivityResponse(activity)
+
+	//Build response if success
+	response := helper.BuildResponse(true, "Successfully Created Activity!", result)
+	context.JSON(http.StatusCreated, response)
+}
+
+func (c *activityController) UpdateActivity(context *gin.Context) {
+
+	// Take id from parameter and convert to int
+	user_id, errConv := strconv.Atoi(context.Param("id"))
+	if errConv != nil {
+		response := helper.BuildErrorResponse(helper.CodeInvalidParameter, "Failed to process request", errConv.Error(), helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// Check if user exist and logged in using session
+	session := sessions.Default(context)
+	if !helper.IsLogin(session.Get("loggedIn")) {
+		response := helper.BuildErrorResponse(helper.CodeAuthRequired, "Failed to process request", "Please login first!", helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusUnauthorized, response)
+		return
+	}
+
+	// Check if user authorized to access data
+	if !helper.IsAuthorize(session.Get("user_id"), user_id) {
+		response := helper.BuildErrorResponse(helper.CodeForbidden, "Failed to process request", "Unauthorized!", helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusForbidden, response)
+		return
+	}
+
+	// Cek if user already check in today
+	if err := c.requireCheckIn(context, user_id); err != nil {
+		//Build response error because user not check in today
+		abortWithError(context, err, http.StatusForbidden, helper.CodeNotCheckedIn)
+		return
+	}
+
+	// Get activity data, activities of other users are not found either
+	actData, errFind := c.findActivity(context, user_id, context.Param("id_activity"))
+	if errFind != nil {
+		//Build response error because activity data empty
+		abortWithError(context, errFind, http.StatusNotFound, helper.CodeActivityNotFound)
+		return
+	}
+
+	var updateActivityData entity.Activity
+	// Fill the updateActivityData
+	errDTO := context.ShouldBind(&updateActivityData)
+	if errDTO != nil {
+		response := helper.BuildValidationErrorResponse(errDTO, helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// Update activity, it may have been deleted since it was found
+	activity, err := c.updateActivity(context, actData, updateActivityData.Description)
+	if err != nil {
+		abortWithError(context, err, http.StatusInternalServerError, helper.CodeActivityNotFound)
+		return
+	}
+
+	// Create activity response
+	result := helper.CreateActivityResponse(activity)
+
+	//Build response if success
+	response := helper.BuildResponse(true, "Successfully Update Activity!", result)
+	context.JSON(http.StatusCreated, response)
+}
+
+func (c *activityController) DeleteActivity(context *gin.Context) {
+	// Take id from parameter and convert to int
+	user_id, errConv := strconv.Atoi(context.Param("id"))
+	if errConv != nil {
+		response := helper.BuildErrorResponse(helper.CodeInvalidParameter, "Failed to process request", errConv.Error(), helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// Check if user exist and logged in using session
+	session := sessions.Default(context)
+	if !helper.IsLogin(session.Get("loggedIn")) {
+		response := helper.BuildErrorResponse(helper.CodeAuthRequired, "Failed to process request", "Please login first!", helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusUnauthorized, response)
+		return
+	}
+
+	// Check if user authorized to access data
+	if !helper.IsAuthorize(session.Get("user_id"), user_id) {
+		response := helper.BuildErrorResponse(helper.CodeForbidden, "Failed to process request", "Unauthorized!", helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusForbidden, response)
+		return
+	}
+
+	// Cek if user already check in today
+	if err := c.requireCheckIn(context, user_id); err != nil {
+		//Build response error because user not check in today
+		abortWithError(context, err, http.StatusForbidden, helper.CodeNotCheckedIn)
+		return
+	}
+
+	// Get activity data, activities of other users are not found either
+	actData, errFind := c.findActivity(context, user_id, context.Param("id_activity"))
+	if errFind != nil {
+		//Build response error because activity data empty
+		abortWithError(context, errFind, http.StatusNotFound, helper.CodeActivityNotFound)
+		return
+	}
+
+	// Delete, it may have been deleted since it was found
+	if err := c.activityService.DeleteActivity(context, actData); err != nil {
+		abortWithError(context, err, http.StatusInternalServerError, helper.CodeActivityNotFound)
+		return
+	}
+
+	// Build response if success
+	res := helper.BuildResponse(true, "Activity deleted!", helper.EmptyObj{})
+	context.JSON(http.StatusOK, res)
+}
+
+func (c *activityController) GetActivityHistoryByDate(context *gin.Context) {
+	// Take id from parameter and convert to int
+	user_id, errConv := strconv.Atoi(context.Param("id"))
+	if errConv != nil {
+		response := helper.BuildErrorResponse(helper.CodeInvalidParameter, "Failed to process request", errConv.Error(), helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// Check if user exist and logged in using session
+	session := sessions.Default(context)
+	if !helper.IsLogin(session.Get("loggedIn")) {
+		response := helper.BuildErrorResponse(helper.CodeAuthRequired, "Failed to process request", "Please login first!", helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusUnauthorized, response)
+		return
+	}
+
+	// Check if user authorized to access data
+	if !helper.IsAuthorize(session.Get("user_id"), user_id) {
+		response := helper.BuildErrorResponse(helper.CodeForbidden, "Failed to process request", "Unauthorized!", helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusForbidden, response)
+		return
+	}
+
+	// Take pagination, sorting and filters from querry
+	var activityQueryDTO dto.ActivityQueryDTO
+	if errDTO := context.ShouldBindQuery(&activityQueryDTO); errDTO != nil {
+		response := helper.BuildValidationErrorResponse(errDTO, helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// Keep accepting the date range names of the first version
+	if activityQueryDTO.StartDate == "" {
+		activityQueryDTO.StartDate = context.Query("startDate")
+	}
+	if activityQueryDTO.EndDate == "" {
+		activityQueryDTO.EndDate = context.Query("endDate")
+	}
+
+	// Get a page of activity history
+	activities, pagination, err := c.activityService.GetActivitiesPage(context, user_id, activityQueryDTO)
+	if err != nil {
+		abortWithError(context, err, http.StatusBadRequest, helper.CodeInvalidParameter)
+		return
+	}
+
+	// Create activity response
+	response := helper.CreateActivityResponses(activities)
+	// An empty page still carries its pagination, the client checks it for more
+	if response == nil {
+		res := helper.BuildPagedResponse("Activities in that range date is empty", []helper.ResponseActivity{}, pagination)
+		context.JSON(http.StatusOK, res)
+		return
+	}
+
+	// Build response if success
+	res := helper.BuildPagedResponse("Successfully get activity history!", response, pagination)
+	context.JSON(http.StatusOK, res)
+}
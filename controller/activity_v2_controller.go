@@ -0,0 +1,159 @@
+package controller
+
+import (
+	"armiariyan/attendances-system/dto"
+	"armiariyan/attendances-system/helper"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (c *activityController) GetMyActivities(context *gin.Context) {
+	user_id, ok := sessionUserId(context)
+	if !ok {
+		return
+	}
+	c.activitiesPage(context, user_id)
+}
+
+func (c *activityController) GetUserActivities(context *gin.Context) {
+	user_id, errConv := strconv.Atoi(context.Param("id"))
+	if errConv != nil {
+		response := helper.BuildErrorResponse(helper.CodeInvalidParameter, "Failed to process request", errConv.Error(), helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+	if !authorizeUserData(context, c.userService, c.departmentService, user_id) {
+		return
+	}
+	c.activitiesPage(context, user_id)
+}
+
+func (c *activityController) activitiesPage(context *gin.Context, user_id int) {
+	// Take pagination, sorting and filters from querry
+	var activityQueryDTO dto.ActivityQueryDTO
+	if errDTO := context.ShouldBindQuery(&activityQueryDTO); errDTO != nil {
+		response := helper.BuildValidationErrorResponse(errDTO, helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	activities, pagination, err := c.activityService.GetActivitiesPage(context, user_id, activityQueryDTO)
+	if err != nil {
+		abortWithError(context, err, http.StatusBadRequest, helper.CodeInvalidParameter)
+		return
+	}
+
+	response := helper.CreateActivityResponses(activities)
+	if response == nil {
+		response = []helper.ResponseActivity{}
+	}
+
+	// Build response if success
+	res := helper.BuildPagedResponse("Successfully get activities!", response, pagination)
+	context.JSON(http.StatusOK, res)
+}
+
+func (c *activityController) CreateMyActivity(context *gin.Context) {
+	user_id, ok := sessionUserId(context)
+	if !ok {
+		return
+	}
+
+	var activityDTO dto.ActivityDTO
+	if errDTO := context.ShouldBind(&activityDTO); errDTO != nil {
+		response := helper.BuildValidationErrorResponse(errDTO, helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// Activities are only written while checked in
+	if err := c.requireCheckIn(context, user_id); err != nil {
+		abortWithError(context, err, v2Status(err), helper.CodeNotCheckedIn)
+		return
+	}
+
+	activity, err := c.createActivity(context, user_id, activityDTO.Description)
+	if err != nil {
+		abortWithError(context, err, http.StatusInternalServerError, helper.CodeInternal)
+		return
+	}
+
+	// Build response if success
+	context.Header("Location", "/api/v2/me/activities/"+activity.Id)
+	res := helper.BuildResponse(true, "Successfully Created Activity!", helper.CreateActivityResponse(activity))
+	context.JSON(http.StatusCreated, res)
+}
+
+func (c *activityController) GetMyActivity(context *gin.Context) {
+	user_id, ok := sessionUserId(context)
+	if !ok {
+		return
+	}
+
+	activity, err := c.findActivity(context, user_id, context.Param("id"))
+	if err != nil {
+		abortWithError(context, err, v2Status(err), helper.CodeActivityNotFound)
+		return
+	}
+
+	// Build response if success
+	res := helper.BuildResponse(true, "Successfully get activity!", helper.CreateActivityResponse(activity))
+	context.JSON(http.StatusOK, res)
+}
+
+func (c *activityController) UpdateMyActivity(context *gin.Context) {
+	user_id, ok := sessionUserId(context)
+	if !ok {
+		return
+	}
+
+	var activityDTO dto.ActivityDTO
+	if errDTO := context.ShouldBind(&activityDTO); errDTO != nil {
+		response := helper.BuildValidationErrorResponse(errDTO, helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	activity, err := c.findActivity(context, user_id, context.Param("id"))
+	if err == nil {
+		err = c.requireCheckIn(context, user_id)
+	}
+	if err != nil {
+		abortWithError(context, err, v2Status(err), helper.CodeActivityNotFound)
+		return
+	}
+
+	activity, err = c.updateActivity(context, activity, activityDTO.Description)
+	if err != nil {
+		abortWithError(context, err, http.StatusInternalServerError, helper.CodeActivityNotFound)
+		return
+	}
+
+	// Build response if success
+	res := helper.BuildResponse(true, "Successfully Update Activity!", helper.CreateActivityResponse(activity))
+	context.JSON(http.StatusOK, res)
+}
+
+func (c *activityController) DeleteMyActivity(context *gin.Context) {
+	user_id, ok := sessionUserId(context)
+	if !ok {
+		return
+	}
+
+	activity, err := c.findActivity(context, user_id, context.Param("id"))
+	if err == nil {
+		err = c.requireCheckIn(context, user_id)
+	}
+	if err != nil {
+		abortWithError(context, err, v2Status(err), helper.CodeActivityNotFound)
+		return
+	}
+
+	if err := c.activityService.DeleteActivity(context, activity); err != nil {
+		abortWithError(context, err, http.StatusInternalServerError, helper.CodeActivityNotFound)
+		return
+	}
+	context.Status(http.StatusNoContent)
+}
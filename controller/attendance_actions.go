@@ -0,0 +1,109 @@
+package controller
+
+import (
+	"armiariyan/attendances-system/entity"
+	"armiariyan/attendances-system/helper"
+	"errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// The rules below are shared by the v1 and v2 handlers, which only differ in
+// how they take the user and which status they answer with
+var (
+	errOnsiteOutsideOffice = errors.New("Onsite check in must come from an office network")
+	errRemoteLimitReached  = errors.New("Remote days limit for this week has been reached")
+	errNotCheckedIn        = errors.New("You should check in first!")
+	errNotOnBreak          = errors.New("You are not on break!")
+)
+
+// checkIn punches a check in, without work mode the user works where the network says they are
+func (c *attendanceController) checkIn(context *gin.Context, user_id int, workMode string) (entity.Attendance, error) {
+	location, err := c.networkService.ResolveLocation(context, context.ClientIP())
+	if err != nil {
+		return entity.Attendance{}, err
+	}
+	if workMode == "" {
+		workMode = location
+	}
+
+	// Check if onsite check in really comes from the office
+	if workMode == entity.WorkModeOnsite && location != entity.LocationOnsite {
+		return entity.Attendance{}, errOnsiteOutsideOffice
+	}
+
+	// Check if user still has remote days left this week
+	if workMode == entity.WorkModeRemote {
+		canWorkRemote, err := c.policyService.CanWorkRemote(context, user_id, time.Now())
+		if err != nil {
+			return entity.Attendance{}, err
+		}
+		if !canWorkRemote {
+			return entity.Attendance{}, errRemoteLimitReached
+		}
+	}
+
+	return c.attendanceService.SaveAttendance(context, newAttendance(user_id, entity.LabelCheckIn, location, workMode))
+}
+
+// checkInQR punches an onsite check in, the scanned token proves the user stands in front of the kiosk
+func (c *attendanceController) checkInQR(context *gin.Context, user_id int, token string) (entity.Attendance, error) {
+	if err := c.kioskService.ValidateToken(context, token, user_id); err != nil {
+		return entity.Attendance{}, err
+	}
+	return c.attendanceService.SaveAttendance(context, newAttendance(user_id, entity.LabelCheckIn, entity.LocationOnsite, entity.WorkModeOnsite))
+}
+
+// checkOut punches a check out with the work mode of today's check in
+func (c *attendanceController) checkOut(context *gin.Context, user_id int) (entity.Attendance, error) {
+	history, err := c.attendanceService.GetAttendancesHistory(context, user_id)
+	if err != nil {
+		return entity.Attendance{}, err
+	}
+	checkInData, isCheckIn := helper.TodayCheckIn(history)
+	if !isCheckIn {
+		return entity.Attendance{}, errNotCheckedIn
+	}
+	location, err := c.networkService.ResolveLocation(context, context.ClientIP())
+	if err != nil {
+		return entity.Attendance{}, err
+	}
+	return c.attendanceService.SaveAttendance(context, newAttendance(user_id, entity.LabelCheckOut, location, checkInData.WorkMode))
+}
+
+// takeBreak starts a break of a working user or ends the break of a user on break
+func (c *attendanceController) takeBreak(context *gin.Context, user_id int, label string) (entity.Attendance, error) {
+	startDate, endDate := helper.DayRange(time.Now())
+	userAtd, err := c.attendanceService.GetAttendancesByDate(context, user_id, startDate, endDate)
+	if err != nil {
+		return entity.Attendance{}, err
+	}
+	status := helper.PresenceOf(userAtd)
+	if label == entity.LabelBreakStart && status != helper.PresenceCheckedIn {
+		return entity.Attendance{}, errNotCheckedIn
+	}
+	if label == entity.LabelBreakEnd && status != helper.PresenceOnBreak {
+		return entity.Attendance{}, errNotOnBreak
+	}
+
+	// The work mode follows the running session
+	sessions := helper.PairAttendances(userAtd)
+	location, err := c.networkService.ResolveLocation(context, context.ClientIP())
+	if err != nil {
+		return entity.Attendance{}, err
+	}
+	return c.attendanceService.SaveAttendance(context, newAttendance(user_id, label, location, sessions[len(sessions)-1].CheckIn.WorkMode))
+}
+
+func newAttendance(user_id int, label, location, workMode string) entity.Attendance {
+	return entity.Attendance{
+		Id:       helper.GenerateIdAttendance(),
+		UserId:   user_id,
+		Label:    label,
+		Location: location,
+		WorkMode: workMode,
+		Date:     time.Now().UnixMilli(),
+		Time:     time.Now().UnixMilli(),
+	}
+}
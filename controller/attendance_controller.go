@@ -0,0 +1,305 @@
+package controller
+
+import (
+	"armiariyan/attendances-system/dto"
+	"armiariyan/attendances-system/entity"
+	"armiariyan/attendances-system/helper"
+	"armiariyan/attendances-system/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+)
+
+type AttendanceController interface {
+	CheckIn(context *gin.Context)
+	CheckInQR(context *gin.Context)
+	CheckOut(context *gin.Context)
+	StartBreak(context *gin.Context)
+	EndBreak(context *gin.Context)
+	GetAttendancesHistory(context *gin.Context)
+	GetMyAttendances(context *gin.Context)
+	CreateMyAttendance(context *gin.Context)
+	GetUserAttendances(context *gin.Context)
+}
+
+type attendanceController struct {
+	attendanceService service.AttendanceService
+	userService       service.UserService
+	networkService    service.NetworkService
+	kioskService      service.KioskService
+	policyService     service.PolicyService
+	departmentService service.DepartmentService
+}
+
+func NewAttendanceController(attendance service.AttendanceService, user service.UserService, network service.NetworkService, kiosk service.KioskService, policy service.PolicyService, department service.DepartmentService) AttendanceController {
+	return &attendanceController{
+		attendanceService: attendance,
+		userService:       user,
+		networkService:    network,
+		kioskService:      kiosk,
+		policyService:     policy,
+		departmentService: department,
+	}
+}
+
+// RegisterAttendanceRoutes registers the check in, break and check out routes of v1 and v2
+func RegisterAttendanceRoutes(r *gin.Engine, c AttendanceController) {
+	v1Routes := r.Group("api/")
+	{
+		v1Routes.POST("/checkin/:id", deprecated("/api/v2/me/attendances"), c.CheckIn)
+		v1Routes.POST("/checkin/:id/qr", deprecated("/api/v2/me/attendances"), c.CheckInQR)
+		v1Routes.POST("/checkout/:id", deprecated("/api/v2/me/attendances"), c.CheckOut)
+		v1Routes.POST("/break/:id", deprecated("/api/v2/me/attendances"), c.StartBreak)
+		v1Routes.POST("/break/:id/end", deprecated("/api/v2/me/attendances"), c.EndBreak)
+		v1Routes.GET("/attendances/:id", deprecated("/api/v2/me/attendances"), c.GetAttendancesHistory)
+	}
+
+	v2Routes := r.Group("api/v2")
+	{
+		v2Routes.GET("/me/attendances", c.GetMyAttendances)
+		v2Routes.POST("/me/attendances", c.CreateMyAttendance)
+		v2Routes.GET("/users/:id/attendances", c.GetUserAttendances)
+	}
+}
+
+func (c *attendanceController) CheckIn(context *gin.Context) {
+
+	// Take id from parameter and convert to int
+	user_id, errConv := strconv.Atoi(context.Param("id"))
+	if errConv != nil {
+		response := helper.BuildErrorResponse(helper.CodeInvalidParameter, "Failed to process request", errConv.Error(), helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// Check if user exist and logged in using session
+	session := sessions.Default(context)
+	if !helper.IsLogin(session.Get("loggedIn")) {
+		response := helper.BuildErrorResponse(helper.CodeAuthRequired, "Failed to process request", "Please login first!", helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusUnauthorized, response)
+		return
+	}
+
+	// Check if user authorized to access data
+	if !helper.IsAuthorize(session.Get("user_id"), user_id) {
+		response := helper.BuildErrorResponse(helper.CodeForbidden, "Failed to process request", "Unauthorized!", helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusForbidden, response)
+		return
+	}
+
+	// // Take user id from session
+	// i, ok := session.Get("user_id").(int)
+
+	var checkInDTO dto.CheckInDTO
+	// Fill checkInDTO variable, the body is optional
+	if context.Request.ContentLength != 0 {
+		errDTO := context.ShouldBind(&checkInDTO)
+		if errDTO != nil {
+			response := helper.BuildValidationErrorResponse(errDTO, helper.EmptyObj{})
+			context.AbortWithStatusJSON(http.StatusBadRequest, response)
+			return
+		}
+	}
+
+	// Checkin
+	attendance, err := c.checkIn(context, user_id, checkInDTO.WorkMode)
+	if err != nil {
+		abortWithError(context, err, http.StatusForbidden, helper.CodeForbidden)
+		return
+	}
+	result := helper.CreateAttendanceResponse(attendance)
+
+	//Build response if success
+	response := helper.BuildResponse(true, "Successfully Check In!", result)
+	context.JSON(http.StatusOK, response)
+}
+
+func (c *attendanceController) CheckInQR(context *gin.Context) {
+
+	// Take id from parameter and convert to int
+	user_id, errConv := strconv.Atoi(context.Param("id"))
+	if errConv != nil {
+		response := helper.BuildErrorResponse(helper.CodeInvalidParameter, "Failed to process request", errConv.Error(), helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// Check if user exist and logged in using session
+	session := sessions.Default(context)
+	if !helper.IsLogin(session.Get("loggedIn")) {
+		response := helper.BuildErrorResponse(helper.CodeAuthRequired, "Failed to process request", "Please login first!", helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusUnauthorized, response)
+		return
+	}
+
+	// Check if user authorized to access data
+	if !helper.IsAuthorize(session.Get("user_id"), user_id) {
+		response := helper.BuildErrorResponse(helper.CodeForbidden, "Failed to process request", "Unauthorized!", helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusForbidden, response)
+		return
+	}
+
+	var kioskCheckInDTO dto.KioskCheckInDTO
+	// Fill kioskCheckInDTO variable
+	errDTO := context.ShouldBind(&kioskCheckInDTO)
+	if errDTO != nil {
+		response := helper.BuildValidationErrorResponse(errDTO, helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// Checkin
+	attendance, err := c.checkInQR(context, user_id, kioskCheckInDTO.Token)
+	if err != nil {
+		abortWithError(context, err, http.StatusForbidden, helper.CodeForbidden)
+		return
+	}
+	result := helper.CreateAttendanceResponse(attendance)
+
+	//Build response if success
+	response := helper.BuildResponse(true, "Successfully Check In!", result)
+	context.JSON(http.StatusOK, response)
+}
+
+func (c *attendanceController) CheckOut(context *gin.Context) {
+
+	// Take id from parameter and convert to int
+	user_id, errConv := strconv.Atoi(context.Param("id"))
+	if errConv != nil {
+		response := helper.BuildErrorResponse(helper.CodeInvalidParameter, "Failed to process request", errConv.Error(), helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// Check if user exist and logged in using session
+	session := sessions.Default(context)
+	if !helper.IsLogin(session.Get("loggedIn")) {
+		response := helper.BuildErrorResponse(helper.CodeAuthRequired, "Failed to process request", "Please login first!", helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusUnauthorized, response)
+		return
+	}
+
+	// Check if user authorized to access data
+	if !helper.IsAuthorize(session.Get("user_id"), user_id) {
+		response := helper.BuildErrorResponse(helper.CodeForbidden, "Failed to process request", "Unauthorized!", helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusForbidden, response)
+		return
+	}
+
+	// Checkout
+	attendance, err := c.checkOut(context, user_id)
+	if err != nil {
+		//Build response error because user not check in today
+		abortWithError(context, err, http.StatusForbidden, helper.CodeForbidden)
+		return
+	}
+	result := helper.CreateAttendanceResponse(attendance)
+
+	//Build response if success
+	response := helper.BuildResponse(true, "Successfully Check Out!", result)
+	context.JSON(http.StatusOK, response)
+}
+
+func (c *attendanceController) StartBreak(context *gin.Context) {
+	c.punchBreak(context, entity.LabelBreakStart)
+}
+
+func (c *attendanceController) EndBreak(context *gin.Context) {
+	c.punchBreak(context, entity.LabelBreakEnd)
+}
+
+func (c *attendanceController) punchBreak(context *gin.Context, label string) {
+
+	// Take id from parameter and convert to int
+	user_id, errConv := strconv.Atoi(context.Param("id"))
+	if errConv != nil {
+		response := helper.BuildErrorResponse(helper.CodeInvalidParameter, "Failed to process request", errConv.Error(), helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// Check if user exist and logged in using session
+	session := sessions.Default(context)
+	if !helper.IsLogin(session.Get("loggedIn")) {
+		response := helper.BuildErrorResponse(helper.CodeAuthRequired, "Failed to process request", "Please login first!", helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusUnauthorized, response)
+		return
+	}
+
+	// Check if user authorized to access data
+	if !helper.IsAuthorize(session.Get("user_id"), user_id) {
+		response := helper.BuildErrorResponse(helper.CodeForbidden, "Failed to process request", "Unauthorized!", helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusForbidden, response)
+		return
+	}
+
+	// Break
+	attendance, err := c.takeBreak(context, user_id, label)
+	if err != nil {
+		abortWithError(context, err, http.StatusForbidden, helper.CodeForbidden)
+		return
+	}
+	result := helper.CreateAttendanceResponse(attendance)
+
+	//Build response if success
+	message := "Successfully Start Break!"
+	if label == entity.LabelBreakEnd {
+		message = "Successfully End Break!"
+	}
+	response := helper.BuildResponse(true, message, result)
+	context.JSON(http.StatusOK, response)
+}
+
+func (c *attendanceController) GetAttendancesHistory(context *gin.Context) {
+
+	// Take id from parameter and convert to int
+	user_id, errConv := strconv.Atoi(context.Param("id"))
+	if errConv != nil {
+		response := helper.BuildErrorResponse(helper.CodeInvalidParameter, "Failed to process request", errConv.Error(), helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// Check if user exist and logged in using session
+	session := sessions.Default(context)
+	if !helper.IsLogin(session.Get("loggedIn")) {
+		response := helper.BuildErrorResponse(helper.CodeAuthRequired, "Failed to process request", "Please login first!", helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusUnauthorized, response)
+		return
+	}
+
+	// Check if user authorized to access data
+	if !helper.IsAuthorize(session.Get("user_id"), user_id) {
+		response := helper.BuildErrorResponse(helper.CodeForbidden, "Failed to process request", "Unauthorized!", helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusForbidden, response)
+		return
+	}
+
+	// Take pagination, sorting and filters from querry
+	var attendanceQueryDTO dto.AttendanceQueryDTO
+	if errDTO := context.ShouldBindQuery(&attendanceQueryDTO); errDTO != nil {
+		response := helper.BuildValidationErrorResponse(errDTO, helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// Get a page of attendances history
+	attendances, pagination, err := c.attendanceService.GetAttendancesPage(context, user_id, attendanceQueryDTO)
+	if err != nil {
+		abortWithError(context, err, http.StatusBadRequest, helper.CodeInvalidParameter)
+		return
+	}
+	response := helper.CreateAttendanceResponses(attendances)
+	// If attendances history empty
+	if response == nil {
+		res := helper.BuildPagedResponse("Successfully get attendance history!", "attendances history is empty", pagination)
+		context.JSON(http.StatusOK, res)
+		return
+	}
+
+	// Build response if success
+	res := helper.BuildPagedResponse("Successfully get attendance history!", response, pagination)
+	context.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"armiariyan/attendances-system/dto"
+	"armiariyan/attendances-system/entity"
+	"armiariyan/attendances-system/helper"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (c *attendanceController) GetMyAttendances(context *gin.Context) {
+	user_id, ok := sessionUserId(context)
+	if !ok {
+		return
+	}
+	c.attendancesPage(context, user_id)
+}
+
+func (c *attendanceController) GetUserAttendances(context *gin.Context) {
+	user_id, errConv := strconv.Atoi(context.Param("id"))
+	if errConv != nil {
+		response := helper.BuildErrorResponse(helper.CodeInvalidParameter, "Failed to process request", errConv.Error(), helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+	if !authorizeUserData(context, c.userService, c.departmentService, user_id) {
+		return
+	}
+	c.attendancesPage(context, user_id)
+}
+
+func (c *attendanceController) attendancesPage(context *gin.Context, user_id int) {
+	// Take pagination, sorting and filters from querry
+	var attendanceQueryDTO dto.AttendanceQueryDTO
+	if errDTO := context.ShouldBindQuery(&attendanceQueryDTO); errDTO != nil {
+		response := helper.BuildValidationErrorResponse(errDTO, helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	attendances, pagination, err := c.attendanceService.GetAttendancesPage(context, user_id, attendanceQueryDTO)
+	if err != nil {
+		abortWithError(context, err, http.StatusBadRequest, helper.CodeInvalidParameter)
+		return
+	}
+
+	response := helper.CreateAttendanceResponses(attendances)
+	if response == nil {
+		response = []helper.ResponseAttendance{}
+	}
+
+	// Build response if success
+	res := helper.BuildPagedResponse("Successfully get attendances!", response, pagination)
+	context.JSON(http.StatusOK, res)
+}
+
+// CreateMyAttendance punches the label of the body, a check in with token goes through the kiosk
+func (c *attendanceController) CreateMyAttendance(context *gin.Context) {
+	user_id, ok := sessionUserId(context)
+	if !ok {
+		return
+	}
+
+	var attendanceDTO dto.AttendanceDTO
+	if errDTO := context.ShouldBind(&attendanceDTO); errDTO != nil {
+		response := helper.BuildValidationErrorResponse(errDTO, helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+	if attendanceDTO.Label != entity.LabelCheckIn && (attendanceDTO.Token != "" || attendanceDTO.WorkMode != "") {
+		response := helper.BuildErrorResponse(helper.CodeValidationFailed, "Failed to process request", "Token and work mode are only taken on check in", helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	var attendance entity.Attendance
+	var err error
+	switch {
+	case attendanceDTO.Label == entity.LabelCheckIn && attendanceDTO.Token != "":
+		attendance, err = c.checkInQR(context, user_id, attendanceDTO.Token)
+	case attendanceDTO.Label == entity.LabelCheckIn:
+		attendance, err = c.checkIn(context, user_id, attendanceDTO.WorkMode)
+	case attendanceDTO.Label == entity.LabelCheckOut:
+		attendance, err = c.checkOut(context, user_id)
+	default:
+		attendance, err = c.takeBreak(context, user_id, attendanceDTO.Label)
+	}
+	if err != nil {
+		abortWithError(context, err, v2Status(err), helper.CodeForbidden)
+		return
+	}
+
+	// Build response if success
+	context.Header("Location", "/api/v2/me/attendances")
+	res := helper.BuildResponse(true, "Successfully saved "+attendance.Label+"!", helper.CreateAttendanceResponse(attendance))
+	context.JSON(http.StatusCreated, res)
+}
@@ -0,0 +1,212 @@
+package controller
+
+import (
+	"armiariyan/attendances-system/helper"
+	"armiariyan/attendances-system/service"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+)
+
+// authorizeAdmin aborts the request unless the session belongs to an admin
+func authorizeAdmin(context *gin.Context, userService service.UserService) bool {
+	// Check if user exist and logged in using session
+	session := sessions.Default(context)
+	if !helper.IsLogin(session.Get("loggedIn")) {
+		response := helper.BuildErrorResponse(helper.CodeAuthRequired, "Failed to process request", "Please login first!", helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusUnauthorized, response)
+		return false
+	}
+
+	// Check if user is an admin
+	session_id, _ := session.Get("user_id").(int)
+	admin, ok := isAdmin(context, userService, session_id)
+	if !ok {
+		return false
+	}
+	if !admin {
+		response := helper.BuildErrorResponse(helper.CodeForbidden, "Failed to process request", "Unauthorized!", helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusForbidden, response)
+		return false
+	}
+	return true
+}
+
+// authorizeSelfOrAdmin aborts the request unless the session belongs to user_id or to an admin
+func authorizeSelfOrAdmin(context *gin.Context, userService service.UserService, user_id int) bool {
+	// Check if user exist and logged in using session
+	session := sessions.Default(context)
+	if !helper.IsLogin(session.Get("loggedIn")) {
+		response := helper.BuildErrorResponse(helper.CodeAuthRequired, "Failed to process request", "Please login first!", helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusUnauthorized, response)
+		return false
+	}
+
+	// Check if user authorized to access data
+	if helper.IsAuthorize(session.Get("user_id"), user_id) {
+		return true
+	}
+	session_id, _ := session.Get("user_id").(int)
+	admin, ok := isAdmin(context, userService, session_id)
+	if !ok {
+		return false
+	}
+	if !admin {
+		response := helper.BuildErrorResponse(helper.CodeForbidden, "Failed to process request", "Unauthorized!", helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusForbidden, response)
+		return false
+	}
+	return true
+}
+
+// authorizeUserData aborts the request unless the session belongs to user_id, to an admin
+// or to a manager of user_id
+func authorizeUserData(context *gin.Context, userService service.UserService, departmentService service.DepartmentService, user_id int) bool {
+	if !authorizeLogin(context) {
+		return false
+	}
+
+	session := sessions.Default(context)
+	session_id, _ := session.Get("user_id").(int)
+	if session_id != user_id {
+		admin, ok := isAdmin(context, userService, session_id)
+		if !ok {
+			return false
+		}
+		report_ids, ok := reportIds(context, departmentService, session_id)
+		if !ok {
+			return false
+		}
+		if !admin && !containsId(report_ids, user_id) {
+			response := helper.BuildErrorResponse(helper.CodeForbidden, "Failed to process request", "Unauthorized!", helper.EmptyObj{})
+			context.AbortWithStatusJSON(http.StatusForbidden, response)
+			return false
+		}
+	}
+
+	// Check if user exist
+	if _, err := userService.GetUserById(context, user_id); err != nil {
+		abortWithError(context, err, http.StatusNotFound, helper.CodeUserNotFound)
+		return false
+	}
+	return true
+}
+
+// isAdmin tells whether the user is an admin, a user that doesn't exist isn't. It aborts
+// the request when the user can't be read
+func isAdmin(context *gin.Context, userService service.UserService, user_id int) (admin bool, ok bool) {
+	user, err := userService.GetUserById(context, user_id)
+	if err != nil && !errors.Is(err, service.ErrNotFound) {
+		abortWithError(context, err, http.StatusInternalServerError, helper.CodeInternal)
+		return false, false
+	}
+	return helper.IsAdmin(user), true
+}
+
+// reportIds returns the ids of the reports of the manager and aborts the request when they can't be read
+func reportIds(context *gin.Context, departmentService service.DepartmentService, manager_id int) ([]int, bool) {
+	ids, err := departmentService.GetReportIds(context, manager_id)
+	if err != nil {
+		abortWithError(context, err, http.StatusInternalServerError, helper.CodeInternal)
+		return nil, false
+	}
+	return ids, true
+}
+
+// sessionUserId returns the user of the session and aborts the request when nobody is logged in
+func sessionUserId(context *gin.Context) (int, bool) {
+	if !authorizeLogin(context) {
+		return 0, false
+	}
+	session_id, _ := sessions.Default(context).Get("user_id").(int)
+	return session_id, true
+}
+
+// authorizeLogin aborts the request unless the user is logged in
+func authorizeLogin(context *gin.Context) bool {
+	// Check if user exist and logged in using session
+	session := sessions.Default(context)
+	if !helper.IsLogin(session.Get("loggedIn")) {
+		response := helper.BuildErrorResponse(helper.CodeAuthRequired, "Failed to process request", "Please login first!", helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusUnauthorized, response)
+		return false
+	}
+	return true
+}
+
+// authorizeTeam reads the optional id_department and id_manager queries and returns the
+// users the session may see, nil user_ids means the whole company which only admins see
+func authorizeTeam(context *gin.Context, userService service.UserService, departmentService service.DepartmentService) (user_ids []int, department_id *int, ok bool) {
+	if !authorizeLogin(context) {
+		return nil, nil, false
+	}
+
+	// Take optional department from query
+	if query := context.Query("id_department"); query != "" {
+		id, errConv := strconv.Atoi(query)
+		if errConv != nil {
+			response := helper.BuildErrorResponse(helper.CodeInvalidParameter, "Failed to process request", errConv.Error(), helper.EmptyObj{})
+			context.AbortWithStatusJSON(http.StatusBadRequest, response)
+			return nil, nil, false
+		}
+		department_id = &id
+	}
+
+	// Take optional manager from query, only the team of that manager is then returned
+	session := sessions.Default(context)
+	session_id, _ := session.Get("user_id").(int)
+	manager_id := 0
+	if query := context.Query("id_manager"); query != "" {
+		id, errConv := strconv.Atoi(query)
+		if errConv != nil {
+			response := helper.BuildErrorResponse(helper.CodeInvalidParameter, "Failed to process request", errConv.Error(), helper.EmptyObj{})
+			context.AbortWithStatusJSON(http.StatusBadRequest, response)
+			return nil, nil, false
+		}
+		manager_id = id
+	}
+
+	// Admin sees the whole company, a manager only sees their own team
+	admin, ok := isAdmin(context, userService, session_id)
+	if !ok {
+		return nil, nil, false
+	}
+	if admin {
+		if manager_id != 0 {
+			if user_ids, ok = reportIds(context, departmentService, manager_id); !ok {
+				return nil, nil, false
+			}
+			user_ids = append([]int{}, user_ids...)
+		}
+	} else {
+		if user_ids, ok = reportIds(context, departmentService, session_id); !ok {
+			return nil, nil, false
+		}
+		if manager_id != 0 && manager_id != session_id {
+			if !containsId(user_ids, manager_id) {
+				response := helper.BuildErrorResponse(helper.CodeForbidden, "Failed to process request", "Unauthorized!", helper.EmptyObj{})
+				context.AbortWithStatusJSON(http.StatusForbidden, response)
+				return nil, nil, false
+			}
+			if user_ids, ok = reportIds(context, departmentService, manager_id); !ok {
+				return nil, nil, false
+			}
+		}
+		if user_ids == nil {
+			user_ids = []int{}
+		}
+	}
+	return user_ids, department_id, true
+}
+
+func containsId(ids []int, id int) bool {
+	for _, current := range ids {
+		if current == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package controller
+
+import (
+	"armiariyan/attendances-system/dto"
+	"armiariyan/attendances-system/helper"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// bindClosedRange reads the date range of a report or export from the querry like the
+// history endpoints do, both ends are required. It aborts with 400 when the range
+// can't be used
+func bindClosedRange(context *gin.Context) (helper.TimeRange, bool) {
+	var dates dto.DateRangeDTO
+	if errDTO := context.ShouldBindQuery(&dates); errDTO != nil {
+		response := helper.BuildValidationErrorResponse(errDTO, helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return helper.TimeRange{}, false
+	}
+	// startDate and endDate are the deprecated names of start_date and end_date
+	if dates.StartDate == "" {
+		dates.StartDate = context.Query("startDate")
+	}
+	if dates.EndDate == "" {
+		dates.EndDate = context.Query("endDate")
+	}
+
+	timeRange, err := helper.ParseClosedTimeRange(helper.RangeQuery{
+		Start:        dates.StartDate,
+		End:          dates.EndDate,
+		Range:        dates.Range,
+		TimeZone:     dates.TimeZone,
+		EndExclusive: dates.EndExclusive,
+	}, time.Now())
+	if err != nil {
+		response := helper.BuildErrorResponse(errorCode(err, helper.CodeInvalidDateRange), "Failed to process request", err.Error(), helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return helper.TimeRange{}, false
+	}
+	return timeRange, true
+}
@@ -0,0 +1,297 @@
+package controller
+
+import (
+	"armiariyan/attendances-system/dto"
+	"armiariyan/attendances-system/entity"
+	"armiariyan/attendances-system/helper"
+	"armiariyan/attendances-system/service"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DepartmentController interface {
+	GetDepartments(context *gin.Context)
+	GetDepartment(context *gin.Context)
+	CreateDepartment(context *gin.Context)
+	UpdateDepartment(context *gin.Context)
+	DeleteDepartment(context *gin.Context)
+	GetMembers(context *gin.Context)
+	MoveUser(context *gin.Context)
+	GetReports(context *gin.Context)
+}
+
+type departmentController struct {
+	departmentService service.DepartmentService
+	userService       service.UserService
+}
+
+func NewDepartmentController(department service.DepartmentService, user service.UserService) DepartmentController {
+	return &departmentController{
+		departmentService: department,
+		userService:       user,
+	}
+}
+
+// RegisterDepartmentRoutes registers the department, membership and reporting line routes
+func RegisterDepartmentRoutes(r *gin.Engine, c DepartmentController) {
+	departmentRoutes := r.Group("api/departments")
+	{
+		departmentRoutes.GET("", c.GetDepartments)
+		departmentRoutes.POST("", c.CreateDepartment)
+		departmentRoutes.GET("/:id_department", c.GetDepartment)
+		departmentRoutes.PUT("/:id_department", c.UpdateDepartment)
+		departmentRoutes.DELETE("/:id_department", c.DeleteDepartment)
+		departmentRoutes.GET("/:id_department/members", c.GetMembers)
+	}
+
+	r.PUT("api/users/:id/department", c.MoveUser)
+	r.GET("api/managers/:id/reports", c.GetReports)
+}
+
+func (c *departmentController) GetDepartments(context *gin.Context) {
+	if !authorizeLogin(context) {
+		return
+	}
+
+	departments, err := c.departmentService.GetDepartments(context)
+	if err != nil {
+		abortWithError(context, err, http.StatusInternalServerError, helper.CodeInternal)
+		return
+	}
+
+	// Build response if success
+	res := helper.BuildResponse(true, "Successfully get departments!", departments)
+	context.JSON(http.StatusOK, res)
+}
+
+func (c *departmentController) GetDepartment(context *gin.Context) {
+	if !authorizeLogin(context) {
+		return
+	}
+
+	department, ok := c.findDepartment(context)
+	if !ok {
+		return
+	}
+
+	// Build response if success
+	res := helper.BuildResponse(true, "Successfully get department!", department)
+	context.JSON(http.StatusOK, res)
+}
+
+func (c *departmentController) CreateDepartment(context *gin.Context) {
+	if !authorizeAdmin(context, c.userService) {
+		return
+	}
+
+	var departmentDTO dto.DepartmentDTO
+	// Fill departmentDTO variable
+	errDTO := context.ShouldBind(&departmentDTO)
+	if errDTO != nil {
+		response := helper.BuildValidationErrorResponse(errDTO, helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// Create department
+	department, err := c.departmentService.CreateDepartment(context, departmentDTO)
+	if err != nil {
+		abortWithError(context, err, http.StatusUnprocessableEntity, helper.CodeInternal)
+		return
+	}
+
+	// Build response if success
+	res := helper.BuildResponse(true, "Successfully Created Department!", department)
+	context.JSON(http.StatusCreated, res)
+}
+
+func (c *departmentController) UpdateDepartment(context *gin.Context) {
+	if !authorizeAdmin(context, c.userService) {
+		return
+	}
+
+	department, ok := c.findDepartment(context)
+	if !ok {
+		return
+	}
+
+	var departmentDTO dto.DepartmentDTO
+	// Fill departmentDTO variable
+	errDTO := context.ShouldBind(&departmentDTO)
+	if errDTO != nil {
+		response := helper.BuildValidationErrorResponse(errDTO, helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// Update department
+	department, err := c.departmentService.UpdateDepartment(context, department, departmentDTO)
+	if err != nil {
+		abortWithError(context, err, http.StatusUnprocessableEntity, helper.CodeInternal)
+		return
+	}
+
+	// Build response if success
+	res := helper.BuildResponse(true, "Successfully Update Department!", department)
+	context.JSON(http.StatusOK, res)
+}
+
+func (c *departmentController) DeleteDepartment(context *gin.Context) {
+	if !authorizeAdmin(context, c.userService) {
+		return
+	}
+
+	department, ok := c.findDepartment(context)
+	if !ok {
+		return
+	}
+
+	// Delete
+	err := c.departmentService.DeleteDepartment(context, department)
+	if err != nil {
+		abortWithError(context, err, http.StatusConflict, helper.CodeInternal)
+		return
+	}
+
+	// Build response if success
+	res := helper.BuildResponse(true, "Department deleted!", helper.EmptyObj{})
+	context.JSON(http.StatusOK, res)
+}
+
+func (c *departmentController) GetMembers(context *gin.Context) {
+	if !authorizeLogin(context) {
+		return
+	}
+
+	department, ok := c.findDepartment(context)
+	if !ok {
+		return
+	}
+
+	members, err := c.departmentService.GetMembers(context, department.Id)
+	if err != nil {
+		abortWithError(context, err, http.StatusInternalServerError, helper.CodeInternal)
+		return
+	}
+
+	// Build response if success
+	res := helper.BuildResponse(true, "Successfully get department members!", members)
+	context.JSON(http.StatusOK, res)
+}
+
+func (c *departmentController) MoveUser(context *gin.Context) {
+	if !authorizeAdmin(context, c.userService) {
+		return
+	}
+
+	// Take id from parameter and convert to int
+	user_id, errConv := strconv.Atoi(context.Param("id"))
+	if errConv != nil {
+		response := helper.BuildErrorResponse(helper.CodeInvalidParameter, "Failed to process request", errConv.Error(), helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// Check if user exist
+	if _, err := c.userService.GetUserById(context, user_id); err != nil {
+		abortWithError(context, err, http.StatusNotFound, helper.CodeUserNotFound)
+		return
+	}
+
+	var moveUserDTO dto.MoveUserDTO
+	// Fill moveUserDTO variable, empty department removes the user from their department
+	errDTO := context.ShouldBind(&moveUserDTO)
+	if errDTO != nil {
+		response := helper.BuildValidationErrorResponse(errDTO, helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// Check if department exist
+	if moveUserDTO.DepartmentId != nil {
+		if _, ok := c.findDepartmentById(context, *moveUserDTO.DepartmentId); !ok {
+			return
+		}
+	}
+
+	// Move
+	if err := c.departmentService.MoveUser(context, user_id, moveUserDTO.DepartmentId); err != nil {
+		abortWithError(context, err, http.StatusInternalServerError, helper.CodeInternal)
+		return
+	}
+
+	user, err := c.userService.GetUserById(context, user_id)
+	if err != nil {
+		abortWithError(context, err, http.StatusNotFound, helper.CodeUserNotFound)
+		return
+	}
+
+	// Build response if success
+	res := helper.BuildResponse(true, "Successfully Moved User!", user)
+	context.JSON(http.StatusOK, res)
+}
+
+func (c *departmentController) GetReports(context *gin.Context) {
+	// Take id from parameter and convert to int
+	manager_id, errConv := strconv.Atoi(context.Param("id"))
+	if errConv != nil {
+		response := helper.BuildErrorResponse(helper.CodeInvalidParameter, "Failed to process request", errConv.Error(), helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	if !authorizeSelfOrAdmin(context, c.userService, manager_id) {
+		return
+	}
+
+	// Take direct from query, default to every direct and indirect report
+	directOnly, errBool := strconv.ParseBool(context.DefaultQuery("direct", "false"))
+	if errBool != nil {
+		response := helper.BuildErrorResponse(helper.CodeInvalidParameter, "Failed to process request", errBool.Error(), helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	reports, err := c.departmentService.GetReports(context, manager_id, directOnly)
+	if err != nil {
+		abortWithError(context, err, http.StatusInternalServerError, helper.CodeInternal)
+		return
+	}
+
+	// Build response if success
+	res := helper.BuildResponse(true, "Successfully get reports!", reports)
+	context.JSON(http.StatusOK, res)
+}
+
+// findDepartment takes the department from parameter and aborts when it doesn't exist
+func (c *departmentController) findDepartment(context *gin.Context) (department entity.Department, ok bool) {
+	// Take id from parameter and convert to int
+	department_id, errConv := strconv.Atoi(context.Param("id_department"))
+	if errConv != nil {
+		response := helper.BuildErrorResponse(helper.CodeInvalidParameter, "Failed to process request", errConv.Error(), helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	return c.findDepartmentById(context, department_id)
+}
+
+// findDepartmentById aborts when the department doesn't exist or can't be read
+func (c *departmentController) findDepartmentById(context *gin.Context, department_id int) (entity.Department, bool) {
+	department, err := c.departmentService.GetDepartmentById(context, department_id)
+	if err != nil && !errors.Is(err, service.ErrNotFound) {
+		abortWithError(context, err, http.StatusInternalServerError, helper.CodeInternal)
+		return department, false
+	}
+
+	// Check if department exist
+	if helper.IsDepartmentEmpty(department) {
+		response := helper.BuildErrorResponse(helper.CodeDepartmentNotFound, "Failed to process request", "Department not found", helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusNotFound, response)
+		return department, false
+	}
+	return department, true
+}
@@ -0,0 +1,33 @@
+package controller
+
+import (
+	"armiariyan/attendances-system/docs"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DocsController interface {
+	GetOpenAPI(context *gin.Context)
+	GetDocs(context *gin.Context)
+}
+
+type docsController struct{}
+
+func NewDocsController() DocsController {
+	return &docsController{}
+}
+
+// RegisterDocsRoutes registers the OpenAPI document and its viewer
+func RegisterDocsRoutes(r *gin.Engine, c DocsController) {
+	r.GET("api/openapi.json", c.GetOpenAPI)
+	r.GET("api/docs", c.GetDocs)
+}
+
+func (c *docsController) GetOpenAPI(context *gin.Context) {
+	context.Data(http.StatusOK, "application/json; charset=utf-8", docs.OpenAPI)
+}
+
+func (c *docsController) GetDocs(context *gin.Context) {
+	context.Data(http.StatusOK, "text/html; charset=utf-8", docs.UI)
+}
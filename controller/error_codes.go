@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"armiariyan/attendances-system/helper"
+	"armiariyan/attendances-system/logger"
+	"armiariyan/attendances-system/service"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// errorCodes is the catalogue of the rule errors handlers pass on to clients
+var errorCodes = map[error]helper.ErrorCode{
+	errOnsiteOutsideOffice:           helper.CodeOnsiteOutsideOffice,
+	errRemoteLimitReached:            helper.CodeRemoteLimitReached,
+	errNotCheckedIn:                  helper.CodeNotCheckedIn,
+	errNotOnBreak:                    helper.CodeNotOnBreak,
+	errActivityNotFound:              helper.CodeActivityNotFound,
+	service.ErrKioskTokenInvalid:     helper.CodeKioskTokenInvalid,
+	service.ErrKioskTokenExpired:     helper.CodeKioskTokenExpired,
+	service.ErrKioskTokenReplayed:    helper.CodeKioskTokenReplayed,
+	service.ErrParentNotFound:        helper.CodeParentNotFound,
+	service.ErrManagerNotFound:       helper.CodeManagerNotFound,
+	service.ErrDepartmentCycle:       helper.CodeDepartmentCycle,
+	service.ErrDepartmentHasChildren: helper.CodeDepartmentHasChildren,
+	service.ErrLeaveInvalidRange:     helper.CodeInvalidDateRange,
+	service.ErrLeaveReviewed:         helper.CodeLeaveReviewed,
+	service.ErrPayrollFormat:         helper.CodeInvalidParameter,
+	helper.ErrInvalidDate:            helper.CodeInvalidDate,
+	helper.ErrInvalidDateRange:       helper.CodeInvalidDateRange,
+	helper.ErrInvalidCursor:          helper.CodeInvalidCursor,
+}
+
+// errorCode returns the code of err, or fallback when err isn't in the catalogue
+func errorCode(err error, fallback helper.ErrorCode) helper.ErrorCode {
+	for known, code := range errorCodes {
+		if errors.Is(err, known) {
+			return code
+		}
+	}
+	return fallback
+}
+
+// abortWithError answers err. A rule error of the catalogue answers status with its own
+// code and a missing row answers 404 with code. A conflicting write answers 409, an
+// unreachable database 503 and anything else 500, their cause only goes to the log
+func abortWithError(context *gin.Context, err error, status int, code helper.ErrorCode) {
+	message := err.Error()
+	switch {
+	case errors.Is(err, service.ErrUnavailable):
+		logger.For(context).WithError(err).Error("service unavailable")
+		status, code, message = http.StatusServiceUnavailable, helper.CodeUnavailable, "Service unavailable, please retry later"
+	case errors.Is(err, service.ErrNotFound):
+		status, message = http.StatusNotFound, "Not found"
+	case errors.Is(err, service.ErrConflict):
+		status, code, message = http.StatusConflict, helper.CodeConflict, "Conflicts with existing data"
+	default:
+		code = errorCode(err, "")
+		if code == "" {
+			logger.For(context).WithError(err).Error("request failed")
+			status, code, message = http.StatusInternalServerError, helper.CodeInternal, "Internal server error"
+		}
+	}
+	response := helper.BuildErrorResponse(code, "Failed to process request", message, helper.EmptyObj{})
+	context.AbortWithStatusJSON(status, response)
+}
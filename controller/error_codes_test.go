@@ -0,0 +1,137 @@
+package controller
+
+import (
+	"armiariyan/attendances-system/docs"
+	"armiariyan/attendances-system/dto"
+	"armiariyan/attendances-system/helper"
+	"armiariyan/attendances-system/service"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestValidationErrorsNameJsonFields(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	context, _ := gin.CreateTestContext(httptest.NewRecorder())
+	context.Request = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"label":"lunch","work_mode":"office"}`))
+	context.Request.Header.Set("Content-Type", "application/json")
+
+	var attendanceDTO dto.AttendanceDTO
+	res := helper.BuildValidationErrorResponse(context.ShouldBind(&attendanceDTO), helper.EmptyObj{})
+
+	if res.Code != helper.CodeValidationFailed {
+		t.Errorf("code = %s, want %s", res.Code, helper.CodeValidationFailed)
+	}
+	want := []string{"label oneof", "work_mode oneof"}
+	if len(res.Details) != len(want) {
+		t.Fatalf("details = %+v, want %v", res.Details, want)
+	}
+	for i, detail := range res.Details {
+		if got := detail.Field + " " + detail.Rule; got != want[i] {
+			t.Errorf("details[%d] = %s, want %s", i, got, want[i])
+		}
+	}
+}
+
+func TestMalformedBodyFailsValidation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	context, _ := gin.CreateTestContext(httptest.NewRecorder())
+	context.Request = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"description":42}`))
+	context.Request.Header.Set("Content-Type", "application/json")
+
+	var activityDTO dto.ActivityDTO
+	res := helper.BuildValidationErrorResponse(context.ShouldBind(&activityDTO), helper.EmptyObj{})
+
+	if res.Code != helper.CodeValidationFailed || len(res.Details) != 1 || res.Details[0].Field != "description" {
+		t.Errorf("got %s %+v, want VALIDATION_FAILED on description", res.Code, res.Details)
+	}
+}
+
+func TestErrorCodeFollowsWrappedErrors(t *testing.T) {
+	if code := errorCode(fmt.Errorf("check out: %w", errNotCheckedIn), helper.CodeInternal); code != helper.CodeNotCheckedIn {
+		t.Errorf("code = %s, want %s", code, helper.CodeNotCheckedIn)
+	}
+	if code := errorCode(fmt.Errorf("unknown"), helper.CodeInternal); code != helper.CodeInternal {
+		t.Errorf("code = %s, want the fallback", code)
+	}
+}
+
+func TestUnknownRouteAnswersWithErrorCode(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	testRouter().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/nope", nil))
+
+	var res helper.Response
+	if err := json.Unmarshal(recorder.Body.Bytes(), &res); err != nil {
+		t.Fatal(err)
+	}
+	if recorder.Code != http.StatusNotFound || res.Code != helper.CodeRouteNotFound {
+		t.Errorf("got %d %s, want 404 %s", recorder.Code, res.Code, helper.CodeRouteNotFound)
+	}
+}
+
+func TestCatalogueIsDocumented(t *testing.T) {
+	var spec struct {
+		Components struct {
+			Schemas struct {
+				ErrorResponse struct {
+					Properties struct {
+						Code struct {
+							Enum []helper.ErrorCode `json:"enum"`
+						} `json:"code"`
+					} `json:"properties"`
+				} `json:"ErrorResponse"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(docs.OpenAPI, &spec); err != nil {
+		t.Fatal(err)
+	}
+
+	documented := map[helper.ErrorCode]bool{}
+	for _, code := range spec.Components.Schemas.ErrorResponse.Properties.Code.Enum {
+		documented[code] = true
+	}
+	for err, code := range errorCodes {
+		if !documented[code] {
+			t.Errorf("%s of %q is missing from docs/openapi.json", code, err)
+		}
+	}
+}
+
+func TestAbortWithErrorMapsDomainErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		err    error
+		status int
+		code   helper.ErrorCode
+	}{
+		{fmt.Errorf("delete activity ACT-1: %w", service.ErrNotFound), http.StatusNotFound, helper.CodeActivityNotFound},
+		{fmt.Errorf("save check in: %w", service.ErrConflict), http.StatusConflict, helper.CodeConflict},
+		{fmt.Errorf("get users: %w", service.ErrUnavailable), http.StatusServiceUnavailable, helper.CodeUnavailable},
+		{errNotCheckedIn, http.StatusForbidden, helper.CodeNotCheckedIn},
+		{fmt.Errorf("syntax error"), http.StatusInternalServerError, helper.CodeInternal},
+	}
+	for _, test := range tests {
+		recorder := httptest.NewRecorder()
+		context, _ := gin.CreateTestContext(recorder)
+		context.Request = httptest.NewRequest(http.MethodDelete, "/", nil)
+
+		abortWithError(context, test.err, http.StatusForbidden, helper.CodeActivityNotFound)
+
+		var res helper.Response
+		if err := json.Unmarshal(recorder.Body.Bytes(), &res); err != nil {
+			t.Fatal(err)
+		}
+		if recorder.Code != test.status || res.Code != test.code {
+			t.Errorf("%v: got %d %s, want %d %s", test.err, recorder.Code, res.Code, test.status, test.code)
+		}
+		if test.status >= http.StatusInternalServerError && strings.Contains(recorder.Body.String(), test.err.Error()) {
+			t.Errorf("%v: the cause leaks into the response %s", test.err, recorder.Body.String())
+		}
+	}
+}
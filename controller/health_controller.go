@@ -0,0 +1,151 @@
+package controller
+
+import (
+	"armiariyan/attendances-system/helper"
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Statuses of a health check
+const (
+	checkUp   = "up"
+	checkDown = "down"
+)
+
+// HealthCheck is a dependency the server needs to answer requests, Run gets a
+// context ending with the timeout of the check
+type HealthCheck struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// MigrationVersion reads the version of the schema, nil when there is no database
+type MigrationVersion func(ctx context.Context) (int, error)
+
+type HealthController interface {
+	Livez(context *gin.Context)
+	Readyz(context *gin.Context)
+}
+
+type healthController struct {
+	readiness        *Readiness
+	checks           []HealthCheck
+	migrationVersion MigrationVersion
+	build            helper.ResponseBuild
+	timeout          time.Duration
+}
+
+// NewHealthController checks each of checks, and the migration version, for up to
+// timeout on every /readyz
+func NewHealthController(readiness *Readiness, checks []HealthCheck, migrationVersion MigrationVersion, build helper.ResponseBuild, timeout time.Duration) HealthController {
+	return &healthController{
+		readiness:        readiness,
+		checks:           checks,
+		migrationVersion: migrationVersion,
+		build:            build,
+		timeout:          timeout,
+	}
+}
+
+// RegisterHealthRoutes registers the probes of the orchestrator, they need no session
+func RegisterHealthRoutes(r *gin.Engine, c HealthController) {
+	r.GET("livez", c.Livez)
+	r.GET("readyz", c.Readyz)
+}
+
+// Livez answers as long as the server serves requests, a failing dependency
+// doesn't make a restart any useful
+func (c *healthController) Livez(context *gin.Context) {
+	context.JSON(http.StatusOK, helper.BuildResponse(true, "alive", helper.EmptyObj{}))
+}
+
+// Readyz runs every check at once and answers 503 when one is down or the server
+// is shutting down, with the result of each check
+func (c *healthController) Readyz(context *gin.Context) {
+	readiness := helper.ResponseReadiness{
+		Checks: map[string]helper.ResponseHealthCheck{},
+		Build:  c.build,
+	}
+	versions := make(chan int, 1)
+	checks := c.withMigrations(versions)
+
+	var mu sync.Mutex
+	var wait sync.WaitGroup
+	for _, check := range checks {
+		wait.Add(1)
+		go func(check HealthCheck) {
+			defer wait.Done()
+			result := c.run(context.Request.Context(), check)
+			mu.Lock()
+			readiness.Checks[check.Name] = result
+			mu.Unlock()
+		}(check)
+	}
+	wait.Wait()
+	select {
+	case readiness.MigrationVersion = <-versions:
+	default:
+	}
+
+	var problems []string
+	if !c.readiness.Ready() {
+		problems = append(problems, "server: shutting down")
+	}
+	for _, check := range checks {
+		if result := readiness.Checks[check.Name]; result.Status == checkDown {
+			problems = append(problems, check.Name+": "+result.Error)
+		}
+	}
+	if len(problems) > 0 {
+		response := helper.BuildErrorResponse(helper.CodeUnavailable, "Not ready", strings.Join(problems, "\n"), readiness)
+		context.AbortWithStatusJSON(http.StatusServiceUnavailable, response)
+		return
+	}
+	context.JSON(http.StatusOK, helper.BuildResponse(true, "ready", readiness))
+}
+
+// withMigrations adds the check reading the migration version to the checks, the
+// version read is sent on versions
+func (c *healthController) withMigrations(versions chan<- int) []HealthCheck {
+	if c.migrationVersion == nil {
+		return c.checks
+	}
+	migrations := HealthCheck{Name: "migrations", Run: func(ctx context.Context) error {
+		version, err := c.migrationVersion(ctx)
+		if err == nil {
+			versions <- version
+		}
+		return err
+	}}
+	return append(c.checks[:len(c.checks):len(c.checks)], migrations)
+}
+
+// run runs check with the timeout, a check still running at the timeout is down
+func (c *healthController) run(parent context.Context, check HealthCheck) helper.ResponseHealthCheck {
+	ctx, cancel := context.WithTimeout(parent, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- check.Run(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	result := helper.ResponseHealthCheck{Status: checkUp, DurationMs: time.Since(start).Milliseconds()}
+	if err != nil {
+		result.Status, result.Error = checkDown, err.Error()
+	}
+	return result
+}
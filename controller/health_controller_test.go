@@ -0,0 +1,82 @@
+package controller
+
+import (
+	"armiariyan/attendances-system/helper"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// readyz answers /readyz of a health controller with checks and returns its status and body
+func readyz(t *testing.T, readiness *Readiness, checks ...HealthCheck) (int, helper.Response, helper.ResponseReadiness) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	version := func(ctx context.Context) (int, error) { return 4, nil }
+	RegisterHealthRoutes(r, NewHealthController(readiness, checks, version, helper.ResponseBuild{Version: "v1"}, 50*time.Millisecond))
+
+	recorder := httptest.NewRecorder()
+	r.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	var data helper.ResponseReadiness
+	res := helper.Response{Data: &data}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &res); err != nil {
+		t.Fatal(err)
+	}
+	return recorder.Code, res, data
+}
+
+func up(name string) HealthCheck {
+	return HealthCheck{Name: name, Run: func(ctx context.Context) error { return nil }}
+}
+
+func TestReadyzWhenEveryCheckIsUp(t *testing.T) {
+	status, _, data := readyz(t, &Readiness{}, up("database"), up("sessions"))
+
+	if status != http.StatusOK {
+		t.Errorf("status = %d, want 200", status)
+	}
+	if len(data.Checks) != 3 || data.Checks["database"].Status != checkUp || data.Checks["migrations"].Status != checkUp {
+		t.Errorf("checks = %+v, want database, sessions and migrations up", data.Checks)
+	}
+	if data.MigrationVersion != 4 || data.Build.Version != "v1" {
+		t.Errorf("readiness = %+v, want migration 4 and build v1", data)
+	}
+}
+
+func TestReadyzReportsChecksDown(t *testing.T) {
+	failing := HealthCheck{Name: "database", Run: func(ctx context.Context) error { return errors.New("connection refused") }}
+	hanging := HealthCheck{Name: "sessions", Run: func(ctx context.Context) error {
+		<-time.After(time.Second)
+		return nil
+	}}
+	status, res, data := readyz(t, &Readiness{}, failing, hanging)
+
+	if status != http.StatusServiceUnavailable || res.Code != helper.CodeUnavailable {
+		t.Errorf("status = %d %s, want 503 %s", status, res.Code, helper.CodeUnavailable)
+	}
+	if check := data.Checks["database"]; check.Status != checkDown || check.Error != "connection refused" {
+		t.Errorf("database = %+v, want down with its error", check)
+	}
+	if check := data.Checks["sessions"]; check.Status != checkDown || check.Error != context.DeadlineExceeded.Error() {
+		t.Errorf("sessions = %+v, want down at the timeout", check)
+	}
+	if data.Checks["migrations"].Status != checkUp {
+		t.Errorf("migrations = %+v, want up", data.Checks["migrations"])
+	}
+}
+
+func TestReadyzWhileDraining(t *testing.T) {
+	readiness := &Readiness{}
+	readiness.Drain()
+	status, res, _ := readyz(t, readiness, up("database"))
+
+	if status != http.StatusServiceUnavailable || len(res.Errors.([]interface{})) != 1 {
+		t.Errorf("status = %d errors %v, want 503 for the shutdown only", status, res.Errors)
+	}
+}
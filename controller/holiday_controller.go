@@ -0,0 +1,138 @@
+package controller
+
+import (
+	"armiariyan/attendances-system/dto"
+	"armiariyan/attendances-system/helper"
+	"armiariyan/attendances-system/service"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HolidayController interface {
+	GetHolidays(context *gin.Context)
+	CreateHoliday(context *gin.Context)
+	DeleteHoliday(context *gin.Context)
+}
+
+type holidayController struct {
+	calendarService service.CalendarService
+	userService     service.UserService
+}
+
+func NewHolidayController(calendar service.CalendarService, user service.UserService) HolidayController {
+	return &holidayController{
+		calendarService: calendar,
+		userService:     user,
+	}
+}
+
+// RegisterHolidayRoutes registers the holiday calendar routes
+func RegisterHolidayRoutes(r *gin.Engine, c HolidayController) {
+	holidayRoutes := r.Group("api/holidays")
+	{
+		holidayRoutes.GET("", c.GetHolidays)
+		holidayRoutes.POST("", c.CreateHoliday)
+		holidayRoutes.DELETE("/:id_holiday", c.DeleteHoliday)
+	}
+}
+
+func (c *holidayController) GetHolidays(context *gin.Context) {
+	if !authorizeLogin(context) {
+		return
+	}
+
+	// Take year from query, default to this year
+	year, errConv := strconv.Atoi(context.DefaultQuery("year", strconv.Itoa(time.Now().Year())))
+	if errConv != nil {
+		response := helper.BuildErrorResponse(helper.CodeInvalidParameter, "Failed to process request", errConv.Error(), helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	holidays, err := c.calendarService.GetHolidays(context, strconv.Itoa(year)+"-01-01", strconv.Itoa(year)+"-12-31")
+	if err != nil {
+		abortWithError(context, err, http.StatusInternalServerError, helper.CodeInternal)
+		return
+	}
+
+	// Build response if success
+	res := helper.BuildResponse(true, "Successfully get holidays!", holidays)
+	context.JSON(http.StatusOK, res)
+}
+
+func (c *holidayController) CreateHoliday(context *gin.Context) {
+	if !authorizeAdmin(context, c.userService) {
+		return
+	}
+
+	var holidayDTO dto.HolidayDTO
+	// Fill holidayDTO variable
+	errDTO := context.ShouldBind(&holidayDTO)
+	if errDTO != nil {
+		response := helper.BuildValidationErrorResponse(errDTO, helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// Check duplicate date
+	duplicate, err := c.calendarService.IsDuplicateHoliday(context, holidayDTO.Date)
+	if err != nil {
+		abortWithError(context, err, http.StatusInternalServerError, helper.CodeInternal)
+		return
+	}
+	if duplicate {
+		response := helper.BuildErrorResponse(helper.CodeHolidayExists, "Failed to process request", "Holiday has been registered", helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusConflict, response)
+		return
+	}
+
+	holiday, err := c.calendarService.CreateHoliday(context, holidayDTO)
+	if err != nil {
+		abortWithError(context, err, http.StatusInternalServerError, helper.CodeInternal)
+		return
+	}
+
+	// Build response if success
+	res := helper.BuildResponse(true, "Successfully Created Holiday!", holiday)
+	context.JSON(http.StatusCreated, res)
+}
+
+func (c *holidayController) DeleteHoliday(context *gin.Context) {
+	if !authorizeAdmin(context, c.userService) {
+		return
+	}
+
+	// Take id from parameter and convert to int
+	holiday_id, errConv := strconv.Atoi(context.Param("id_holiday"))
+	if errConv != nil {
+		response := helper.BuildErrorResponse(helper.CodeInvalidParameter, "Failed to process request", errConv.Error(), helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// Check if holiday exist
+	holiday, err := c.calendarService.GetHolidayById(context, holiday_id)
+	if err != nil && !errors.Is(err, service.ErrNotFound) {
+		abortWithError(context, err, http.StatusInternalServerError, helper.CodeInternal)
+		return
+	}
+	if holiday.Id == 0 {
+		response := helper.BuildErrorResponse(helper.CodeHolidayNotFound, "Failed to process request", "Holiday not found", helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusNotFound, response)
+		return
+	}
+
+	// Delete
+	if err := c.calendarService.DeleteHoliday(context, holiday); err != nil {
+		abortWithError(context, err, http.StatusInternalServerError, helper.CodeInternal)
+		return
+	}
+
+	// Build response if success
+	res := helper.BuildResponse(true, "Holiday deleted!", helper.EmptyObj{})
+	context.JSON(http.StatusOK, res)
+}
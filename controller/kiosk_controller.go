@@ -0,0 +1,96 @@
+package controller
+
+import (
+	"armiariyan/attendances-system/helper"
+	"armiariyan/attendances-system/service"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type KioskController interface {
+	GetQRCode(context *gin.Context)
+	GetToken(context *gin.Context)
+}
+
+type kioskController struct {
+	kioskService service.KioskService
+}
+
+func NewKioskController(kiosk service.KioskService) KioskController {
+	return &kioskController{
+		kioskService: kiosk,
+	}
+}
+
+// RegisterKioskRoutes registers the kiosk QR code routes
+func RegisterKioskRoutes(r *gin.Engine, c KioskController) {
+	kioskRoutes := r.Group("api/kiosk")
+	{
+		kioskRoutes.GET("/qr", c.GetQRCode)
+		kioskRoutes.GET("/token", c.GetToken)
+	}
+}
+
+// authorizeKiosk aborts the request unless it comes from a configured kiosk device
+func (c *kioskController) authorizeKiosk(context *gin.Context) bool {
+	// Browsers showing the png in an img tag can't set headers, so the key may also be a query
+	key := context.GetHeader("X-Kiosk-Key")
+	if key == "" {
+		key = context.Query("key")
+	}
+
+	if !c.kioskService.IsValidKioskKey(key) {
+		response := helper.BuildErrorResponse(helper.CodeKioskUnauthorized, "Failed to process request", "Unauthorized kiosk!", helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusUnauthorized, response)
+		return false
+	}
+	return true
+}
+
+func (c *kioskController) GetQRCode(context *gin.Context) {
+	if !c.authorizeKiosk(context) {
+		return
+	}
+
+	// Take png size from query, default 256 pixel
+	size, errConv := strconv.Atoi(context.DefaultQuery("size", "256"))
+	if errConv != nil || size < 64 || size > 1024 {
+		response := helper.BuildErrorResponse(helper.CodeInvalidParameter, "Failed to process request", "size must be between 64 and 1024", helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// Generate token and render it as qr code
+	token, expiresAt := c.kioskService.GenerateToken()
+	png, err := c.kioskService.GenerateQRCode(token, size)
+	if err != nil {
+		response := helper.BuildErrorResponse(helper.CodeInternal, "Failed to process request", err.Error(), helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	// Let the kiosk know when to fetch the next code
+	context.Header("Cache-Control", "no-store")
+	context.Header("Refresh", strconv.Itoa(int(service.KioskTokenTTL.Seconds())))
+	context.Header("X-Token-Expires-At", expiresAt.Format(time.RFC3339))
+	context.Data(http.StatusOK, "image/png", png)
+}
+
+func (c *kioskController) GetToken(context *gin.Context) {
+	if !c.authorizeKiosk(context) {
+		return
+	}
+
+	token, expiresAt := c.kioskService.GenerateToken()
+
+	// Build response if success
+	context.Header("Cache-Control", "no-store")
+	res := helper.BuildResponse(true, "Successfully generate kiosk token!", gin.H{
+		"token":      token,
+		"expires_at": expiresAt.Format(time.RFC3339),
+	})
+	context.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,173 @@
+package controller
+
+import (
+	"armiariyan/attendances-system/dto"
+	"armiariyan/attendances-system/entity"
+	"armiariyan/attendances-system/helper"
+	"armiariyan/attendances-system/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+)
+
+type LeaveController interface {
+	RequestLeave(context *gin.Context)
+	GetLeaves(context *gin.Context)
+	ApproveLeave(context *gin.Context)
+	RejectLeave(context *gin.Context)
+}
+
+type leaveController struct {
+	leaveService service.LeaveService
+	userService  service.UserService
+}
+
+func NewLeaveController(leave service.LeaveService, user service.UserService) LeaveController {
+	return &leaveController{
+		leaveService: leave,
+		userService:  user,
+	}
+}
+
+// RegisterLeaveRoutes registers the leave request routes
+func RegisterLeaveRoutes(r *gin.Engine, c LeaveController) {
+	leaveRoutes := r.Group("api/leaves")
+	{
+		leaveRoutes.POST("/:id", c.RequestLeave)
+		leaveRoutes.GET("/:id", c.GetLeaves)
+		leaveRoutes.PUT("/:id/:id_leave/approve", c.ApproveLeave)
+		leaveRoutes.PUT("/:id/:id_leave/reject", c.RejectLeave)
+	}
+}
+
+func (c *leaveController) RequestLeave(context *gin.Context) {
+
+	// Take id from parameter and convert to int
+	user_id, errConv := strconv.Atoi(context.Param("id"))
+	if errConv != nil {
+		response := helper.BuildErrorResponse(helper.CodeInvalidParameter, "Failed to process request", errConv.Error(), helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// Check if user exist and logged in using session
+	session := sessions.Default(context)
+	if !helper.IsLogin(session.Get("loggedIn")) {
+		response := helper.BuildErrorResponse(helper.CodeAuthRequired, "Failed to process request", "Please login first!", helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusUnauthorized, response)
+		return
+	}
+
+	// Check if user authorized to access data
+	if !helper.IsAuthorize(session.Get("user_id"), user_id) {
+		response := helper.BuildErrorResponse(helper.CodeForbidden, "Failed to process request", "Unauthorized!", helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusForbidden, response)
+		return
+	}
+
+	var leaveDTO dto.LeaveDTO
+	// Fill leaveDTO variable
+	errDTO := context.ShouldBind(&leaveDTO)
+	if errDTO != nil {
+		response := helper.BuildValidationErrorResponse(errDTO, helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// Request leave
+	leave, err := c.leaveService.RequestLeave(context, user_id, leaveDTO)
+	if err != nil {
+		abortWithError(context, err, http.StatusBadRequest, helper.CodeValidationFailed)
+		return
+	}
+
+	// Build response if success
+	res := helper.BuildResponse(true, "Successfully Requested Leave!", leave)
+	context.JSON(http.StatusCreated, res)
+}
+
+func (c *leaveController) GetLeaves(context *gin.Context) {
+
+	// Take id from parameter and convert to int
+	user_id, errConv := strconv.Atoi(context.Param("id"))
+	if errConv != nil {
+		response := helper.BuildErrorResponse(helper.CodeInvalidParameter, "Failed to process request", errConv.Error(), helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	if !authorizeSelfOrAdmin(context, c.userService, user_id) {
+		return
+	}
+
+	// Build response if success
+	res := helper.BuildResponse(true, "Successfully get leaves!", c.leaveService.GetLeaves(context, user_id))
+	context.JSON(http.StatusOK, res)
+}
+
+func (c *leaveController) ApproveLeave(context *gin.Context) {
+	c.reviewLeave(context, entity.LeaveStatusApproved)
+}
+
+func (c *leaveController) RejectLeave(context *gin.Context) {
+	c.reviewLeave(context, entity.LeaveStatusRejected)
+}
+
+func (c *leaveController) reviewLeave(context *gin.Context, status string) {
+
+	// Take id from parameter and convert to int
+	user_id, errConv := strconv.Atoi(context.Param("id"))
+	if errConv != nil {
+		response := helper.BuildErrorResponse(helper.CodeInvalidParameter, "Failed to process request", errConv.Error(), helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+	leave_id, errConv := strconv.Atoi(context.Param("id_leave"))
+	if errConv != nil {
+		response := helper.BuildErrorResponse(helper.CodeInvalidParameter, "Failed to process request", errConv.Error(), helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// Check if user exist and logged in using session
+	session := sessions.Default(context)
+	if !helper.IsLogin(session.Get("loggedIn")) {
+		response := helper.BuildErrorResponse(helper.CodeAuthRequired, "Failed to process request", "Please login first!", helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusUnauthorized, response)
+		return
+	}
+
+	// Check if leave exist
+	leave := c.leaveService.GetLeaveById(context, leave_id)
+	if leave.Id == 0 || leave.UserId != user_id {
+		response := helper.BuildErrorResponse(helper.CodeLeaveNotFound, "Failed to process request", "Leave not found", helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusNotFound, response)
+		return
+	}
+
+	// Check if user is the manager of the leave owner or an admin
+	reviewer_id, _ := session.Get("user_id").(int)
+	canReview, errReview := c.leaveService.CanReview(context, reviewer_id, leave)
+	if errReview != nil {
+		abortWithError(context, errReview, http.StatusInternalServerError, helper.CodeInternal)
+		return
+	}
+	if !canReview {
+		response := helper.BuildErrorResponse(helper.CodeForbidden, "Failed to process request", "Unauthorized!", helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusForbidden, response)
+		return
+	}
+
+	// Review
+	leave, err := c.leaveService.ReviewLeave(context, leave, reviewer_id, status)
+	if err != nil {
+		abortWithError(context, err, http.StatusConflict, helper.CodeLeaveReviewed)
+		return
+	}
+
+	// Build response if success
+	res := helper.BuildResponse(true, "Successfully Reviewed Leave!", leave)
+	context.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,26 @@
+package controller
+
+import (
+	"armiariyan/attendances-system/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MetricsController interface {
+	GetMetrics(context *gin.Context)
+}
+
+type metricsController struct{}
+
+func NewMetricsController() MetricsController {
+	return &metricsController{}
+}
+
+// RegisterMetricsRoutes registers the metrics scraped by Prometheus, they need no session
+func RegisterMetricsRoutes(r *gin.Engine, c MetricsController) {
+	r.GET("metrics", c.GetMetrics)
+}
+
+func (c *metricsController) GetMetrics(context *gin.Context) {
+	metrics.Handler().ServeHTTP(context.Writer, context.Request)
+}
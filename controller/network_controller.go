@@ -0,0 +1,129 @@
+package controller
+
+import (
+	"armiariyan/attendances-system/dto"
+	"armiariyan/attendances-system/helper"
+	"armiariyan/attendances-system/service"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type NetworkController interface {
+	GetNetworks(context *gin.Context)
+	CreateNetwork(context *gin.Context)
+	DeleteNetwork(context *gin.Context)
+}
+
+type networkController struct {
+	networkService service.NetworkService
+	userService    service.UserService
+}
+
+func NewNetworkController(network service.NetworkService, user service.UserService) NetworkController {
+	return &networkController{
+		networkService: network,
+		userService:    user,
+	}
+}
+
+// RegisterNetworkRoutes registers the trusted office network routes
+func RegisterNetworkRoutes(r *gin.Engine, c NetworkController) {
+	networkRoutes := r.Group("api/networks")
+	{
+		networkRoutes.GET("", c.GetNetworks)
+		networkRoutes.POST("", c.CreateNetwork)
+		networkRoutes.DELETE("/:id_network", c.DeleteNetwork)
+	}
+}
+
+func (c *networkController) GetNetworks(context *gin.Context) {
+	if !authorizeAdmin(context, c.userService) {
+		return
+	}
+
+	networks, err := c.networkService.GetNetworks(context)
+	if err != nil {
+		abortWithError(context, err, http.StatusInternalServerError, helper.CodeInternal)
+		return
+	}
+
+	// Build response if success
+	res := helper.BuildResponse(true, "Successfully get office networks!", networks)
+	context.JSON(http.StatusOK, res)
+}
+
+func (c *networkController) CreateNetwork(context *gin.Context) {
+	if !authorizeAdmin(context, c.userService) {
+		return
+	}
+
+	var createNetworkDTO dto.CreateNetworkDTO
+	// Fill createNetworkDTO variable
+	errDTO := context.ShouldBind(&createNetworkDTO)
+	if errDTO != nil {
+		response := helper.BuildValidationErrorResponse(errDTO, helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// Check duplicate cidr
+	duplicate, err := c.networkService.IsDuplicateNetwork(context, createNetworkDTO.CIDR)
+	if err != nil {
+		abortWithError(context, err, http.StatusInternalServerError, helper.CodeInternal)
+		return
+	}
+	if duplicate {
+		response := helper.BuildErrorResponse(helper.CodeNetworkExists, "Failed to process request", "Network has been registered", helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusConflict, response)
+		return
+	}
+
+	network, err := c.networkService.CreateNetwork(context, createNetworkDTO)
+	if err != nil {
+		abortWithError(context, err, http.StatusInternalServerError, helper.CodeInternal)
+		return
+	}
+
+	// Build response if success
+	res := helper.BuildResponse(true, "Successfully Created Office Network!", network)
+	context.JSON(http.StatusCreated, res)
+}
+
+func (c *networkController) DeleteNetwork(context *gin.Context) {
+	if !authorizeAdmin(context, c.userService) {
+		return
+	}
+
+	// Take id from parameter and convert to int
+	network_id, errConv := strconv.Atoi(context.Param("id_network"))
+	if errConv != nil {
+		response := helper.BuildErrorResponse(helper.CodeInvalidParameter, "Failed to process request", errConv.Error(), helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// Check if network exist
+	network, err := c.networkService.GetNetworkById(context, network_id)
+	if err != nil && !errors.Is(err, service.ErrNotFound) {
+		abortWithError(context, err, http.StatusInternalServerError, helper.CodeInternal)
+		return
+	}
+	if helper.IsNetworkEmpty(network) {
+		response := helper.BuildErrorResponse(helper.CodeNetworkNotFound, "Failed to process request", "Network not found", helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusNotFound, response)
+		return
+	}
+
+	// Delete
+	if err := c.networkService.DeleteNetwork(context, network); err != nil {
+		abortWithError(context, err, http.StatusInternalServerError, helper.CodeInternal)
+		return
+	}
+
+	// Build response if success
+	res := helper.BuildResponse(true, "Network deleted!", helper.EmptyObj{})
+	context.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"armiariyan/attendances-system/helper"
+	"armiariyan/attendances-system/service"
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PayrollController interface {
+	ExportPayroll(context *gin.Context)
+}
+
+type payrollController struct {
+	payrollService service.PayrollService
+	userService    service.UserService
+}
+
+func NewPayrollController(payroll service.PayrollService, user service.UserService) PayrollController {
+	return &payrollController{
+		payrollService: payroll,
+		userService:    user,
+	}
+}
+
+// RegisterPayrollRoutes registers the payroll export route
+func RegisterPayrollRoutes(r *gin.Engine, c PayrollController) {
+	r.GET("api/payroll/export", c.ExportPayroll)
+}
+
+func (c *payrollController) ExportPayroll(context *gin.Context) {
+	if !authorizeAdmin(context, c.userService) {
+		return
+	}
+
+	// Take the date range from querry, the days are those of its time zone
+	timeRange, ok := bindClosedRange(context)
+	if !ok {
+		return
+	}
+	_, endDate := timeRange.UnixMilli()
+	from := timeRange.From
+	to := time.UnixMilli(endDate).In(from.Location())
+
+	// Write into a buffer first so a failing export still answers with json
+	format := context.DefaultQuery("format", service.PayrollFormatCSV)
+	var file bytes.Buffer
+	err := c.payrollService.Export(context, &file, from, to, format)
+	if err == service.ErrPayrollFormat {
+		response := helper.BuildErrorResponse(helper.CodeInvalidParameter, "Failed to process request", err.Error(), helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+	if errors.Is(err, service.ErrUnavailable) {
+		abortWithError(context, err, http.StatusServiceUnavailable, helper.CodeUnavailable)
+		return
+	}
+	if err != nil {
+		response := helper.BuildErrorResponse(helper.CodeExportFailed, "Failed to process request", err.Error(), helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	contentType := "text/csv"
+	extension := "csv"
+	if format == service.PayrollFormatFixedWidth {
+		contentType = "text/plain"
+		extension = "txt"
+	}
+	filename := fmt.Sprintf("payroll_%s_%s.%s", from.Format("20060102"), to.Format("20060102"), extension)
+	context.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	context.Data(http.StatusOK, contentType+"; charset=utf-8", file.Bytes())
+}
@@ -0,0 +1,149 @@
+package controller
+
+import (
+	"armiariyan/attendances-system/dto"
+	"armiariyan/attendances-system/entity"
+	"armiariyan/attendances-system/helper"
+	"armiariyan/attendances-system/service"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PolicyController interface {
+	GetPolicy(context *gin.Context)
+	SetPolicy(context *gin.Context)
+	DeletePolicy(context *gin.Context)
+}
+
+type policyController struct {
+	policyService service.PolicyService
+	userService   service.UserService
+}
+
+func NewPolicyController(policy service.PolicyService, user service.UserService) PolicyController {
+	return &policyController{
+		policyService: policy,
+		userService:   user,
+	}
+}
+
+// RegisterPolicyRoutes registers the work policy routes
+func RegisterPolicyRoutes(r *gin.Engine, c PolicyController) {
+	policyRoutes := r.Group("api/policies")
+	{
+		policyRoutes.GET("/:id", c.GetPolicy)
+		policyRoutes.PUT("/:id", c.SetPolicy)
+		policyRoutes.DELETE("/:id", c.DeletePolicy)
+	}
+}
+
+func (c *policyController) GetPolicy(context *gin.Context) {
+	// Take id from parameter and convert to int
+	user_id, errConv := strconv.Atoi(context.Param("id"))
+	if errConv != nil {
+		response := helper.BuildErrorResponse(helper.CodeInvalidParameter, "Failed to process request", errConv.Error(), helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	if !authorizeSelfOrAdmin(context, c.userService, user_id) {
+		return
+	}
+
+	policy, ok := c.findPolicy(context, user_id)
+	if !ok {
+		return
+	}
+
+	// Build response if success
+	res := helper.BuildResponse(true, "Successfully get work policy!", policy)
+	context.JSON(http.StatusOK, res)
+}
+
+func (c *policyController) SetPolicy(context *gin.Context) {
+	// Take id from parameter and convert to int
+	user_id, errConv := strconv.Atoi(context.Param("id"))
+	if errConv != nil {
+		response := helper.BuildErrorResponse(helper.CodeInvalidParameter, "Failed to process request", errConv.Error(), helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	if !authorizeAdmin(context, c.userService) {
+		return
+	}
+
+	// Check if user exist
+	if _, err := c.userService.GetUserById(context, user_id); err != nil {
+		abortWithError(context, err, http.StatusNotFound, helper.CodeUserNotFound)
+		return
+	}
+
+	var workPolicyDTO dto.WorkPolicyDTO
+	// Fill workPolicyDTO variable
+	errDTO := context.ShouldBind(&workPolicyDTO)
+	if errDTO != nil {
+		response := helper.BuildValidationErrorResponse(errDTO, helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	policy, err := c.policyService.SetPolicy(context, user_id, workPolicyDTO)
+	if err != nil {
+		abortWithError(context, err, http.StatusInternalServerError, helper.CodeInternal)
+		return
+	}
+
+	// Build response if success
+	res := helper.BuildResponse(true, "Successfully Saved Work Policy!", policy)
+	context.JSON(http.StatusOK, res)
+}
+
+func (c *policyController) DeletePolicy(context *gin.Context) {
+	// Take id from parameter and convert to int
+	user_id, errConv := strconv.Atoi(context.Param("id"))
+	if errConv != nil {
+		response := helper.BuildErrorResponse(helper.CodeInvalidParameter, "Failed to process request", errConv.Error(), helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	if !authorizeAdmin(context, c.userService) {
+		return
+	}
+
+	policy, ok := c.findPolicy(context, user_id)
+	if !ok {
+		return
+	}
+
+	// Delete
+	if err := c.policyService.DeletePolicy(context, policy); err != nil {
+		abortWithError(context, err, http.StatusInternalServerError, helper.CodeInternal)
+		return
+	}
+
+	// Build response if success
+	res := helper.BuildResponse(true, "Policy deleted!", helper.EmptyObj{})
+	context.JSON(http.StatusOK, res)
+}
+
+// findPolicy returns the policy of the user and aborts when there is none or it can't be read
+func (c *policyController) findPolicy(context *gin.Context, user_id int) (entity.WorkPolicy, bool) {
+	policy, err := c.policyService.GetPolicy(context, user_id)
+	if err != nil && !errors.Is(err, service.ErrNotFound) {
+		abortWithError(context, err, http.StatusInternalServerError, helper.CodeInternal)
+		return policy, false
+	}
+
+	// Check if user has a policy
+	if policy.UserId == 0 {
+		response := helper.BuildErrorResponse(helper.CodePolicyNotFound, "Failed to process request", "Policy not found", helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusNotFound, response)
+		return policy, false
+	}
+	return policy, true
+}
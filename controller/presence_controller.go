@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"armiariyan/attendances-system/helper"
+	"armiariyan/attendances-system/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PresenceController interface {
+	GetPresenceBoard(context *gin.Context)
+}
+
+type presenceController struct {
+	presenceService   service.PresenceService
+	departmentService service.DepartmentService
+	userService       service.UserService
+}
+
+func NewPresenceController(presence service.PresenceService, department service.DepartmentService, user service.UserService) PresenceController {
+	return &presenceController{
+		presenceService:   presence,
+		departmentService: department,
+		userService:       user,
+	}
+}
+
+// RegisterPresenceRoutes registers the presence board route
+func RegisterPresenceRoutes(r *gin.Engine, c PresenceController) {
+	r.GET("api/presence", c.GetPresenceBoard)
+}
+
+func (c *presenceController) GetPresenceBoard(context *gin.Context) {
+	user_ids, department_id, ok := authorizeTeam(context, c.userService, c.departmentService)
+	if !ok {
+		return
+	}
+
+	board, err := c.presenceService.GetPresenceBoard(context, user_ids, department_id)
+	if err != nil {
+		abortWithError(context, err, http.StatusInternalServerError, helper.CodeInternal)
+		return
+	}
+
+	// Build response if success
+	res := helper.BuildResponse(true, "Successfully get presence board!", board)
+	context.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,18 @@
+package controller
+
+import "sync/atomic"
+
+// Readiness tells whether the server takes new requests, it stops being ready
+// for good once the server starts draining its requests to shut down
+type Readiness struct {
+	draining int32
+}
+
+// Drain marks the server as not ready
+func (readiness *Readiness) Drain() {
+	atomic.StoreInt32(&readiness.draining, 1)
+}
+
+func (readiness *Readiness) Ready() bool {
+	return atomic.LoadInt32(&readiness.draining) == 0
+}
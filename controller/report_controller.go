@@ -0,0 +1,65 @@
+package controller
+
+import (
+	"armiariyan/attendances-system/helper"
+	"armiariyan/attendances-system/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReportController interface {
+	GetWorkModeReport(context *gin.Context)
+}
+
+type reportController struct {
+	reportService service.ReportService
+	userService   service.UserService
+}
+
+func NewReportController(report service.ReportService, user service.UserService) ReportController {
+	return &reportController{
+		reportService: report,
+		userService:   user,
+	}
+}
+
+// RegisterReportRoutes registers the report routes
+func RegisterReportRoutes(r *gin.Engine, c ReportController) {
+	reportRoutes := r.Group("api/reports")
+	{
+		reportRoutes.GET("/work-modes/:id", c.GetWorkModeReport)
+	}
+}
+
+func (c *reportController) GetWorkModeReport(context *gin.Context) {
+	// Take id from parameter and convert to int
+	user_id, errConv := strconv.Atoi(context.Param("id"))
+	if errConv != nil {
+		response := helper.BuildErrorResponse(helper.CodeInvalidParameter, "Failed to process request", errConv.Error(), helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	if !authorizeSelfOrAdmin(context, c.userService, user_id) {
+		return
+	}
+
+	// Take the date range from querry
+	timeRange, ok := bindClosedRange(context)
+	if !ok {
+		return
+	}
+	startDate, endDate := timeRange.UnixMilli()
+
+	report, err := c.reportService.GetWorkModeReport(context, user_id, startDate, endDate)
+	if err != nil {
+		abortWithError(context, err, http.StatusInternalServerError, helper.CodeInternal)
+		return
+	}
+
+	// Build response if success
+	res := helper.BuildResponse(true, "Successfully get work mode report!", report)
+	context.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,75 @@
+package controller
+
+import (
+	"armiariyan/attendances-system/helper"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// v1DeprecatedAt is when the v1 routes replaced by /api/v2 were deprecated
+var v1DeprecatedAt = time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+
+// Controllers holds every controller serving the api
+type Controllers struct {
+	User       UserController
+	Attendance AttendanceController
+	Activity   ActivityController
+	Network    NetworkController
+	Kiosk      KioskController
+	Policy     PolicyController
+	Report     ReportController
+	Department DepartmentController
+	Leave      LeaveController
+	Presence   PresenceController
+	Holiday    HolidayController
+	Timesheet  TimesheetController
+	Payroll    PayrollController
+	Absence    AbsenceController
+	Webhook    WebhookController
+	Docs       DocsController
+	Metrics    MetricsController
+	Health     HealthController
+}
+
+// RegisterRoutes registers the routes of every module of the api, docs/openapi.json
+// has to describe each of them
+func RegisterRoutes(r *gin.Engine, c Controllers) {
+	RegisterUserRoutes(r, c.User)
+	RegisterAttendanceRoutes(r, c.Attendance)
+	RegisterActivityRoutes(r, c.Activity)
+	RegisterNetworkRoutes(r, c.Network)
+	RegisterKioskRoutes(r, c.Kiosk)
+	RegisterPolicyRoutes(r, c.Policy)
+	RegisterReportRoutes(r, c.Report)
+	RegisterDepartmentRoutes(r, c.Department)
+	RegisterLeaveRoutes(r, c.Leave)
+	RegisterPresenceRoutes(r, c.Presence)
+	RegisterHolidayRoutes(r, c.Holiday)
+	RegisterTimesheetRoutes(r, c.Timesheet)
+	RegisterPayrollRoutes(r, c.Payroll)
+	RegisterAbsenceRoutes(r, c.Absence)
+	RegisterWebhookRoutes(r, c.Webhook)
+	RegisterDocsRoutes(r, c.Docs)
+	RegisterMetricsRoutes(r, c.Metrics)
+	RegisterHealthRoutes(r, c.Health)
+
+	r.NoRoute(routeNotFound)
+}
+
+// routeNotFound answers unknown routes with the error envelope instead of gin's plain text
+func routeNotFound(context *gin.Context) {
+	response := helper.BuildErrorResponse(helper.CodeRouteNotFound, "Failed to process request", "Route not found", helper.EmptyObj{})
+	context.AbortWithStatusJSON(http.StatusNotFound, response)
+}
+
+// deprecated marks a v1 route replaced by the successor route of v2
+func deprecated(successor string) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		context.Header("Deprecation", "@"+strconv.FormatInt(v1DeprecatedAt.Unix(), 10))
+		context.Header("Link", "<"+successor+">; rel=\"successor-version\"")
+		context.Next()
+	}
+}
@@ -0,0 +1,125 @@
+package controller
+
+import (
+	"armiariyan/attendances-system/docs"
+	"armiariyan/attendances-system/helper"
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type openAPI struct {
+	Paths map[string]map[string]json.RawMessage `json:"paths"`
+}
+
+// pathParam turns the :id of gin paths into the {id} of OpenAPI
+var pathParam = regexp.MustCompile(`:([A-Za-z_]+)`)
+
+// testRouter registers every route on controllers without services, only good for requests never reaching them
+func testRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	RegisterRoutes(r, Controllers{
+		User:       NewUserController(nil, &Readiness{}),
+		Attendance: NewAttendanceController(nil, nil, nil, nil, nil, nil),
+		Activity:   NewActivityController(nil, nil, nil, nil),
+		Network:    NewNetworkController(nil, nil),
+		Kiosk:      NewKioskController(nil),
+		Policy:     NewPolicyController(nil, nil),
+		Report:     NewReportController(nil, nil),
+		Department: NewDepartmentController(nil, nil),
+		Leave:      NewLeaveController(nil, nil),
+		Presence:   NewPresenceController(nil, nil, nil),
+		Holiday:    NewHolidayController(nil, nil),
+		Timesheet:  NewTimesheetController(nil, nil),
+		Payroll:    NewPayrollController(nil, nil),
+		Absence:    NewAbsenceController(nil, nil, nil),
+		Webhook:    NewWebhookController(nil, nil),
+		Docs:       NewDocsController(),
+		Metrics:    NewMetricsController(),
+		Health:     NewHealthController(&Readiness{}, nil, nil, helper.ResponseBuild{}, time.Second),
+	})
+	return r
+}
+
+func registeredRoutes() gin.RoutesInfo {
+	return testRouter().Routes()
+}
+
+func loadSpec(t *testing.T) openAPI {
+	var spec openAPI
+	if err := json.Unmarshal(docs.OpenAPI, &spec); err != nil {
+		t.Fatalf("docs/openapi.json is not valid json: %v", err)
+	}
+	return spec
+}
+
+func TestEveryRouteIsDocumented(t *testing.T) {
+	spec := loadSpec(t)
+
+	for _, route := range registeredRoutes() {
+		path := pathParam.ReplaceAllString(route.Path, "{$1}")
+		if _, ok := spec.Paths[path][strings.ToLower(route.Method)]; !ok {
+			t.Errorf("%s %s is missing from docs/openapi.json", route.Method, path)
+		}
+	}
+}
+
+func TestEveryDocumentedRouteExists(t *testing.T) {
+	spec := loadSpec(t)
+
+	registered := map[string]bool{}
+	for _, route := range registeredRoutes() {
+		registered[strings.ToLower(route.Method)+" "+pathParam.ReplaceAllString(route.Path, "{$1}")] = true
+	}
+
+	var stale []string
+	for path, operations := range spec.Paths {
+		for method := range operations {
+			if !registered[method+" "+path] {
+				stale = append(stale, method+" "+path)
+			}
+		}
+	}
+	sort.Strings(stale)
+	for _, operation := range stale {
+		t.Errorf("%s is documented but not registered", operation)
+	}
+}
+
+func TestSpecReferencesResolve(t *testing.T) {
+	var spec map[string]interface{}
+	if err := json.Unmarshal(docs.OpenAPI, &spec); err != nil {
+		t.Fatal(err)
+	}
+
+	var walk func(node interface{})
+	walk = func(node interface{}) {
+		switch value := node.(type) {
+		case map[string]interface{}:
+			if ref, ok := value["$ref"].(string); ok {
+				var target interface{} = spec
+				for _, key := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+					object, _ := target.(map[string]interface{})
+					target = object[key]
+				}
+				if target == nil {
+					t.Errorf("%s doesn't resolve", ref)
+				}
+			}
+			for _, child := range value {
+				walk(child)
+			}
+		case []interface{}:
+			for _, child := range value {
+				walk(child)
+			}
+		}
+	}
+	walk(spec)
+}
@@ -0,0 +1,64 @@
+package controller
+
+import (
+	"armiariyan/attendances-system/helper"
+	"armiariyan/attendances-system/service"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TimesheetController interface {
+	GetTimesheet(context *gin.Context)
+}
+
+type timesheetController struct {
+	timesheetService service.TimesheetService
+	userService      service.UserService
+}
+
+func NewTimesheetController(timesheet service.TimesheetService, user service.UserService) TimesheetController {
+	return &timesheetController{
+		timesheetService: timesheet,
+		userService:      user,
+	}
+}
+
+// RegisterTimesheetRoutes registers the timesheet route
+func RegisterTimesheetRoutes(r *gin.Engine, c TimesheetController) {
+	r.GET("api/timesheet/:id", c.GetTimesheet)
+}
+
+func (c *timesheetController) GetTimesheet(context *gin.Context) {
+	// Take id from parameter and convert to int
+	user_id, errConv := strconv.Atoi(context.Param("id"))
+	if errConv != nil {
+		response := helper.BuildErrorResponse(helper.CodeInvalidParameter, "Failed to process request", errConv.Error(), helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	if !authorizeSelfOrAdmin(context, c.userService, user_id) {
+		return
+	}
+
+	// Take month from query, default to this month
+	month, errMonth := time.ParseInLocation("2006-01", context.DefaultQuery("month", time.Now().Format("2006-01")), time.Local)
+	if errMonth != nil {
+		response := helper.BuildErrorResponse(helper.CodeInvalidParameter, "Failed to process request", "month must be formatted as 2006-01", helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	timesheet, err := c.timesheetService.GetTimesheet(context, user_id, month)
+	if err != nil {
+		abortWithError(context, err, http.StatusInternalServerError, helper.CodeInternal)
+		return
+	}
+
+	// Build response if success
+	res := helper.BuildResponse(true, "Successfully get timesheet!", timesheet)
+	context.JSON(http.StatusOK, res)
+}
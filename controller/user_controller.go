@@ -2,13 +2,12 @@ package controller
 
 import (
 	"armiariyan/attendances-system/dto"
-	"armiariyan/attendances-system/entity"
 	"armiariyan/attendances-system/helper"
+	"armiariyan/attendances-system/logger"
+	"armiariyan/attendances-system/metrics"
 	"armiariyan/attendances-system/service"
-	"fmt"
+	"errors"
 	"net/http"
-	"strconv"
-	"time"
 
 	"github.com/gin-contrib/sessions"
 	"github.com/gin-gonic/gin"
@@ -20,84 +19,56 @@ type UserController interface {
 	Register(context *gin.Context)
 	Login(context *gin.Context)
 	Logout(context *gin.Context)
-	CheckIn(context *gin.Context)
-	CheckOut(context *gin.Context)
-	CreateActivity(context *gin.Context)
-	UpdateActivity(context *gin.Context)
-	DeleteActivity(context *gin.Context)
-	GetActivityHistoryByDate(context *gin.Context)
-	GetAttendancesHistory(context *gin.Context)
+	GetMe(context *gin.Context)
 }
 
 type userController struct {
 	userService service.UserService
+	readiness   *Readiness
 }
 
-func NewUserController(user service.UserService) UserController {
+func NewUserController(user service.UserService, readiness *Readiness) UserController {
 	return &userController{
 		userService: user,
+		readiness:   readiness,
 	}
 }
 
-func (c *userController) Index(context *gin.Context) {
-	context.Redirect(http.StatusFound, "/api/check/health")
-}
+// RegisterUserRoutes registers the account and session routes of v1 and v2
+func RegisterUserRoutes(r *gin.Engine, c UserController) {
+	r.GET("/", c.Index)
 
-func (c *userController) Healthcheck(context *gin.Context) {
-	//Build response if success
-	result := helper.Response{
-		Status:  true,
-		Message: "ok! check documentation at https://github.com/armiariyan/attendances_system",
-		Errors:  "null",
-		Data:    "null",
+	v1Routes := r.Group("api/")
+	{
+		v1Routes.GET("/check/health", c.Healthcheck)
+		v1Routes.POST("/register", deprecated("/api/v2/users"), c.Register)
+		v1Routes.POST("/login", deprecated("/api/v2/sessions"), c.Login)
+		v1Routes.POST("/logout", deprecated("/api/v2/sessions"), c.Logout)
 	}
 
-	response := helper.BuildResponse(true, "healthcheck successfull", result)
-	context.JSON(http.StatusOK, response)
-}
-
-func (c *userController) CheckIn(context *gin.Context) {
-
-	// Take id from parameter and convert to int
-	user_id, errConv := strconv.Atoi(context.Param("id"))
-	if errConv != nil {
-		response := helper.BuildErrorResponse("Failed to process request", errConv.Error(), helper.EmptyObj{})
-		context.AbortWithStatusJSON(http.StatusBadRequest, response)
-		return
+	v2Routes := r.Group("api/v2")
+	{
+		v2Routes.POST("/users", c.Register)
+		v2Routes.POST("/sessions", c.Login)
+		v2Routes.DELETE("/sessions", c.Logout)
+		v2Routes.GET("/me", c.GetMe)
 	}
+}
 
-	// Check if user exist and logged in using session
-	session := sessions.Default(context)
-	if !helper.IsLogin(session.Get("loggedIn")) {
-		response := helper.BuildErrorResponse("Failed to process request", "Please login first!", helper.EmptyObj{})
-		context.AbortWithStatusJSON(http.StatusUnauthorized, response)
-		return
-	}
+func (c *userController) Index(context *gin.Context) {
+	context.Redirect(http.StatusFound, "/api/check/health")
+}
 
-	// Check if user authorized to access data
-	if !helper.IsAuthorize(session.Get("user_id"), user_id) {
-		response := helper.BuildErrorResponse("Failed to process request", "Unauthorized!", helper.EmptyObj{})
-		context.AbortWithStatusJSON(http.StatusForbidden, response)
+func (c *userController) Healthcheck(context *gin.Context) {
+	// Load balancers stop sending requests while the server drains them
+	if !c.readiness.Ready() {
+		response := helper.BuildErrorResponse(helper.CodeUnavailable, "Failed to process request", "Server is shutting down", helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusServiceUnavailable, response)
 		return
 	}
 
-	// // Take user id from session
-	// i, ok := session.Get("user_id").(int)
-
-	// Make Checkin Data
-	checkInData := entity.Attendance{
-		Id:     helper.GenerateIdAttendance(),
-		UserId: user_id,
-		Label:  "check in",
-		Date:   time.Now().UnixMilli(),
-		Time:   time.Now().UnixMilli(),
-	}
-
-	// Checkin
-	result := helper.CreateAttendanceResponse(c.userService.CheckIn(checkInData))
-
-	//Build response if success
-	response := helper.BuildResponse(true, "Successfully Check In!", result)
+	// Only tells the server is up, /readyz checks its dependencies
+	response := helper.BuildResponse(true, "ok! check documentation at /api/docs", helper.EmptyObj{})
 	context.JSON(http.StatusOK, response)
 }
 
@@ -107,26 +78,23 @@ func (c *userController) Login(context *gin.Context) {
 	// Fill loginDTO variable
 	errDTO := context.ShouldBind(&loginDTO)
 	if errDTO != nil {
-		response := helper.BuildErrorResponse("Failed to process request", errDTO.Error(), helper.EmptyObj{})
+		response := helper.BuildValidationErrorResponse(errDTO, helper.EmptyObj{})
 		context.AbortWithStatusJSON(http.StatusBadRequest, response)
 		return
 	}
 
 	// Verify the data exist
-	tmpResult := c.userService.VerifyCredential(loginDTO.Email)
-	if tmpResult == nil {
-		// Build response error
-		response := helper.BuildErrorResponse("Failed to process request", "Invalid email or password", helper.EmptyObj{})
-		context.AbortWithStatusJSON(http.StatusUnauthorized, response)
+	entityResult, err := c.userService.VerifyCredential(context, loginDTO.Email)
+	if err != nil && !errors.Is(err, service.ErrNotFound) {
+		abortWithError(context, err, http.StatusInternalServerError, helper.CodeInternal)
 		return
 	}
 
-	// Take the entity result because tmpResult is interface{}
-	entityResult := tmpResult.(entity.User)
-
-	// Check if password match
-	if !helper.ComparePassword(entityResult.Password, []byte(loginDTO.Password)) {
-		response := helper.BuildErrorResponse("Failed to process request", "Invalid email or password", helper.EmptyObj{})
+	// Check if password match, an unknown email fails the same way
+	if err != nil || !helper.ComparePassword(entityResult.Password, []byte(loginDTO.Password)) {
+		logger.For(context).Warn("login failed")
+		metrics.FailedLogins.Inc()
+		response := helper.BuildErrorResponse(helper.CodeInvalidCredentials, "Failed to process request", "Invalid email or password", helper.EmptyObj{})
 		context.AbortWithStatusJSON(http.StatusUnauthorized, response)
 		return
 	}
@@ -138,7 +106,7 @@ func (c *userController) Login(context *gin.Context) {
 	session.Set("user_id", entityResult.Id)
 	session.Set("name", entityResult.Name)
 	session.Set("email", entityResult.Email)
-	session.Options(sessions.Options{MaxAge: 86400}) // Set session for one day (value in seconds)
+	session.Options(sessions.Options{Path: "/", MaxAge: 86400}) // Set session for one day (value in seconds) on every route, v1 or v2
 	// Save session
 	session.Save()
 
@@ -153,14 +121,19 @@ func (c *userController) Register(context *gin.Context) {
 
 	errDTO := context.ShouldBind(&registerDTO)
 	if errDTO != nil {
-		response := helper.BuildErrorResponse("Failed to process request", errDTO.Error(), helper.EmptyObj{})
+		response := helper.BuildValidationErrorResponse(errDTO, helper.EmptyObj{})
 		context.AbortWithStatusJSON(http.StatusBadRequest, response)
 		return
 	}
 
 	// Check Duplicate Email
-	if c.userService.IsDuplicateEmail(registerDTO.Email) {
-		response := helper.BuildErrorResponse("Failed to process request", "Email has been used", helper.EmptyObj{})
+	isDuplicate, err := c.userService.IsDuplicateEmail(context, registerDTO.Email)
+	if err != nil {
+		abortWithError(context, err, http.StatusInternalServerError, helper.CodeInternal)
+		return
+	}
+	if isDuplicate {
+		response := helper.BuildErrorResponse(helper.CodeEmailTaken, "Failed to process request", "Email has been used", helper.EmptyObj{})
 		context.JSON(http.StatusConflict, response)
 		return
 	}
@@ -168,344 +141,31 @@ func (c *userController) Register(context *gin.Context) {
 	// Hash Password
 	registerDTO.Password = helper.HashAndSalt([]byte(registerDTO.Password))
 
-	// Create User
-	createdUser := c.userService.CreateUser(registerDTO)
-
-	//Build Response
-	response := helper.BuildResponse(true, "User Registered! Please Login", createdUser)
-	context.JSON(http.StatusCreated, response)
-}
-
-func (c *userController) CheckOut(context *gin.Context) {
-
-	// Take id from parameter and convert to int
-	user_id, errConv := strconv.Atoi(context.Param("id"))
-	if errConv != nil {
-		response := helper.BuildErrorResponse("Failed to process request", errConv.Error(), helper.EmptyObj{})
-		context.AbortWithStatusJSON(http.StatusBadRequest, response)
-	}
-
-	// Check if user exist and logged in using session
-	session := sessions.Default(context)
-	if !helper.IsLogin(session.Get("loggedIn")) {
-		response := helper.BuildErrorResponse("Failed to process request", "Please login first!", helper.EmptyObj{})
-		context.AbortWithStatusJSON(http.StatusUnauthorized, response)
+	// Create User, the email may have been taken since the check
+	createdUser, err := c.userService.CreateUser(context, registerDTO)
+	if err != nil {
+		abortWithError(context, err, http.StatusInternalServerError, helper.CodeInternal)
 		return
 	}
 
-	// Check if user authorized to access data
-	if !helper.IsAuthorize(session.Get("user_id"), user_id) {
-		response := helper.BuildErrorResponse("Failed to process request", "Unauthorized!", helper.EmptyObj{})
-		context.AbortWithStatusJSON(http.StatusForbidden, response)
-		return
-	}
-
-	// Cek if user already check in today
-	userAtd := c.userService.GetAttendancesHistory(user_id)
-	if !helper.IsCheckIn(userAtd) {
-		//Build response error because user not check in today
-		response := helper.BuildErrorResponse("Failed to process request", "You should check in first!", helper.EmptyObj{})
-		context.AbortWithStatusJSON(http.StatusForbidden, response)
-		return
-	}
-
-	// Make Checkout Data
-	checkOutData := entity.Attendance{
-		Id:     helper.GenerateIdAttendance(),
-		UserId: user_id,
-		Label:  "check out",
-		Date:   time.Now().UnixMilli(),
-		Time:   time.Now().UnixMilli(),
-	}
-
-	// Checkout
-	result := helper.CreateAttendanceResponse(c.userService.CheckIn(checkOutData))
-
-	//Build response if success
-	response := helper.BuildResponse(true, "Successfully Check Out!", result)
-	context.JSON(http.StatusOK, response)
-}
-
-func (c *userController) CreateActivity(context *gin.Context) {
-
-	// Take id from parameter and convert to int
-	user_id, errConv := strconv.Atoi(context.Param("id"))
-	if errConv != nil {
-		response := helper.BuildErrorResponse("Failed to process request", errConv.Error(), helper.EmptyObj{})
-		context.AbortWithStatusJSON(http.StatusBadRequest, response)
-	}
-
-	// Check if user exist and logged in using session
-	session := sessions.Default(context)
-	if !helper.IsLogin(session.Get("loggedIn")) {
-		response := helper.BuildErrorResponse("Failed to process request", "Please login first!", helper.EmptyObj{})
-		context.AbortWithStatusJSON(http.StatusUnauthorized, response)
-		return
-	}
-
-	// Check if user authorized to access data
-	if !helper.IsAuthorize(session.Get("user_id"), user_id) {
-		response := helper.BuildErrorResponse("Failed to process request", "Unauthorized!", helper.EmptyObj{})
-		context.AbortWithStatusJSON(http.StatusForbidden, response)
-		return
-	}
-
-	// Cek if user already check in today
-	userAtd := c.userService.GetAttendancesHistory(user_id)
-	if !helper.IsCheckIn(userAtd) {
-		//Build response error because user not check in today
-		response := helper.BuildErrorResponse("Failed to process request", "You should check in first!", helper.EmptyObj{})
-		context.AbortWithStatusJSON(http.StatusForbidden, response)
-		return
-	}
-
-	var createActivityData entity.Activity
-	// Fill the createActivityData
-	errDTO := context.ShouldBind(&createActivityData)
-	if errDTO != nil {
-		response := helper.BuildErrorResponse("Failed to process request", errDTO.Error(), helper.EmptyObj{})
-		context.AbortWithStatusJSON(http.StatusBadRequest, response)
-		return
-	}
-
-	// Create activity data
-	createActivityData = entity.Activity{
-		Id:          helper.GenerateIdActivity(),
-		UserId:      user_id,
-		Description: createActivityData.Description,
-		DateCreated: time.Now().UnixMilli(),
-		TimeCreated: time.Now().UnixMilli(),
-	}
-
-	// Create activity
-	result := helper.CreateActivityResponse(c.userService.CreateActivity(createActivityData))
-
-	//Build response if success
-	response := helper.BuildResponse(true, "Successfully Created Activity!", result)
-	context.JSON(http.StatusCreated, response)
-}
-
-func (c *userController) UpdateActivity(context *gin.Context) {
-
-	// Take id from parameter and convert to int
-	user_id, errConv := strconv.Atoi(context.Param("id"))
-	if errConv != nil {
-		response := helper.BuildErrorResponse("Failed to process request", errConv.Error(), helper.EmptyObj{})
-		context.AbortWithStatusJSON(http.StatusBadRequest, response)
-	}
-
-	// Check if user exist and logged in using session
-	session := sessions.Default(context)
-	if !helper.IsLogin(session.Get("loggedIn")) {
-		response := helper.BuildErrorResponse("Failed to process request", "Please login first!", helper.EmptyObj{})
-		context.AbortWithStatusJSON(http.StatusUnauthorized, response)
-		return
-	}
-
-	// Check if user authorized to access data
-	if !helper.IsAuthorize(session.Get("user_id"), user_id) {
-		response := helper.BuildErrorResponse("Failed to process request", "Unauthorized!", helper.EmptyObj{})
-		context.AbortWithStatusJSON(http.StatusForbidden, response)
-		return
-	}
-
-	// Cek if user already check in today
-	userAtd := c.userService.GetAttendancesHistory(user_id)
-	if !helper.IsCheckIn(userAtd) {
-		//Build response error because user not check in today
-		response := helper.BuildErrorResponse("Failed to process request", "You should check in first!", helper.EmptyObj{})
-		context.AbortWithStatusJSON(http.StatusForbidden, response)
-		return
-	}
-
-	// Get activity data
-	act_id := context.Param("id_activity")
-	actData := c.userService.GetActivityById(act_id)
-
-	// Check if activity data empty
-	if helper.IsActivityEmpty(actData) {
-		//Build response error because activity data empty
-		response := helper.BuildErrorResponse("Failed to process request", "Activity not found", helper.EmptyObj{})
-		context.AbortWithStatusJSON(http.StatusNotFound, response)
-		return
-	}
-
-	var updateActivityData entity.Activity
-	// Fill the updateActivityData
-	errDTO := context.ShouldBind(&updateActivityData)
-	if errDTO != nil {
-		response := helper.BuildErrorResponse("Failed to process request", errDTO.Error(), helper.EmptyObj{})
-		context.AbortWithStatusJSON(http.StatusBadRequest, response)
-		return
-	}
-
-	// Create new activity data
-	updateActivityData = entity.Activity{
-		Id:          actData.Id,
-		UserId:      actData.UserId,
-		Description: updateActivityData.Description,
-		DateCreated: actData.DateCreated,
-		TimeCreated: actData.TimeCreated,
-	}
-
-	// Create activity response
-	result := helper.CreateActivityResponse(c.userService.UpdateActivity(updateActivityData))
-
-	//Build response if success
-	response := helper.BuildResponse(true, "Successfully Update Activity!", result)
+	//Build Response
+	response := helper.BuildResponse(true, "User Registered! Please Login", createdUser)
 	context.JSON(http.StatusCreated, response)
 }
 
-func (c *userController) DeleteActivity(context *gin.Context) {
-	// Take id from parameter and convert to int
-	user_id, errConv := strconv.Atoi(context.Param("id"))
-	if errConv != nil {
-		response := helper.BuildErrorResponse("Failed to process request", errConv.Error(), helper.EmptyObj{})
-		context.AbortWithStatusJSON(http.StatusBadRequest, response)
-	}
-
-	// Check if user exist and logged in using session
-	session := sessions.Default(context)
-	if !helper.IsLogin(session.Get("loggedIn")) {
-		response := helper.BuildErrorResponse("Failed to process request", "Please login first!", helper.EmptyObj{})
-		context.AbortWithStatusJSON(http.StatusUnauthorized, response)
-		return
-	}
-
-	// Check if user authorized to access data
-	if !helper.IsAuthorize(session.Get("user_id"), user_id) {
-		response := helper.BuildErrorResponse("Failed to process request", "Unauthorized!", helper.EmptyObj{})
-		context.AbortWithStatusJSON(http.StatusForbidden, response)
-		return
-	}
-
-	// Cek if user already check in today
-	userAtd := c.userService.GetAttendancesHistory(user_id)
-	if !helper.IsCheckIn(userAtd) {
-		//Build response error because user not check in today
-		response := helper.BuildErrorResponse("Failed to process request", "You should check in first!", helper.EmptyObj{})
-		context.AbortWithStatusJSON(http.StatusForbidden, response)
-		return
-	}
-
-	// Get activity data
-	act_id := context.Param("id_activity")
-	actData := c.userService.GetActivityById(act_id)
-
-	// Check if activity data empty
-	if helper.IsActivityEmpty(actData) {
-		//Build response error because activity data empty
-		response := helper.BuildErrorResponse("Failed to process request", "Activity not found", helper.EmptyObj{})
-		context.AbortWithStatusJSON(http.StatusNotFound, response)
-		return
-	}
-
-	// Delete
-	c.userService.DeleteActivity(actData)
-
-	// Build response if success
-	res := helper.BuildResponse(true, "Activity deleted!", helper.EmptyObj{})
-	context.JSON(http.StatusOK, res)
-}
-
-func (c *userController) GetAttendancesHistory(context *gin.Context) {
-
-	// Take id from parameter and convert to int
-	user_id, errConv := strconv.Atoi(context.Param("id"))
-	if errConv != nil {
-		response := helper.BuildErrorResponse("Failed to process request", errConv.Error(), helper.EmptyObj{})
-		context.AbortWithStatusJSON(http.StatusBadRequest, response)
-	}
-
-	// Check if user exist and logged in using session
-	session := sessions.Default(context)
-	if !helper.IsLogin(session.Get("loggedIn")) {
-		response := helper.BuildErrorResponse("Failed to process request", "Please login first!", helper.EmptyObj{})
-		context.AbortWithStatusJSON(http.StatusUnauthorized, response)
-		return
-	}
-
-	// Check if user authorized to access data
-	if !helper.IsAuthorize(session.Get("user_id"), user_id) {
-		response := helper.BuildErrorResponse("Failed to process request", "Unauthorized!", helper.EmptyObj{})
-		context.AbortWithStatusJSON(http.StatusForbidden, response)
-		return
-	}
-
-	// Get attendances history
-	response := helper.CreateAttendanceResponses(c.userService.GetAttendancesHistory(user_id))
-	// If attendances history empty
-	if response == nil {
-		res := helper.BuildResponse(true, "Successfully get attendance history!", "attendances history is empty")
-		context.JSON(http.StatusOK, res)
-		return
-	}
-
-	// Build response if success
-	res := helper.BuildResponse(true, "Successfully get attendance history!", response)
-	context.JSON(http.StatusOK, res)
-}
-
-func (c *userController) GetActivityHistoryByDate(context *gin.Context) {
-	// Take id from parameter and convert to int
-	user_id, errConv := strconv.Atoi(context.Param("id"))
-	if errConv != nil {
-		response := helper.BuildErrorResponse("Failed to process request", errConv.Error(), helper.EmptyObj{})
-		context.AbortWithStatusJSON(http.StatusBadRequest, response)
-	}
-
-	// Check if user exist and logged in using session
-	session := sessions.Default(context)
-	if !helper.IsLogin(session.Get("loggedIn")) {
-		response := helper.BuildErrorResponse("Failed to process request", "Please login first!", helper.EmptyObj{})
-		context.AbortWithStatusJSON(http.StatusUnauthorized, response)
-		return
-	}
-
-	// Check if user authorized to access data
-	if !helper.IsAuthorize(session.Get("user_id"), user_id) {
-		response := helper.BuildErrorResponse("Failed to process request", "Unauthorized!", helper.EmptyObj{})
-		context.AbortWithStatusJSON(http.StatusForbidden, response)
-		return
-	}
-
-	// Take start date and end date from querry
-	startDate := helper.StringToUnixMilli(context.Query("startDate"))
-	endDate := helper.StringToUnixMilli(context.Query("endDate"))
-
-	// Get activity history
-	activities := c.userService.GetActivityHistoryByDate(startDate, endDate)
-
-	// Check if activity in range date input empty
-	// But this will return status No Content and no response were made
-	if helper.IsActivitiesEmpty(activities) {
-		fmt.Println("request succes but status no content, no response were made")
-		res := helper.BuildResponse(true, "Activities in that range date is empty", activities)
-		context.JSON(http.StatusNoContent, res)
-		return
-	}
-
-	// Create activity response
-	response := helper.CreateActivityResponses(activities)
-
-	// Build response if success
-	res := helper.BuildResponse(true, "Successfully get activity history!", response)
-	context.JSON(http.StatusOK, res)
-}
-
 func (c *userController) Logout(context *gin.Context) {
 
 	// Check if user exist and logged in using session
 	session := sessions.Default(context)
 	if !helper.IsLogin(session.Get("loggedIn")) {
-		response := helper.BuildErrorResponse("Failed to process request", "Please login first!", helper.EmptyObj{})
+		response := helper.BuildErrorResponse(helper.CodeAuthRequired, "Failed to process request", "Please login first!", helper.EmptyObj{})
 		context.AbortWithStatusJSON(http.StatusUnauthorized, response)
 		return
 	}
 
 	session.Set("user_id", "") // this will mark the session as "written" and hopefully remove the username
 	session.Clear()
-	session.Options(sessions.Options{MaxAge: -1}) // this sets the cookie with a MaxAge of 0
+	session.Options(sessions.Options{Path: "/", MaxAge: -1}) // this sets the cookie with a MaxAge of 0
 	session.Save()
 
 	//Build response if success
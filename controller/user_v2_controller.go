@@ -0,0 +1,41 @@
+package controller
+
+import (
+	"armiariyan/attendances-system/helper"
+	"armiariyan/attendances-system/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// v2 takes the user from the session instead of the path, answers a broken
+// rule with the status that fits it and always returns lists, even empty ones
+
+// v2Status is the status v2 answers a failed rule with
+func v2Status(err error) int {
+	switch err {
+	case errNotCheckedIn, errNotOnBreak, service.ErrKioskTokenReplayed:
+		return http.StatusConflict
+	case errActivityNotFound:
+		return http.StatusNotFound
+	default:
+		return http.StatusForbidden
+	}
+}
+
+func (c *userController) GetMe(context *gin.Context) {
+	user_id, ok := sessionUserId(context)
+	if !ok {
+		return
+	}
+
+	user, err := c.userService.GetUserById(context, user_id)
+	if err != nil {
+		abortWithError(context, err, http.StatusNotFound, helper.CodeUserNotFound)
+		return
+	}
+
+	// Build response if success
+	res := helper.BuildResponse(true, "Successfully get user!", user)
+	context.JSON(http.StatusOK, res)
+}
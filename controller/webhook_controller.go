@@ -0,0 +1,202 @@
+package controller
+
+import (
+	"armiariyan/attendances-system/dto"
+	"armiariyan/attendances-system/entity"
+	"armiariyan/attendances-system/helper"
+	"armiariyan/attendances-system/service"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookController interface {
+	GetWebhooks(context *gin.Context)
+	CreateWebhook(context *gin.Context)
+	DeleteWebhook(context *gin.Context)
+	GetDeliveries(context *gin.Context)
+	RetryDelivery(context *gin.Context)
+}
+
+type webhookController struct {
+	webhookService service.WebhookService
+	userService    service.UserService
+}
+
+func NewWebhookController(webhook service.WebhookService, user service.UserService) WebhookController {
+	return &webhookController{
+		webhookService: webhook,
+		userService:    user,
+	}
+}
+
+// RegisterWebhookRoutes registers the webhook and delivery routes
+func RegisterWebhookRoutes(r *gin.Engine, c WebhookController) {
+	webhookRoutes := r.Group("api/webhooks")
+	{
+		webhookRoutes.GET("", c.GetWebhooks)
+		webhookRoutes.POST("", c.CreateWebhook)
+		webhookRoutes.DELETE("/:id_webhook", c.DeleteWebhook)
+		webhookRoutes.GET("/:id_webhook/deliveries", c.GetDeliveries)
+		webhookRoutes.POST("/:id_webhook/deliveries/:id_delivery/retry", c.RetryDelivery)
+	}
+}
+
+func (c *webhookController) GetWebhooks(context *gin.Context) {
+	if !authorizeAdmin(context, c.userService) {
+		return
+	}
+
+	subscriptions, err := c.webhookService.GetSubscriptions(context)
+	if err != nil {
+		abortWithError(context, err, http.StatusInternalServerError, helper.CodeInternal)
+		return
+	}
+
+	// Build response if success
+	res := helper.BuildResponse(true, "Successfully get webhooks!", subscriptions)
+	context.JSON(http.StatusOK, res)
+}
+
+func (c *webhookController) CreateWebhook(context *gin.Context) {
+	if !authorizeAdmin(context, c.userService) {
+		return
+	}
+
+	var webhookDTO dto.WebhookDTO
+	// Fill webhookDTO variable
+	errDTO := context.ShouldBind(&webhookDTO)
+	if errDTO != nil {
+		response := helper.BuildValidationErrorResponse(errDTO, helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// The secret is only shown once, the subscriber needs it to verify signatures
+	subscription, err := c.webhookService.CreateSubscription(context, webhookDTO)
+	if err != nil {
+		abortWithError(context, err, http.StatusInternalServerError, helper.CodeInternal)
+		return
+	}
+	result := gin.H{
+		"id":          subscription.Id,
+		"url":         subscription.URL,
+		"secret":      subscription.Secret,
+		"event_types": subscription.EventTypes,
+		"active":      subscription.Active,
+		"created_at":  subscription.CreatedAt,
+	}
+
+	// Build response if success
+	res := helper.BuildResponse(true, "Successfully Created Webhook!", result)
+	context.JSON(http.StatusCreated, res)
+}
+
+func (c *webhookController) DeleteWebhook(context *gin.Context) {
+	if !authorizeAdmin(context, c.userService) {
+		return
+	}
+
+	subscription, ok := c.findWebhook(context)
+	if !ok {
+		return
+	}
+
+	// Delete
+	if err := c.webhookService.DeleteSubscription(context, subscription); err != nil {
+		abortWithError(context, err, http.StatusInternalServerError, helper.CodeInternal)
+		return
+	}
+
+	// Build response if success
+	res := helper.BuildResponse(true, "Webhook deleted!", helper.EmptyObj{})
+	context.JSON(http.StatusOK, res)
+}
+
+func (c *webhookController) GetDeliveries(context *gin.Context) {
+	if !authorizeAdmin(context, c.userService) {
+		return
+	}
+
+	subscription, ok := c.findWebhook(context)
+	if !ok {
+		return
+	}
+
+	deliveries, err := c.webhookService.GetDeliveries(context, subscription.Id)
+	if err != nil {
+		abortWithError(context, err, http.StatusInternalServerError, helper.CodeInternal)
+		return
+	}
+
+	// Build response if success
+	res := helper.BuildResponse(true, "Successfully get webhook deliveries!", deliveries)
+	context.JSON(http.StatusOK, res)
+}
+
+func (c *webhookController) RetryDelivery(context *gin.Context) {
+	if !authorizeAdmin(context, c.userService) {
+		return
+	}
+
+	subscription, ok := c.findWebhook(context)
+	if !ok {
+		return
+	}
+
+	// Take id from parameter and convert to int
+	delivery_id, errConv := strconv.Atoi(context.Param("id_delivery"))
+	if errConv != nil {
+		response := helper.BuildErrorResponse(helper.CodeInvalidParameter, "Failed to process request", errConv.Error(), helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// Check if delivery exist
+	delivery, err := c.webhookService.GetDeliveryById(context, delivery_id)
+	if err != nil && !errors.Is(err, service.ErrNotFound) {
+		abortWithError(context, err, http.StatusInternalServerError, helper.CodeInternal)
+		return
+	}
+	if delivery.Id == 0 || delivery.SubscriptionId != subscription.Id {
+		response := helper.BuildErrorResponse(helper.CodeDeliveryNotFound, "Failed to process request", "Delivery not found", helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusNotFound, response)
+		return
+	}
+
+	delivery, err = c.webhookService.RetryDelivery(context, delivery)
+	if err != nil {
+		abortWithError(context, err, http.StatusInternalServerError, helper.CodeInternal)
+		return
+	}
+
+	// Build response if success
+	res := helper.BuildResponse(true, "Successfully Queued Delivery!", delivery)
+	context.JSON(http.StatusOK, res)
+}
+
+// findWebhook takes the webhook from parameter and aborts when it doesn't exist
+func (c *webhookController) findWebhook(context *gin.Context) (subscription entity.WebhookSubscription, ok bool) {
+	// Take id from parameter and convert to int
+	subscription_id, errConv := strconv.Atoi(context.Param("id_webhook"))
+	if errConv != nil {
+		response := helper.BuildErrorResponse(helper.CodeInvalidParameter, "Failed to process request", errConv.Error(), helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// Check if webhook exist
+	subscription, err := c.webhookService.GetSubscriptionById(context, subscription_id)
+	if err != nil && !errors.Is(err, service.ErrNotFound) {
+		abortWithError(context, err, http.StatusInternalServerError, helper.CodeInternal)
+		return
+	}
+	if subscription.Id == 0 {
+		response := helper.BuildErrorResponse(helper.CodeWebhookNotFound, "Failed to process request", "Webhook not found", helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusNotFound, response)
+		return
+	}
+	return subscription, true
+}
@@ -0,0 +1,13 @@
+package docs
+
+import _ "embed"
+
+// OpenAPI is the OpenAPI 3 document of every route in controller.RegisterRoutes
+//
+//go:embed openapi.json
+var OpenAPI []byte
+
+// UI is a standalone page rendering OpenAPI, it loads the document from /api/openapi.json
+//
+//go:embed index.html
+var UI []byte
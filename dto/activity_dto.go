@@ -0,0 +1,5 @@
+package dto
+
+type ActivityDTO struct {
+	Description string `json:"description" form:"description" binding:"required,max=128"`
+}
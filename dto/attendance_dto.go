@@ -0,0 +1,12 @@
+package dto
+
+type CheckInDTO struct {
+	WorkMode string `json:"work_mode" form:"work_mode" binding:"omitempty,oneof=onsite remote client_site business_trip"`
+}
+
+// AttendanceDTO punches an attendance on v2, token checks in with the QR code of a kiosk
+type AttendanceDTO struct {
+	Label    string `json:"label" form:"label" binding:"required,oneof='check in' 'break start' 'break end' 'check out'"`
+	WorkMode string `json:"work_mode" form:"work_mode" binding:"omitempty,oneof=onsite remote client_site business_trip"`
+	Token    string `json:"token" form:"token"`
+}
@@ -0,0 +1,11 @@
+package dto
+
+type DepartmentDTO struct {
+	Name      string `json:"name" form:"name" binding:"required"`
+	ManagerId *int   `json:"id_manager" form:"id_manager"`
+	ParentId  *int   `json:"id_parent" form:"id_parent"`
+}
+
+type MoveUserDTO struct {
+	DepartmentId *int `json:"id_department" form:"id_department"`
+}
@@ -0,0 +1,33 @@
+package dto
+
+// PageDTO is the cursor pagination of history endpoints, cursor is the
+// next_cursor of the previous page
+type PageDTO struct {
+	Cursor string `json:"cursor" form:"cursor"`
+	Limit  int    `json:"limit" form:"limit" binding:"omitempty,min=1,max=200"`
+	Sort   string `json:"sort" form:"sort" binding:"omitempty,oneof=asc desc"`
+}
+
+// DateRangeDTO is the date range of history endpoints, start_date and end_date
+// are ISO 8601 dates or datetimes, range a relative range replacing both and tz
+// the time zone dates without offset are taken in
+type DateRangeDTO struct {
+	StartDate    string `json:"start_date" form:"start_date"`
+	EndDate      string `json:"end_date" form:"end_date"`
+	Range        string `json:"range" form:"range"`
+	TimeZone     string `json:"tz" form:"tz"`
+	EndExclusive bool   `json:"end_exclusive" form:"end_exclusive"`
+}
+
+type AttendanceQueryDTO struct {
+	PageDTO
+	DateRangeDTO
+	Label string `json:"label" form:"label" binding:"omitempty,oneof='check in' 'break start' 'break end' 'check out'"`
+	Query string `json:"q" form:"q" binding:"max=128"`
+}
+
+type ActivityQueryDTO struct {
+	PageDTO
+	DateRangeDTO
+	Query string `json:"q" form:"q" binding:"max=128"`
+}
@@ -0,0 +1,6 @@
+package dto
+
+type HolidayDTO struct {
+	Date string `json:"date" form:"date" binding:"required,datetime=2006-01-02"`
+	Name string `json:"name" form:"name" binding:"required"`
+}
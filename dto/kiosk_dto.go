@@ -0,0 +1,5 @@
+package dto
+
+type KioskCheckInDTO struct {
+	Token string `json:"token" form:"token" binding:"required"`
+}
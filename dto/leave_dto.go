@@ -0,0 +1,8 @@
+package dto
+
+type LeaveDTO struct {
+	Type      string `json:"type" form:"type" binding:"required,oneof=annual sick unpaid other"`
+	StartDate string `json:"start_date" form:"start_date" binding:"required,datetime=2006-01-02"`
+	EndDate   string `json:"end_date" form:"end_date" binding:"required,datetime=2006-01-02"`
+	Reason    string `json:"reason" form:"reason" binding:"max=255"`
+}
@@ -0,0 +1,6 @@
+package dto
+
+type CreateNetworkDTO struct {
+	Name string `json:"name" form:"name" binding:"required"`
+	CIDR string `json:"cidr" form:"cidr" binding:"required,cidr"`
+}
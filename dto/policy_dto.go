@@ -0,0 +1,5 @@
+package dto
+
+type WorkPolicyDTO struct {
+	MaxRemoteDaysPerWeek *int `json:"max_remote_days_per_week" form:"max_remote_days_per_week" binding:"required,min=0,max=7"`
+}
@@ -0,0 +1,7 @@
+package dto
+
+type WebhookDTO struct {
+	URL        string   `json:"url" form:"url" binding:"required,url"`
+	Secret     string   `json:"secret" form:"secret" binding:"omitempty,min=16"`
+	EventTypes []string `json:"event_types" form:"event_types" binding:"required,min=1,dive,oneof=attendance.checked_in attendance.checked_out activity.created activity.updated activity.deleted"`
+}
@@ -0,0 +1,10 @@
+package entity
+
+// Absence is a scheduled work day without check in, leave or holiday, Date is formatted as 2006-01-02
+type Absence struct {
+	Id         int    `gorm:"primary_key:auto_increment" json:"id"`
+	UserId     int    `gorm:"uniqueIndex:idx_absence_user_date" json:"id_user"`
+	Date       string `gorm:"type:varchar(10);uniqueIndex:idx_absence_user_date;index" json:"date"`
+	DetectedAt int64  `json:"detected_at"`
+	User       User   `gorm:"foreignKey:UserId" json:"-"`
+}
@@ -1,10 +1,34 @@
 package entity
 
 type Attendance struct {
-	Id     string `gorm:"primaryKey;type:varchar(128)" json:"id"`
-	UserId int    `json:"id_user"`
-	Label  string `gorm:"type:varchar(128)" json:"label"`
-	Date   int64  `json:"date"`
-	Time   int64  `json:"time"`
-	User   User   `gorm:"foreignKey:UserId" json:"-"`
+	Id       string `gorm:"primaryKey;type:varchar(128)" json:"id"`
+	UserId   int    `gorm:"index:idx_attendance_user_date" json:"id_user"`
+	Label    string `gorm:"type:varchar(128)" json:"label"`
+	Location string `gorm:"type:varchar(16)" json:"location"`
+	WorkMode string `gorm:"type:varchar(32)" json:"work_mode"`
+	Date     int64  `gorm:"index:idx_attendance_user_date" json:"date"`
+	Time     int64  `json:"time"`
+	User     User   `gorm:"foreignKey:UserId" json:"-"`
 }
+
+const (
+	LabelCheckIn    = "check in"
+	LabelBreakStart = "break start"
+	LabelBreakEnd   = "break end"
+	LabelCheckOut   = "check out"
+)
+
+const (
+	LocationOnsite = "onsite"
+	LocationRemote = "remote"
+)
+
+const (
+	WorkModeOnsite       = "onsite"
+	WorkModeRemote       = "remote"
+	WorkModeClientSite   = "client_site"
+	WorkModeBusinessTrip = "business_trip"
+)
+
+// WorkModes lists every work mode in reporting order
+var WorkModes = []string{WorkModeOnsite, WorkModeRemote, WorkModeClientSite, WorkModeBusinessTrip}
@@ -0,0 +1,10 @@
+package entity
+
+type Department struct {
+	Id        int         `gorm:"primary_key:auto_increment" json:"id"`
+	Name      string      `gorm:"type:varchar(128)" json:"name"`
+	ManagerId *int        `json:"id_manager"`
+	ParentId  *int        `json:"id_parent"`
+	Manager   *User       `gorm:"foreignKey:ManagerId" json:"-"`
+	Parent    *Department `gorm:"foreignKey:ParentId" json:"-"`
+}
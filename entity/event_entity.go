@@ -0,0 +1,32 @@
+package entity
+
+// OutboxEvent is a domain event saved in the same transaction as the change
+// that caused it, the dispatcher hands it to the subscribers afterwards
+type OutboxEvent struct {
+	Id           int    `gorm:"primary_key:auto_increment" json:"id"`
+	EventId      string `gorm:"type:varchar(64);uniqueIndex" json:"id_event"`
+	Type         string `gorm:"type:varchar(64);index" json:"type"`
+	Payload      string `gorm:"type:text" json:"payload"`
+	Status       string `gorm:"type:varchar(16);index" json:"status"`
+	Attempts     int    `json:"attempts"`
+	LastError    string `gorm:"type:varchar(512)" json:"last_error"`
+	OccurredAt   int64  `json:"occurred_at"`
+	DispatchedAt int64  `json:"dispatched_at"`
+}
+
+const (
+	OutboxPending    = "pending"
+	OutboxDispatched = "dispatched"
+	OutboxFailed     = "failed"
+)
+
+const (
+	EventUserRegistered  = "user.registered"
+	EventCheckedIn       = "attendance.checked_in"
+	EventBreakStarted    = "attendance.break_started"
+	EventBreakEnded      = "attendance.break_ended"
+	EventCheckedOut      = "attendance.checked_out"
+	EventActivityCreated = "activity.created"
+	EventActivityUpdated = "activity.updated"
+	EventActivityDeleted = "activity.deleted"
+)
@@ -0,0 +1,8 @@
+package entity
+
+// Holiday is a public holiday, Date is formatted as 2006-01-02
+type Holiday struct {
+	Id   int    `gorm:"primary_key:auto_increment" json:"id"`
+	Date string `gorm:"type:varchar(10);uniqueIndex" json:"date"`
+	Name string `gorm:"type:varchar(128)" json:"name"`
+}
@@ -0,0 +1,9 @@
+package entity
+
+// KioskToken records a qr token that has been used to check in, so it can't be replayed
+type KioskToken struct {
+	Nonce  string `gorm:"primaryKey;type:varchar(64)" json:"nonce"`
+	UserId int    `gorm:"primaryKey;autoIncrement:false" json:"id_user"`
+	UsedAt int64  `gorm:"index" json:"used_at"`
+	User   User   `gorm:"foreignKey:UserId" json:"-"`
+}
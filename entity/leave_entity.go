@@ -0,0 +1,20 @@
+package entity
+
+// Leave covers whole days from StartDate until EndDate, dates are formatted as 2006-01-02
+type Leave struct {
+	Id         int    `gorm:"primary_key:auto_increment" json:"id"`
+	UserId     int    `gorm:"index" json:"id_user"`
+	Type       string `gorm:"type:varchar(32)" json:"type"`
+	StartDate  string `gorm:"type:varchar(10);index" json:"start_date"`
+	EndDate    string `gorm:"type:varchar(10);index" json:"end_date"`
+	Reason     string `gorm:"type:varchar(255)" json:"reason"`
+	Status     string `gorm:"type:varchar(16);default:pending" json:"status"`
+	ReviewerId *int   `json:"id_reviewer"`
+	User       User   `gorm:"foreignKey:UserId" json:"-"`
+}
+
+const (
+	LeaveStatusPending  = "pending"
+	LeaveStatusApproved = "approved"
+	LeaveStatusRejected = "rejected"
+)
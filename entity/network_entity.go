@@ -0,0 +1,7 @@
+package entity
+
+type OfficeNetwork struct {
+	Id   int    `gorm:"primary_key:auto_increment" json:"id"`
+	Name string `gorm:"type:varchar(128)" json:"name"`
+	CIDR string `gorm:"type:varchar(64);uniqueIndex" json:"cidr"`
+}
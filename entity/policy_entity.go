@@ -0,0 +1,8 @@
+package entity
+
+// WorkPolicy limits how a user may work, a user without policy has no limit
+type WorkPolicy struct {
+	UserId               int  `gorm:"primaryKey;autoIncrement:false" json:"id_user"`
+	MaxRemoteDaysPerWeek int  `json:"max_remote_days_per_week"`
+	User                 User `gorm:"foreignKey:UserId" json:"-"`
+}
@@ -1,10 +1,18 @@
 package entity
 
 type User struct {
-	Id         int          `gorm:"primary_key:auto_increment" json:"id"`
-	Name       string       `gorm:"type:varchar(128)" json:"name"`
-	Email      string       `gorm:"type:varchar(128)" json:"email"`
-	Password   string       `gorm:"type:varchar(255)" json:"-"`
-	Activity   []Activity   `json:"-"`
-	Attendance []Attendance `json:"-"`
+	Id           int          `gorm:"primary_key:auto_increment" json:"id"`
+	Name         string       `gorm:"type:varchar(128)" json:"name"`
+	Email        string       `gorm:"type:varchar(128)" json:"email"`
+	Password     string       `gorm:"type:varchar(255)" json:"-"`
+	Role         string       `gorm:"type:varchar(32);default:employee" json:"role"`
+	DepartmentId *int         `json:"id_department"`
+	CreatedAt    int64        `gorm:"autoCreateTime:milli" json:"-"`
+	Activity     []Activity   `json:"-"`
+	Attendance   []Attendance `json:"-"`
 }
+
+const (
+	RoleEmployee = "employee"
+	RoleAdmin    = "admin"
+)
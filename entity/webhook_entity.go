@@ -0,0 +1,35 @@
+package entity
+
+type WebhookSubscription struct {
+	Id         int    `gorm:"primary_key:auto_increment" json:"id"`
+	URL        string `gorm:"type:varchar(512)" json:"url"`
+	Secret     string `gorm:"type:varchar(128)" json:"-"`
+	EventTypes string `gorm:"type:varchar(512)" json:"event_types"`
+	Active     bool   `json:"active"`
+	CreatedAt  int64  `gorm:"autoCreateTime:milli" json:"created_at"`
+}
+
+type WebhookDelivery struct {
+	Id             int                 `gorm:"primary_key:auto_increment" json:"id"`
+	SubscriptionId int                 `gorm:"uniqueIndex:idx_delivery_subscription_event" json:"id_webhook"`
+	EventId        string              `gorm:"type:varchar(64);uniqueIndex:idx_delivery_subscription_event" json:"id_event"`
+	EventType      string              `gorm:"type:varchar(64)" json:"event_type"`
+	Payload        string              `gorm:"type:text" json:"payload"`
+	Status         string              `gorm:"type:varchar(16);index" json:"status"`
+	Attempts       int                 `json:"attempts"`
+	NextAttemptAt  int64               `gorm:"index" json:"next_attempt_at"`
+	ResponseStatus int                 `json:"response_status"`
+	LastError      string              `gorm:"type:varchar(512)" json:"last_error"`
+	CreatedAt      int64               `gorm:"autoCreateTime:milli" json:"created_at"`
+	UpdatedAt      int64               `gorm:"autoUpdateTime:milli" json:"updated_at"`
+	Subscription   WebhookSubscription `gorm:"foreignKey:SubscriptionId" json:"-"`
+}
+
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryDead      = "dead"
+)
+
+// WebhookEvents lists the events a webhook can subscribe to
+var WebhookEvents = []string{EventCheckedIn, EventCheckedOut, EventActivityCreated, EventActivityUpdated, EventActivityDeleted}
@@ -0,0 +1,32 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+// WriteCSV writes the rows with a header line in the order of PayrollColumns
+func WriteCSV(w io.Writer, rows []PayrollRow) error {
+	writer := csv.NewWriter(w)
+
+	header := make([]string, len(PayrollColumns))
+	for i, column := range PayrollColumns {
+		header[i] = column.Name
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		record := make([]string, len(PayrollColumns))
+		for i, column := range PayrollColumns {
+			record[i] = column.Value(row)
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
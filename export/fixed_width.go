@@ -0,0 +1,134 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// FixedWidthField places a payroll column at a fixed width
+type FixedWidthField struct {
+	Column string `json:"column"`
+	Width  int    `json:"width"`
+	Align  string `json:"align"`
+	Pad    string `json:"pad"`
+}
+
+// FixedWidthLayout is the configurable shape of a fixed width payroll file
+type FixedWidthLayout struct {
+	Fields     []FixedWidthField `json:"fields"`
+	Header     bool              `json:"header"`
+	LineEnding string            `json:"line_ending"`
+}
+
+// DefaultFixedWidthLayout is used when no layout file is configured
+var DefaultFixedWidthLayout = FixedWidthLayout{
+	Fields: []FixedWidthField{
+		{Column: "employee_id", Width: 10, Align: "right", Pad: "0"},
+		{Column: "regular_hours", Width: 8, Align: "right", Pad: "0"},
+		{Column: "overtime_hours", Width: 8, Align: "right", Pad: "0"},
+		{Column: "leave_days", Width: 4, Align: "right", Pad: "0"},
+		{Column: "absences", Width: 4, Align: "right", Pad: "0"},
+	},
+	Header:     false,
+	LineEnding: "\r\n",
+}
+
+// LoadFixedWidthLayout reads a json layout, an empty path returns the default layout
+func LoadFixedWidthLayout(path string) (FixedWidthLayout, error) {
+	if path == "" {
+		return DefaultFixedWidthLayout, nil
+	}
+
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return FixedWidthLayout{}, err
+	}
+
+	layout := FixedWidthLayout{LineEnding: DefaultFixedWidthLayout.LineEnding}
+	if err := json.Unmarshal(file, &layout); err != nil {
+		return FixedWidthLayout{}, fmt.Errorf("invalid fixed width layout %s: %w", path, err)
+	}
+	return layout, layout.Validate()
+}
+
+// Validate checks that every field points to a known column with a usable width
+func (layout FixedWidthLayout) Validate() error {
+	if len(layout.Fields) == 0 {
+		return fmt.Errorf("fixed width layout has no fields")
+	}
+	for _, field := range layout.Fields {
+		if _, ok := ColumnByName(field.Column); !ok {
+			return fmt.Errorf("fixed width layout has unknown column %q", field.Column)
+		}
+		if field.Width <= 0 {
+			return fmt.Errorf("fixed width column %q must have a positive width", field.Column)
+		}
+		if field.Align != "" && field.Align != "left" && field.Align != "right" {
+			return fmt.Errorf("fixed width column %q must align left or right", field.Column)
+		}
+		if utf8.RuneCountInString(field.Pad) > 1 {
+			return fmt.Errorf("fixed width column %q must pad with a single character", field.Column)
+		}
+	}
+	return nil
+}
+
+// WriteFixedWidth writes the rows using the layout, a value longer than its width is
+// an error because truncating payroll figures would silently pay the wrong amount
+func WriteFixedWidth(w io.Writer, rows []PayrollRow, layout FixedWidthLayout) error {
+	if err := layout.Validate(); err != nil {
+		return err
+	}
+
+	if layout.Header {
+		// Column names are only a hint for humans, so they are cut to fit
+		var line strings.Builder
+		for _, field := range layout.Fields {
+			name := []rune(field.Column)
+			if len(name) > field.Width {
+				name = name[:field.Width]
+			}
+			value, _ := fit(string(name), FixedWidthField{Width: field.Width, Align: "left"}, " ")
+			line.WriteString(value)
+		}
+		if _, err := io.WriteString(w, line.String()+layout.LineEnding); err != nil {
+			return err
+		}
+	}
+
+	for _, row := range rows {
+		var line strings.Builder
+		for _, field := range layout.Fields {
+			column, _ := ColumnByName(field.Column)
+			value, err := fit(column.Value(row), field, field.Pad)
+			if err != nil {
+				return fmt.Errorf("employee %d: %w", row.EmployeeId, err)
+			}
+			line.WriteString(value)
+		}
+		if _, err := io.WriteString(w, line.String()+layout.LineEnding); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func fit(value string, field FixedWidthField, pad string) (string, error) {
+	length := utf8.RuneCountInString(value)
+	if length > field.Width {
+		return "", fmt.Errorf("value %q of column %q is longer than %d characters", value, field.Column, field.Width)
+	}
+	if pad == "" {
+		pad = " "
+	}
+
+	padding := strings.Repeat(pad, field.Width-length)
+	if field.Align == "left" {
+		return value + padding, nil
+	}
+	return padding + value, nil
+}
@@ -0,0 +1,44 @@
+package export
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// PayrollRow is one employee line of the payroll file
+type PayrollRow struct {
+	EmployeeId    int
+	RegularHours  float64
+	OvertimeHours float64
+	LeaveDays     int
+	Absences      int
+}
+
+// Column describes one field of the payroll file, the order of PayrollColumns is the
+// order of the file and must stay stable because the payroll vendor parses by position
+type Column struct {
+	Name  string
+	Value func(row PayrollRow) string
+}
+
+var PayrollColumns = []Column{
+	{Name: "employee_id", Value: func(row PayrollRow) string { return strconv.Itoa(row.EmployeeId) }},
+	{Name: "regular_hours", Value: func(row PayrollRow) string { return formatHours(row.RegularHours) }},
+	{Name: "overtime_hours", Value: func(row PayrollRow) string { return formatHours(row.OvertimeHours) }},
+	{Name: "leave_days", Value: func(row PayrollRow) string { return strconv.Itoa(row.LeaveDays) }},
+	{Name: "absences", Value: func(row PayrollRow) string { return strconv.Itoa(row.Absences) }},
+}
+
+// ColumnByName returns the payroll column with the given name
+func ColumnByName(name string) (Column, bool) {
+	for _, column := range PayrollColumns {
+		if column.Name == name {
+			return column, true
+		}
+	}
+	return Column{}, false
+}
+
+func formatHours(hours float64) string {
+	return fmt.Sprintf("%.2f", hours)
+}
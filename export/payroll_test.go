@@ -0,0 +1,99 @@
+package export
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files")
+
+var payrollRows = []PayrollRow{
+	{EmployeeId: 1, RegularHours: 160, OvertimeHours: 12.5, LeaveDays: 2, Absences: 0},
+	{EmployeeId: 42, RegularHours: 151.25, OvertimeHours: 0, LeaveDays: 0, Absences: 1},
+	{EmployeeId: 1234567, RegularHours: 0, OvertimeHours: 0, LeaveDays: 20, Absences: 3},
+}
+
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read golden file: %v (run go test ./export -update to create it)", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s mismatch\ngot:\n%q\nwant:\n%q", name, got, want)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var out bytes.Buffer
+	if err := WriteCSV(&out, payrollRows); err != nil {
+		t.Fatal(err)
+	}
+	assertGolden(t, "payroll.csv.golden", out.Bytes())
+}
+
+func TestWriteCSVWithoutRows(t *testing.T) {
+	var out bytes.Buffer
+	if err := WriteCSV(&out, nil); err != nil {
+		t.Fatal(err)
+	}
+	assertGolden(t, "payroll_empty.csv.golden", out.Bytes())
+}
+
+func TestWriteFixedWidthDefaultLayout(t *testing.T) {
+	var out bytes.Buffer
+	if err := WriteFixedWidth(&out, payrollRows, DefaultFixedWidthLayout); err != nil {
+		t.Fatal(err)
+	}
+	assertGolden(t, "payroll_default.txt.golden", out.Bytes())
+}
+
+func TestWriteFixedWidthCustomLayout(t *testing.T) {
+	layout, err := LoadFixedWidthLayout(filepath.Join("testdata", "layout_custom.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := WriteFixedWidth(&out, payrollRows, layout); err != nil {
+		t.Fatal(err)
+	}
+	assertGolden(t, "payroll_custom.txt.golden", out.Bytes())
+}
+
+func TestWriteFixedWidthOverflow(t *testing.T) {
+	layout := FixedWidthLayout{
+		Fields:     []FixedWidthField{{Column: "employee_id", Width: 3}},
+		LineEnding: "\n",
+	}
+
+	var out bytes.Buffer
+	if err := WriteFixedWidth(&out, payrollRows, layout); err == nil {
+		t.Fatal("expected an error for an employee id wider than its column")
+	}
+}
+
+func TestFixedWidthLayoutValidate(t *testing.T) {
+	layouts := map[string]FixedWidthLayout{
+		"no fields":      {},
+		"unknown column": {Fields: []FixedWidthField{{Column: "salary", Width: 5}}},
+		"zero width":     {Fields: []FixedWidthField{{Column: "absences"}}},
+		"bad align":      {Fields: []FixedWidthField{{Column: "absences", Width: 2, Align: "center"}}},
+		"long pad":       {Fields: []FixedWidthField{{Column: "absences", Width: 2, Pad: "ab"}}},
+	}
+	for name, layout := range layouts {
+		if err := layout.Validate(); err == nil {
+			t.Errorf("%s: expected validation error", name)
+		}
+	}
+}
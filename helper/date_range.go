@@ -0,0 +1,195 @@
+package helper
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	// tz keeps working on hosts without a zoneinfo database
+	_ "time/tzdata"
+)
+
+// Relative ranges taken instead of start and end dates
+const (
+	RangeToday     = "today"
+	RangeYesterday = "yesterday"
+	RangeThisWeek  = "this-week"
+	RangeLastWeek  = "last-week"
+	RangeThisMonth = "this-month"
+	RangeLastMonth = "last-month"
+)
+
+// ErrInvalidDate is wrapped by every DateError
+var ErrInvalidDate = errors.New("invalid date")
+
+// DateError tells which parameter of a range couldn't be used and why
+type DateError struct {
+	Field  string
+	Value  string
+	Reason string
+}
+
+func (e *DateError) Error() string {
+	if e.Value == "" {
+		return fmt.Sprintf("%s %s", e.Field, e.Reason)
+	}
+	return fmt.Sprintf("%s %q %s", e.Field, e.Value, e.Reason)
+}
+
+func (e *DateError) Unwrap() error {
+	return ErrInvalidDate
+}
+
+// RangeQuery is a date range as clients send it, either start and end or a relative range.
+// Start is inclusive, end is inclusive too unless EndExclusive is set
+type RangeQuery struct {
+	Start        string
+	End          string
+	Range        string
+	TimeZone     string
+	EndExclusive bool
+}
+
+// TimeRange runs from From included until To excluded, a zero end leaves that side open
+type TimeRange struct {
+	From time.Time
+	To   time.Time
+}
+
+// dateLayouts are the ISO 8601 forms a range bound may take, with how much time
+// the bound spans when an inclusive end includes all of it. Seconds may carry a
+// fraction, which narrows the span to a millisecond
+var dateLayouts = []struct {
+	layout string
+	span   time.Duration
+}{
+	{"2006-01-02", 24 * time.Hour},
+	{"2006-01-02T15:04", time.Minute},
+	{"2006-01-02T15:04:05", time.Second},
+	{"2006-01-02T15:04Z07:00", time.Minute},
+	{"2006-01-02T15:04:05Z07:00", time.Second},
+}
+
+// ParseTimeRange turns the query into the range it means at now. Dates and datetimes
+// without offset are taken in the time zone of the query, or the server's by default.
+// An inclusive end date covers the whole day, an inclusive end datetime the whole
+// minute or second it names
+func ParseTimeRange(query RangeQuery, now time.Time) (TimeRange, error) {
+	location := time.Local
+	if query.TimeZone != "" {
+		var err error
+		if location, err = time.LoadLocation(query.TimeZone); err != nil {
+			return TimeRange{}, &DateError{Field: "tz", Value: query.TimeZone, Reason: "is not a known time zone like Asia/Jakarta or UTC"}
+		}
+	}
+
+	if query.Range != "" {
+		if query.Start != "" || query.End != "" {
+			return TimeRange{}, &DateError{Field: "range", Reason: "can't be combined with start_date or end_date"}
+		}
+		return relativeRange(query.Range, now.In(location))
+	}
+
+	var timeRange TimeRange
+	if query.Start != "" {
+		start, _, err := parseBound("start_date", query.Start, location)
+		if err != nil {
+			return TimeRange{}, err
+		}
+		timeRange.From = start
+	}
+	if query.End != "" {
+		end, span, err := parseBound("end_date", query.End, location)
+		if err != nil {
+			return TimeRange{}, err
+		}
+		if !query.EndExclusive {
+			end = addSpan(end, span)
+		}
+		timeRange.To = end
+	}
+	if !timeRange.From.IsZero() && !timeRange.To.IsZero() && !timeRange.To.After(timeRange.From) {
+		return TimeRange{}, ErrInvalidDateRange
+	}
+	return timeRange, nil
+}
+
+// ParseClosedTimeRange is ParseTimeRange for the reports and exports, which need
+// both ends of the range from start and end or from a relative range
+func ParseClosedTimeRange(query RangeQuery, now time.Time) (TimeRange, error) {
+	timeRange, err := ParseTimeRange(query, now)
+	if err != nil {
+		return TimeRange{}, err
+	}
+	if timeRange.From.IsZero() {
+		return TimeRange{}, &DateError{Field: "start_date", Reason: "is required unless range is given"}
+	}
+	if timeRange.To.IsZero() {
+		return TimeRange{}, &DateError{Field: "end_date", Reason: "is required unless range is given"}
+	}
+	return timeRange, nil
+}
+
+// UnixMilli returns the range as the inclusive unix milli bounds the repositories
+// filter on, zero for an open side
+func (timeRange TimeRange) UnixMilli() (int64, int64) {
+	var start, end int64
+	if !timeRange.From.IsZero() {
+		start = timeRange.From.UnixMilli()
+	}
+	if !timeRange.To.IsZero() {
+		end = timeRange.To.UnixMilli() - 1
+	}
+	return start, end
+}
+
+func parseBound(field, value string, location *time.Location) (time.Time, time.Duration, error) {
+	// A space stands for the T of a datetime, or for the + of an offset sent unescaped
+	normalized := value
+	if len(normalized) > 10 && normalized[10] == ' ' {
+		normalized = normalized[:10] + "T" + normalized[11:]
+	}
+	normalized = strings.Replace(normalized, " ", "+", 1)
+
+	for _, candidate := range dateLayouts {
+		if parsed, err := time.ParseInLocation(candidate.layout, normalized, location); err == nil {
+			if strings.Contains(normalized, ".") {
+				return parsed, time.Millisecond, nil
+			}
+			return parsed, candidate.span, nil
+		}
+	}
+	return time.Time{}, 0, &DateError{Field: field, Value: value, Reason: "must be an ISO 8601 date or datetime like 2022-07-01, 2022-07-01T08:00:00 or 2022-07-01T08:00:00+07:00"}
+}
+
+// addSpan adds a day as a calendar day so ranges keep whole days over daylight saving changes
+func addSpan(t time.Time, span time.Duration) time.Time {
+	if span == 24*time.Hour {
+		return t.AddDate(0, 0, 1)
+	}
+	return t.Add(span)
+}
+
+func relativeRange(name string, now time.Time) (TimeRange, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	week := StartOfWeek(now)
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+
+	switch name {
+	case RangeToday:
+		return TimeRange{From: today, To: today.AddDate(0, 0, 1)}, nil
+	case RangeYesterday:
+		return TimeRange{From: today.AddDate(0, 0, -1), To: today}, nil
+	case RangeThisWeek:
+		return TimeRange{From: week, To: week.AddDate(0, 0, 7)}, nil
+	case RangeLastWeek:
+		return TimeRange{From: week.AddDate(0, 0, -7), To: week}, nil
+	case RangeThisMonth:
+		return TimeRange{From: month, To: month.AddDate(0, 1, 0)}, nil
+	case RangeLastMonth:
+		return TimeRange{From: month.AddDate(0, -1, 0), To: month}, nil
+	default:
+		return TimeRange{}, &DateError{Field: "range", Value: name, Reason: "must be one of today, yesterday, this-week, last-week, this-month or last-month"}
+	}
+}
@@ -0,0 +1,128 @@
+package helper
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseTimeRange(t *testing.T) {
+	jakarta, _ := time.LoadLocation("Asia/Jakarta")
+	// A wednesday
+	now := time.Date(2022, time.July, 13, 10, 30, 0, 0, jakarta)
+	at := func(value string) time.Time {
+		parsed, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+
+	tests := []struct {
+		name  string
+		query RangeQuery
+		from  string
+		to    string
+	}{
+		{"open", RangeQuery{}, "", ""},
+		{"dates include the end day", RangeQuery{Start: "2022-07-01", End: "2022-07-01", TimeZone: "Asia/Jakarta"}, "2022-07-01T00:00:00+07:00", "2022-07-02T00:00:00+07:00"},
+		{"exclusive end date", RangeQuery{Start: "2022-07-01", End: "2022-07-03", TimeZone: "UTC", EndExclusive: true}, "2022-07-01T00:00:00Z", "2022-07-03T00:00:00Z"},
+		{"datetime end includes its second", RangeQuery{End: "2022-07-01T17:00:00", TimeZone: "UTC"}, "", "2022-07-01T17:00:01Z"},
+		{"datetime end includes its minute", RangeQuery{End: "2022-07-01T17:00", TimeZone: "UTC"}, "", "2022-07-01T17:01:00Z"},
+		{"fraction narrows to a millisecond", RangeQuery{End: "2022-07-01T17:00:00.250Z"}, "", "2022-07-01T17:00:00.251Z"},
+		{"offset wins over tz", RangeQuery{Start: "2022-07-01T08:00:00+07:00", TimeZone: "UTC"}, "2022-07-01T01:00:00Z", ""},
+		{"unescaped plus", RangeQuery{Start: "2022-07-01T08:00:00 07:00"}, "2022-07-01T01:00:00Z", ""},
+		{"space separator", RangeQuery{Start: "2022-07-01 08:00:00", TimeZone: "UTC"}, "2022-07-01T08:00:00Z", ""},
+		{"today", RangeQuery{Range: RangeToday, TimeZone: "Asia/Jakarta"}, "2022-07-13T00:00:00+07:00", "2022-07-14T00:00:00+07:00"},
+		{"yesterday", RangeQuery{Range: RangeYesterday, TimeZone: "Asia/Jakarta"}, "2022-07-12T00:00:00+07:00", "2022-07-13T00:00:00+07:00"},
+		{"this week starts on monday", RangeQuery{Range: RangeThisWeek, TimeZone: "Asia/Jakarta"}, "2022-07-11T00:00:00+07:00", "2022-07-18T00:00:00+07:00"},
+		{"last week", RangeQuery{Range: RangeLastWeek, TimeZone: "Asia/Jakarta"}, "2022-07-04T00:00:00+07:00", "2022-07-11T00:00:00+07:00"},
+		{"this month", RangeQuery{Range: RangeThisMonth, TimeZone: "Asia/Jakarta"}, "2022-07-01T00:00:00+07:00", "2022-08-01T00:00:00+07:00"},
+		{"last month", RangeQuery{Range: RangeLastMonth, TimeZone: "Asia/Jakarta"}, "2022-06-01T00:00:00+07:00", "2022-07-01T00:00:00+07:00"},
+		{"relative ranges follow tz", RangeQuery{Range: RangeToday, TimeZone: "UTC"}, "2022-07-13T00:00:00Z", "2022-07-14T00:00:00Z"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ParseTimeRange(test.query, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var from, to time.Time
+			if test.from != "" {
+				from = at(test.from)
+			}
+			if test.to != "" {
+				to = at(test.to)
+			}
+			if !got.From.Equal(from) || !got.To.Equal(to) {
+				t.Errorf("got [%v, %v), want [%v, %v)", got.From, got.To, from, to)
+			}
+		})
+	}
+}
+
+func TestParseTimeRangeRejects(t *testing.T) {
+	tests := []struct {
+		name  string
+		query RangeQuery
+		want  error
+		field string
+	}{
+		{"malformed start", RangeQuery{Start: "2022-13-01"}, ErrInvalidDate, "start_date"},
+		{"malformed end", RangeQuery{End: "yesterday"}, ErrInvalidDate, "end_date"},
+		{"unknown range", RangeQuery{Range: "next-week"}, ErrInvalidDate, "range"},
+		{"range with dates", RangeQuery{Range: RangeToday, Start: "2022-07-01"}, ErrInvalidDate, "range"},
+		{"unknown time zone", RangeQuery{TimeZone: "Mars/Olympus"}, ErrInvalidDate, "tz"},
+		{"end before start", RangeQuery{Start: "2022-07-02", End: "2022-07-01"}, ErrInvalidDateRange, ""},
+		{"empty exclusive range", RangeQuery{Start: "2022-07-01", End: "2022-07-01", EndExclusive: true}, ErrInvalidDateRange, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ParseTimeRange(test.query, time.Now())
+			if !errors.Is(err, test.want) {
+				t.Fatalf("err = %v, want %v", err, test.want)
+			}
+			var dateError *DateError
+			if test.field != "" && (!errors.As(err, &dateError) || dateError.Field != test.field) {
+				t.Errorf("err = %v, want it on %s", err, test.field)
+			}
+		})
+	}
+}
+
+func TestParseClosedTimeRange(t *testing.T) {
+	tests := []struct {
+		name  string
+		query RangeQuery
+		field string
+	}{
+		{"dates", RangeQuery{Start: "2022-07-01", End: "2022-07-31"}, ""},
+		{"relative range", RangeQuery{Range: RangeLastMonth}, ""},
+		{"open start", RangeQuery{End: "2022-07-31"}, "start_date"},
+		{"open end", RangeQuery{Start: "2022-07-01"}, "end_date"},
+		{"nothing", RangeQuery{}, "start_date"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ParseClosedTimeRange(test.query, time.Now())
+			var dateError *DateError
+			switch {
+			case test.field == "" && err != nil:
+				t.Errorf("err = %v, want none", err)
+			case test.field != "" && (!errors.As(err, &dateError) || dateError.Field != test.field):
+				t.Errorf("err = %v, want it on %s", err, test.field)
+			}
+		})
+	}
+}
+
+func TestTimeRangeUnixMilliIsInclusive(t *testing.T) {
+	from := time.Date(2022, time.July, 1, 0, 0, 0, 0, time.UTC)
+	start, end := TimeRange{From: from, To: from.AddDate(0, 0, 1)}.UnixMilli()
+	if start != from.UnixMilli() || end != from.AddDate(0, 0, 1).UnixMilli()-1 {
+		t.Errorf("got %d, %d", start, end)
+	}
+	if start, end := (TimeRange{}).UnixMilli(); start != 0 || end != 0 {
+		t.Errorf("open range got %d, %d", start, end)
+	}
+}
@@ -0,0 +1,154 @@
+package helper
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// ErrorCode is the stable code of a failed response, clients match on it
+// instead of on the message, which may be reworded any time
+type ErrorCode string
+
+// Catalogue of the error codes, the README lists what each one means
+const (
+	// Request
+	CodeValidationFailed ErrorCode = "VALIDATION_FAILED"
+	CodeInvalidParameter ErrorCode = "INVALID_PARAMETER"
+	CodeInvalidDate      ErrorCode = "INVALID_DATE"
+	CodeInvalidDateRange ErrorCode = "INVALID_DATE_RANGE"
+	CodeInvalidCursor    ErrorCode = "INVALID_CURSOR"
+	CodeRouteNotFound    ErrorCode = "ROUTE_NOT_FOUND"
+
+	// Authentication and authorization
+	CodeAuthRequired       ErrorCode = "AUTH_REQUIRED"
+	CodeInvalidCredentials ErrorCode = "INVALID_CREDENTIALS"
+	CodeForbidden          ErrorCode = "FORBIDDEN"
+	CodeKioskUnauthorized  ErrorCode = "KIOSK_UNAUTHORIZED"
+
+	// Attendance rules
+	CodeNotCheckedIn        ErrorCode = "NOT_CHECKED_IN"
+	CodeNotOnBreak          ErrorCode = "NOT_ON_BREAK"
+	CodeOnsiteOutsideOffice ErrorCode = "ONSITE_OUTSIDE_OFFICE"
+	CodeRemoteLimitReached  ErrorCode = "REMOTE_LIMIT_REACHED"
+	CodeKioskTokenInvalid   ErrorCode = "KIOSK_TOKEN_INVALID"
+	CodeKioskTokenExpired   ErrorCode = "KIOSK_TOKEN_EXPIRED"
+	CodeKioskTokenReplayed  ErrorCode = "KIOSK_TOKEN_REPLAYED"
+
+	// Not found
+	CodeUserNotFound       ErrorCode = "USER_NOT_FOUND"
+	CodeActivityNotFound   ErrorCode = "ACTIVITY_NOT_FOUND"
+	CodePolicyNotFound     ErrorCode = "POLICY_NOT_FOUND"
+	CodeNetworkNotFound    ErrorCode = "NETWORK_NOT_FOUND"
+	CodeDepartmentNotFound ErrorCode = "DEPARTMENT_NOT_FOUND"
+	CodeParentNotFound     ErrorCode = "PARENT_DEPARTMENT_NOT_FOUND"
+	CodeManagerNotFound    ErrorCode = "MANAGER_NOT_FOUND"
+	CodeLeaveNotFound      ErrorCode = "LEAVE_NOT_FOUND"
+	CodeHolidayNotFound    ErrorCode = "HOLIDAY_NOT_FOUND"
+	CodeWebhookNotFound    ErrorCode = "WEBHOOK_NOT_FOUND"
+	CodeDeliveryNotFound   ErrorCode = "DELIVERY_NOT_FOUND"
+
+	// Conflicts
+	CodeEmailTaken            ErrorCode = "EMAIL_TAKEN"
+	CodeNetworkExists         ErrorCode = "NETWORK_EXISTS"
+	CodeHolidayExists         ErrorCode = "HOLIDAY_EXISTS"
+	CodeLeaveReviewed         ErrorCode = "LEAVE_ALREADY_REVIEWED"
+	CodeDepartmentCycle       ErrorCode = "DEPARTMENT_CYCLE"
+	CodeDepartmentHasChildren ErrorCode = "DEPARTMENT_HAS_CHILDREN"
+	CodeConflict              ErrorCode = "CONFLICT"
+
+	// Server
+	CodeExportFailed ErrorCode = "EXPORT_FAILED"
+	CodeInternal     ErrorCode = "INTERNAL_ERROR"
+	CodeUnavailable  ErrorCode = "SERVICE_UNAVAILABLE"
+)
+
+// FieldError is the validation failure of one field of the request
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+// BuildValidationErrorResponse method is BuildErrorResponse with a detail for every field ShouldBind rejected
+func BuildValidationErrorResponse(err error, data interface{}) Response {
+	details := FieldErrors(err)
+	if len(details) == 0 {
+		return BuildErrorResponse(CodeValidationFailed, "Failed to process request", err.Error(), data)
+	}
+
+	messages := make([]string, len(details))
+	for i, detail := range details {
+		messages[i] = detail.Message
+	}
+	res := BuildErrorResponse(CodeValidationFailed, "Failed to process request", strings.Join(messages, "\n"), data)
+	res.Details = details
+	return res
+}
+
+// FieldErrors turns the error of ShouldBind into one FieldError per rejected field,
+// errors which don't come from a field, like a malformed body, have none
+func FieldErrors(err error) []FieldError {
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		details := make([]FieldError, len(validationErrors))
+		for i, fieldError := range validationErrors {
+			details[i] = FieldError{
+				Field:   fieldError.Field(),
+				Rule:    fieldError.Tag(),
+				Param:   fieldError.Param(),
+				Message: fieldMessage(fieldError),
+			}
+		}
+		return details
+	}
+
+	var typeError *json.UnmarshalTypeError
+	if errors.As(err, &typeError) && typeError.Field != "" {
+		return []FieldError{{
+			Field:   typeError.Field,
+			Rule:    "type",
+			Param:   typeError.Type.String(),
+			Message: fmt.Sprintf("%s must be a %s", typeError.Field, typeError.Type),
+		}}
+	}
+	return nil
+}
+
+func fieldMessage(fieldError validator.FieldError) string {
+	field, param := fieldError.Field(), fieldError.Param()
+	switch fieldError.Tag() {
+	case "required":
+		return field + " is required"
+	case "email":
+		return field + " must be a valid email address"
+	case "url":
+		return field + " must be a valid URL"
+	case "cidr":
+		return field + " must be a CIDR like 10.0.0.0/24"
+	case "datetime":
+		return field + " must be formatted as " + param
+	case "oneof":
+		return field + " must be one of " + param
+	case "min", "max":
+		bound := "at least "
+		if fieldError.Tag() == "max" {
+			bound = "at most "
+		}
+		switch fieldError.Kind() {
+		case reflect.String:
+			return field + " must be " + bound + param + " characters long"
+		case reflect.Slice, reflect.Array, reflect.Map:
+			return field + " must have " + bound + param + " items"
+		default:
+			return field + " must be " + bound + param
+		}
+	default:
+		return field + " failed on the " + fieldError.Tag() + " rule"
+	}
+}
@@ -2,8 +2,7 @@ package helper
 
 import (
 	"armiariyan/attendances-system/entity"
-	"fmt"
-	"log"
+	"math"
 	"math/rand"
 	"time"
 
@@ -14,8 +13,7 @@ import (
 func HashAndSalt(pwd []byte) string {
 	hash, err := bcrypt.GenerateFromPassword(pwd, bcrypt.MinCost)
 	if err != nil {
-		log.Println(err)
-		panic("Failed to hash a password")
+		panic("Failed to hash a password: " + err.Error())
 	}
 	return string(hash)
 }
@@ -25,11 +23,13 @@ func CreateAttendanceResponse(data entity.Attendance) ResponseAttendance {
 	stringTime := UnixMilliToString(data.Time, "time")
 
 	attendanceResponse := ResponseAttendance{
-		Id:     data.Id,
-		UserId: data.UserId,
-		Label:  data.Label,
-		Date:   stringDate,
-		Time:   stringTime,
+		Id:       data.Id,
+		UserId:   data.UserId,
+		Label:    data.Label,
+		Location: data.Location,
+		WorkMode: data.WorkMode,
+		Date:     stringDate,
+		Time:     stringTime,
 	}
 
 	return attendanceResponse
@@ -82,27 +82,15 @@ func UnixMilliToString(data int64, kind string) string {
 	}
 }
 
-func StringToUnixMilli(str string) int64 {
-	// Change to time
-	str = str + " 23:59:59"
-	timeDate, _ := time.Parse("2006-01-02 15:04:05", str)
-
-	// Change to unix mili
-	return timeDate.UnixMilli()
+// RoundHours rounds hours to two decimals for responses
+func RoundHours(hours float64) float64 {
+	return math.Round(hours*100) / 100
 }
 
-// Today start at 00.00, today end at 23.59
+// Today start at 00.00, today end at 23.59.59.999 local time
 func GenerateTodayUnixMilli() (result []int64) {
-	strTimeTodayStart := time.Now().Format("2006-01-02")
-	tmpTimeTodayStart, _ := time.Parse("2006-01-02", strTimeTodayStart)
-	result = append(result, tmpTimeTodayStart.UnixMilli())
-
-	strTimeTodayEnd := time.Now().Format("2006-01-02 15:04:05")
-	tmpTimeTodayEnd, _ := time.Parse("2006-01-02 15:04:05", strTimeTodayEnd)
-	result = append(result, tmpTimeTodayEnd.UnixMilli())
-
-	fmt.Println(strTimeTodayStart, strTimeTodayEnd)
-	return result
+	start, end := DayRange(time.Now())
+	return append(result, start, end)
 }
 
 func GenerateRandomString(n int) string {
@@ -147,6 +135,30 @@ func IsActivitiesEmpty(activities []entity.Activity) bool {
 	}
 }
 
+func IsNetworkEmpty(data entity.OfficeNetwork) bool {
+	if cmp.Equal(data, entity.OfficeNetwork{}) {
+		return true
+	} else {
+		return false
+	}
+}
+
+func IsDepartmentEmpty(data entity.Department) bool {
+	if cmp.Equal(data, entity.Department{}) {
+		return true
+	} else {
+		return false
+	}
+}
+
+func IsAdmin(data entity.User) bool {
+	if data.Role != entity.RoleAdmin {
+		return false
+	} else {
+		return true
+	}
+}
+
 func IsLogin(status interface{}) bool {
 	if status != true {
 		return false
@@ -163,26 +175,11 @@ func IsAuthorize(param_id interface{}, session_id int) bool {
 	}
 }
 
-func IsCheckIn(attendances []entity.Attendance) bool {
-	result := GenerateTodayUnixMilli()
-
-	// result[0] is start, [1] is end
-	for _, attendance := range attendances {
-		if attendance.Date >= result[0] && attendance.Date <= result[1] && attendance.Label == "check in" {
-			return true
-		}
-	}
-	return false
-}
-
 func ComparePassword(hashedPwd string, plainPassword []byte) bool {
 	byteHash := []byte(hashedPwd)
+	// A mismatch is no error worth logging, the login handler logs failed logins
 	err := bcrypt.CompareHashAndPassword(byteHash, plainPassword)
-	if err != nil {
-		log.Println(err)
-		return false
-	}
-	return true
+	return err == nil
 }
 
 // func IsAuthorize(token *jwt.Token, user_id int) bool {
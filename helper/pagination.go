@@ -0,0 +1,46 @@
+package helper
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+const (
+	DefaultPageLimit = 50
+	SortAsc          = "asc"
+	SortDesc         = "desc"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Pagination is the metadata of a cursor paginated response
+type Pagination struct {
+	Limit      int    `json:"limit"`
+	Sort       string `json:"sort"`
+	Count      int    `json:"count"`
+	HasMore    bool   `json:"has_more"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// EncodeCursor makes an opaque cursor pointing after the row with the given time and id
+func EncodeCursor(time int64, id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(time, 10) + ":" + id))
+}
+
+func DecodeCursor(cursor string) (int64, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, "", ErrInvalidCursor
+	}
+	parts := strings.SplitN(string(raw), ":", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return 0, "", ErrInvalidCursor
+	}
+	time, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, "", ErrInvalidCursor
+	}
+	return time, parts[1], nil
+}
@@ -4,18 +4,23 @@ import "strings"
 
 //Response is used for static shape json return
 type Response struct {
-	Status  bool        `json:"status"`
-	Message string      `json:"message"`
-	Errors  interface{} `json:"errors"`
-	Data    interface{} `json:"data"`
+	Status     bool         `json:"status"`
+	Message    string       `json:"message"`
+	Code       ErrorCode    `json:"code,omitempty"`
+	Errors     interface{}  `json:"errors"`
+	Details    []FieldError `json:"details,omitempty"`
+	Data       interface{}  `json:"data"`
+	Pagination *Pagination  `json:"pagination,omitempty"`
 }
 
 type ResponseAttendance struct {
-	Id     string `json:"id"`
-	UserId int    `json:"id_user"`
-	Label  string `json:"label"`
-	Date   string `json:"date"`
-	Time   string `json:"time"`
+	Id       string `json:"id"`
+	UserId   int    `json:"id_user"`
+	Label    string `json:"label"`
+	Location string `json:"location"`
+	WorkMode string `json:"work_mode"`
+	Date     string `json:"date"`
+	Time     string `json:"time"`
 }
 
 type ResponseActivity struct {
@@ -26,6 +31,85 @@ type ResponseActivity struct {
 	TimeCreated string `json:"time_created"`
 }
 
+type ResponseWorkModeHours struct {
+	WorkMode string  `json:"work_mode"`
+	Days     int     `json:"days"`
+	Hours    float64 `json:"hours"`
+}
+
+type ResponseWorkModeReport struct {
+	UserId     int                     `json:"id_user"`
+	StartDate  string                  `json:"start_date"`
+	EndDate    string                  `json:"end_date"`
+	Modes      []ResponseWorkModeHours `json:"modes"`
+	TotalHours float64                 `json:"total_hours"`
+}
+
+type ResponsePresence struct {
+	UserId       int    `json:"id_user"`
+	Name         string `json:"name"`
+	DepartmentId *int   `json:"id_department"`
+	Status       string `json:"status"`
+	WorkMode     string `json:"work_mode,omitempty"`
+	CheckedInAt  string `json:"checked_in_at,omitempty"`
+	LastPunchAt  string `json:"last_punch_at,omitempty"`
+}
+
+type ResponseTimesheetDay struct {
+	Date        string             `json:"date"`
+	Weekday     string             `json:"weekday"`
+	Status      string             `json:"status"`
+	FirstIn     string             `json:"first_in,omitempty"`
+	LastOut     string             `json:"last_out,omitempty"`
+	BreakHours  float64            `json:"break_hours"`
+	WorkedHours float64            `json:"worked_hours"`
+	Activities  []ResponseActivity `json:"activities"`
+}
+
+type ResponseTimesheetTotals struct {
+	WorkedDays    int     `json:"worked_days"`
+	LeaveDays     int     `json:"leave_days"`
+	Holidays      int     `json:"holidays"`
+	AbsentDays    int     `json:"absent_days"`
+	WeekendDays   int     `json:"weekend_days"`
+	BreakHours    float64 `json:"break_hours"`
+	WorkedHours   float64 `json:"worked_hours"`
+	ActivityCount int     `json:"activity_count"`
+}
+
+type ResponseTimesheet struct {
+	UserId int                     `json:"id_user"`
+	Month  string                  `json:"month"`
+	Days   []ResponseTimesheetDay  `json:"days"`
+	Totals ResponseTimesheetTotals `json:"totals"`
+}
+
+type ResponseAbsenceReport struct {
+	UserId       int      `json:"id_user"`
+	Name         string   `json:"name"`
+	DepartmentId *int     `json:"id_department"`
+	Absences     int      `json:"absences"`
+	Dates        []string `json:"dates"`
+}
+
+type ResponseHealthCheck struct {
+	Status     string `json:"status"`
+	DurationMs int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+}
+
+type ResponseBuild struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	GoVersion string `json:"go_version"`
+}
+
+type ResponseReadiness struct {
+	Checks           map[string]ResponseHealthCheck `json:"checks"`
+	MigrationVersion int                            `json:"migration_version"`
+	Build            ResponseBuild                  `json:"build"`
+}
+
 //EmptyObj object is used when data doesnt want to be null on json
 type EmptyObj struct{}
 
@@ -40,12 +124,20 @@ func BuildResponse(status bool, message string, data interface{}) Response {
 	return res
 }
 
-//BuildErrorResponse method is to inject data value to dynamic failed response
-func BuildErrorResponse(message string, err string, data interface{}) Response {
+//BuildPagedResponse method is BuildResponse with the pagination of a page of data
+func BuildPagedResponse(message string, data interface{}, pagination Pagination) Response {
+	res := BuildResponse(true, message, data)
+	res.Pagination = &pagination
+	return res
+}
+
+//BuildErrorResponse method is to inject data value to dynamic failed response, code tells clients what failed
+func BuildErrorResponse(code ErrorCode, message string, err string, data interface{}) Response {
 	splittedError := strings.Split(err, "\n")
 	res := Response{
 		Status:  false,
 		Message: message,
+		Code:    code,
 		Errors:  splittedError,
 		Data:    data,
 	}
@@ -0,0 +1,151 @@
+package helper
+
+import (
+	"armiariyan/attendances-system/entity"
+	"errors"
+	"sort"
+	"time"
+)
+
+var ErrInvalidDateRange = errors.New("endDate must not be before startDate")
+
+// BreakPeriod is a break taken during a work session, End is 0 while the break is running
+type BreakPeriod struct {
+	Start int64
+	End   int64
+}
+
+// WorkSession is a check in paired with the check out that closes it
+type WorkSession struct {
+	CheckIn  entity.Attendance
+	CheckOut entity.Attendance
+	Breaks   []BreakPeriod
+	Open     bool
+}
+
+// BreakDuration sums the finished breaks of the session
+func (s WorkSession) BreakDuration() time.Duration {
+	var total int64
+	for _, period := range s.Breaks {
+		if period.End != 0 {
+			total += period.End - period.Start
+		}
+	}
+	return time.Duration(total) * time.Millisecond
+}
+
+// Duration of a closed session without its breaks, an open session hasn't been worked yet
+func (s WorkSession) Duration() time.Duration {
+	if s.Open {
+		return 0
+	}
+	return time.Duration(s.CheckOut.Time-s.CheckIn.Time)*time.Millisecond - s.BreakDuration()
+}
+
+// OnBreak tells if the last break of the session is still running
+func (s WorkSession) OnBreak() bool {
+	return len(s.Breaks) > 0 && s.Breaks[len(s.Breaks)-1].End == 0
+}
+
+// PairAttendances pairs every check in with the next check out and puts the breaks
+// in between into the session, punches that don't fit the sequence are ignored
+func PairAttendances(attendances []entity.Attendance) []WorkSession {
+	sorted := make([]entity.Attendance, len(attendances))
+	copy(sorted, attendances)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Time < sorted[j].Time
+	})
+
+	var sessions []WorkSession
+	for _, attendance := range sorted {
+		var current *WorkSession
+		if len(sessions) > 0 && sessions[len(sessions)-1].Open {
+			current = &sessions[len(sessions)-1]
+		}
+
+		switch attendance.Label {
+		case entity.LabelCheckIn:
+			if current == nil {
+				sessions = append(sessions, WorkSession{CheckIn: attendance, Open: true})
+			}
+		case entity.LabelBreakStart:
+			if current != nil && !current.OnBreak() {
+				current.Breaks = append(current.Breaks, BreakPeriod{Start: attendance.Time})
+			}
+		case entity.LabelBreakEnd:
+			if current != nil && current.OnBreak() {
+				current.Breaks[len(current.Breaks)-1].End = attendance.Time
+			}
+		case entity.LabelCheckOut:
+			if current != nil {
+				// Checking out ends a running break
+				if current.OnBreak() {
+					current.Breaks[len(current.Breaks)-1].End = attendance.Time
+				}
+				current.CheckOut = attendance
+				current.Open = false
+			}
+		}
+	}
+	return sessions
+}
+
+const (
+	DayWorked   = "worked"
+	DayLeave    = "leave"
+	DayHoliday  = "holiday"
+	DayAbsent   = "absent"
+	DayWeekend  = "weekend"
+	DayUpcoming = "upcoming"
+)
+
+const (
+	PresenceCheckedIn  = "checked_in"
+	PresenceOnBreak    = "on_break"
+	PresenceCheckedOut = "checked_out"
+	PresenceOnLeave    = "on_leave"
+	PresenceNotArrived = "not_arrived"
+)
+
+// PresenceOf returns the status given by the punches of a day, not arrived when there is none
+func PresenceOf(attendances []entity.Attendance) string {
+	sessions := PairAttendances(attendances)
+	if len(sessions) == 0 {
+		return PresenceNotArrived
+	}
+
+	last := sessions[len(sessions)-1]
+	if !last.Open {
+		return PresenceCheckedOut
+	}
+	if last.OnBreak() {
+		return PresenceOnBreak
+	}
+	return PresenceCheckedIn
+}
+
+// TodayCheckIn returns the first check in of today
+func TodayCheckIn(attendances []entity.Attendance) (entity.Attendance, bool) {
+	result := GenerateTodayUnixMilli()
+
+	// result[0] is start, [1] is end
+	for _, attendance := range attendances {
+		if attendance.Date >= result[0] && attendance.Date <= result[1] && attendance.Label == entity.LabelCheckIn {
+			return attendance, true
+		}
+	}
+	return entity.Attendance{}, false
+}
+
+// StartOfWeek returns monday 00:00 of the week t belongs to
+func StartOfWeek(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+// DayRange returns unix milli from 00:00 until 23:59:59.999 of the local day t belongs to
+func DayRange(t time.Time) (int64, int64) {
+	start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	return start.UnixMilli(), start.AddDate(0, 0, 1).UnixMilli() - 1
+}
@@ -0,0 +1,429 @@
+package main
+
+import (
+	"armiariyan/attendances-system/config"
+	"armiariyan/attendances-system/entity"
+	"armiariyan/attendances-system/helper"
+	"armiariyan/attendances-system/logger"
+	"armiariyan/attendances-system/metrics"
+	"armiariyan/attendances-system/migration"
+	"armiariyan/attendances-system/repository/memory"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-contrib/sessions/cookie"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// testConfig is a valid config keeping the sqlite database in a temporary file
+func testConfig(t *testing.T) config.Config {
+	t.Helper()
+	cfg, _, err := config.Load([]string{
+		"--db-driver", config.DriverSQLite,
+		"--db-name", filepath.Join(t.TempDir(), "test.db"),
+		"--session-secret", "integration-test-secret",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
+
+// startServer serves the routes of main on the wired controllers with the real
+// session middleware, the router logs are dropped
+func startServer(t *testing.T, r *gin.Engine) *httptest.Server {
+	t.Helper()
+	registerRoutes(r)
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// memoryServer wires the app on the in-memory repositories, the sessions live in the cookie
+func memoryServer(t *testing.T) *httptest.Server {
+	cfg := testConfig(t)
+	store := memory.NewStore()
+	userRepository = memory.NewUserRepository(store)
+	attendanceRepository = memory.NewAttendanceRepository(store)
+	activityRepository = memory.NewActivityRepository(store)
+	networkRepository = memory.NewNetworkRepository(store)
+	kioskRepository = memory.NewKioskRepository(store)
+	policyRepository = memory.NewPolicyRepository(store)
+	departmentRepository = memory.NewDepartmentRepository(store)
+	leaveRepository = memory.NewLeaveRepository(store)
+	holidayRepository = memory.NewHolidayRepository(store)
+	absenceRepository = memory.NewAbsenceRepository(store)
+	webhookRepository = memory.NewWebhookRepository(store)
+	outboxRepository = memory.NewOutboxRepository(store)
+	healthChecks, migrationVersion = nil, nil
+	setupServices(cfg)
+
+	return startServer(t, config.InitWithStore(cfg, cookie.NewStore([]byte(cfg.Session.Secret))))
+}
+
+// sqliteServer wires the app like main does, on a migrated sqlite file
+func sqliteServer(t *testing.T) *httptest.Server {
+	cfg := testConfig(t)
+	setup(cfg)
+	t.Cleanup(func() {
+		config.CloseDatabaseConnection(db)
+	})
+	if _, err := migration.Up(db, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	return startServer(t, config.InitWithSession(cfg, db))
+}
+
+var backends = []struct {
+	name  string
+	start func(t *testing.T) *httptest.Server
+}{
+	{"memory", memoryServer},
+	{"sqlite", sqliteServer},
+}
+
+// apiResponse is the envelope of every answer, data is decoded by the test
+type apiResponse struct {
+	Status     bool               `json:"status"`
+	Code       helper.ErrorCode   `json:"code"`
+	Data       json.RawMessage    `json:"data"`
+	Pagination *helper.Pagination `json:"pagination"`
+}
+
+// apiClient keeps the session cookie between its requests like a browser
+type apiClient struct {
+	t      *testing.T
+	server *httptest.Server
+	client *http.Client
+}
+
+func newClient(t *testing.T, server *httptest.Server) *apiClient {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &apiClient{t: t, server: server, client: &http.Client{Jar: jar}}
+}
+
+// call sends body as json and checks the answer has the status wanted, its data is
+// decoded into data when given
+func (c *apiClient) call(method, path string, body interface{}, want int, data interface{}) apiResponse {
+	c.t.Helper()
+	var reader io.Reader
+	if body != nil {
+		content, err := json.Marshal(body)
+		if err != nil {
+			c.t.Fatal(err)
+		}
+		reader = bytes.NewReader(content)
+	}
+	request, err := http.NewRequest(method, c.server.URL+path, reader)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+
+	response, err := c.client.Do(request)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	defer response.Body.Close()
+	content, err := io.ReadAll(response.Body)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	if response.StatusCode != want {
+		c.t.Fatalf("%s %s = %d %s, want %d", method, path, response.StatusCode, content, want)
+	}
+
+	var envelope apiResponse
+	if len(content) == 0 {
+		return envelope
+	}
+	if err := json.Unmarshal(content, &envelope); err != nil {
+		c.t.Fatalf("%s %s answered %s: %v", method, path, content, err)
+	}
+	if data != nil {
+		if err := json.Unmarshal(envelope.Data, data); err != nil {
+			c.t.Fatalf("%s %s answered data %s: %v", method, path, envelope.Data, err)
+		}
+	}
+	return envelope
+}
+
+// registerAndLogin registers a user through v2 and logs the client in as them
+func (c *apiClient) registerAndLogin(name, email string) entity.User {
+	c.t.Helper()
+	var user entity.User
+	c.call("POST", "/api/v2/users", map[string]string{"name": name, "email": email, "password": "secret"}, http.StatusCreated, &user)
+	c.call("POST", "/api/v2/sessions", map[string]string{"email": email, "password": "secret"}, http.StatusOK, nil)
+	return user
+}
+
+func TestAPI(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	gin.DefaultWriter = io.Discard
+	logger.Log.SetOutput(io.Discard)
+
+	for _, backend := range backends {
+		t.Run(backend.name, func(t *testing.T) {
+			t.Run("register and login", func(t *testing.T) {
+				testRegisterAndLogin(t, newClient(t, backend.start(t)))
+			})
+			t.Run("check in and out", func(t *testing.T) {
+				testCheckInAndOut(t, newClient(t, backend.start(t)))
+			})
+			t.Run("activities", func(t *testing.T) {
+				testActivities(t, newClient(t, backend.start(t)))
+			})
+			t.Run("history of other users", func(t *testing.T) {
+				server := backend.start(t)
+				testHistoryOfOtherUsers(t, newClient(t, server), newClient(t, server))
+			})
+			t.Run("work mode report", func(t *testing.T) {
+				testWorkModeReport(t, newClient(t, backend.start(t)))
+			})
+			t.Run("metrics", func(t *testing.T) {
+				testMetrics(t, newClient(t, backend.start(t)))
+			})
+			t.Run("probes", func(t *testing.T) {
+				testProbes(t, newClient(t, backend.start(t)))
+			})
+		})
+	}
+}
+
+func testRegisterAndLogin(t *testing.T, c *apiClient) {
+	body := map[string]string{"name": "Ana", "email": "ana@example.com", "password": "secret"}
+	var user entity.User
+	c.call("POST", "/api/register", body, http.StatusCreated, &user)
+	if user.Id == 0 || user.Email != "ana@example.com" || user.Role != entity.RoleEmployee {
+		t.Errorf("registered %+v", user)
+	}
+
+	body["email"] = "ANA@example.com"
+	if res := c.call("POST", "/api/v2/users", body, http.StatusConflict, nil); res.Code != helper.CodeEmailTaken {
+		t.Errorf("second registration code = %s, want %s", res.Code, helper.CodeEmailTaken)
+	}
+
+	c.call("GET", "/api/v2/me", nil, http.StatusUnauthorized, nil)
+	if res := c.call("POST", "/api/v2/sessions", map[string]string{"email": "ana@example.com", "password": "wrong"}, http.StatusUnauthorized, nil); res.Code != helper.CodeInvalidCredentials {
+		t.Errorf("wrong password code = %s", res.Code)
+	}
+	c.call("POST", "/api/login", map[string]string{"email": "Ana@Example.com", "password": "secret"}, http.StatusOK, nil)
+
+	var me entity.User
+	c.call("GET", "/api/v2/me", nil, http.StatusOK, &me)
+	if me.Id != user.Id {
+		t.Errorf("me = %+v, want user %d", me, user.Id)
+	}
+
+	c.call("DELETE", "/api/v2/sessions", nil, http.StatusOK, nil)
+	c.call("GET", "/api/v2/me", nil, http.StatusUnauthorized, nil)
+}
+
+func testCheckInAndOut(t *testing.T, c *apiClient) {
+	user := c.registerAndLogin("Ana", "ana@example.com")
+	id := strconv.Itoa(user.Id)
+
+	if res := c.call("POST", "/api/v2/me/attendances", map[string]string{"label": entity.LabelCheckOut}, http.StatusConflict, nil); res.Code != helper.CodeNotCheckedIn {
+		t.Errorf("check out before check in code = %s", res.Code)
+	}
+
+	// Without office network the check in is remote
+	var checkIn helper.ResponseAttendance
+	c.call("POST", "/api/checkin/"+id, nil, http.StatusOK, &checkIn)
+	if checkIn.Label != entity.LabelCheckIn || checkIn.WorkMode != entity.WorkModeRemote || checkIn.UserId != user.Id {
+		t.Errorf("check in = %+v", checkIn)
+	}
+	if res := c.call("POST", "/api/v2/me/attendances", map[string]string{"label": entity.LabelCheckIn, "work_mode": entity.WorkModeOnsite}, http.StatusForbidden, nil); res.Code != helper.CodeOnsiteOutsideOffice {
+		t.Errorf("onsite check in from outside code = %s", res.Code)
+	}
+
+	c.call("POST", "/api/v2/me/attendances", map[string]string{"label": entity.LabelBreakStart}, http.StatusCreated, nil)
+	c.call("POST", "/api/break/"+id+"/end", nil, http.StatusOK, nil)
+
+	var checkOut helper.ResponseAttendance
+	c.call("POST", "/api/v2/me/attendances", map[string]string{"label": entity.LabelCheckOut}, http.StatusCreated, &checkOut)
+	if checkOut.WorkMode != entity.WorkModeRemote {
+		t.Errorf("check out = %+v, want the work mode of the check in", checkOut)
+	}
+
+	var attendances []helper.ResponseAttendance
+	res := c.call("GET", "/api/v2/me/attendances?limit=3", nil, http.StatusOK, &attendances)
+	if len(attendances) != 3 || res.Pagination == nil || !res.Pagination.HasMore || res.Pagination.NextCursor == "" {
+		t.Fatalf("first page = %+v %+v, want 3 of 4 attendances", attendances, res.Pagination)
+	}
+	var rest []helper.ResponseAttendance
+	res = c.call("GET", "/api/v2/me/attendances?limit=3&cursor="+res.Pagination.NextCursor, nil, http.StatusOK, &rest)
+	if len(rest) != 1 || res.Pagination.HasMore {
+		t.Errorf("second page = %+v %+v, want the last attendance", rest, res.Pagination)
+	}
+
+	var checkIns []helper.ResponseAttendance
+	c.call("GET", "/api/v2/me/attendances?label=check+in&q=REMOTE", nil, http.StatusOK, &checkIns)
+	if len(checkIns) != 1 || checkIns[0].Id != checkIn.Id {
+		t.Errorf("remote check ins = %+v, want %s", checkIns, checkIn.Id)
+	}
+	c.call("GET", "/api/attendances/"+id, nil, http.StatusOK, nil)
+}
+
+func testActivities(t *testing.T, c *apiClient) {
+	user := c.registerAndLogin("Ana", "ana@example.com")
+	id := strconv.Itoa(user.Id)
+
+	if res := c.call("POST", "/api/v2/me/activities", map[string]string{"description": "standup"}, http.StatusConflict, nil); res.Code != helper.CodeNotCheckedIn {
+		t.Errorf("activity before check in code = %s", res.Code)
+	}
+	c.call("POST", "/api/v2/me/attendances", map[string]string{"label": entity.LabelCheckIn}, http.StatusCreated, nil)
+
+	var first, second helper.ResponseActivity
+	c.call("POST", "/api/activity/"+id, map[string]string{"description": "100% reviewed"}, http.StatusCreated, &first)
+	c.call("POST", "/api/v2/me/activities", map[string]string{"description": "standup"}, http.StatusCreated, &second)
+
+	var found helper.ResponseActivity
+	c.call("GET", "/api/v2/me/activities/"+second.Id, nil, http.StatusOK, &found)
+	if found.Description != "standup" {
+		t.Errorf("activity = %+v", found)
+	}
+
+	var updated helper.ResponseActivity
+	c.call("PUT", "/api/v2/me/activities/"+second.Id, map[string]string{"description": "retro"}, http.StatusOK, &updated)
+	if updated.Description != "retro" || updated.Id != second.Id {
+		t.Errorf("updated = %+v", updated)
+	}
+	c.call("PUT", "/api/activity/"+id+"/"+first.Id, map[string]string{"description": "100% merged"}, http.StatusCreated, nil)
+
+	// % is no wildcard of the search
+	var searched []helper.ResponseActivity
+	c.call("GET", "/api/v2/me/activities?q=100%25", nil, http.StatusOK, &searched)
+	if len(searched) != 1 || searched[0].Id != first.Id || searched[0].Description != "100% merged" {
+		t.Errorf("search = %+v, want %s", searched, first.Id)
+	}
+
+	c.call("DELETE", "/api/v2/me/activities/"+second.Id, nil, http.StatusNoContent, nil)
+	c.call("GET", "/api/v2/me/activities/"+second.Id, nil, http.StatusNotFound, nil)
+	c.call("DELETE", "/api/activity/"+id+"/"+second.Id, nil, http.StatusNotFound, nil)
+
+	var history []helper.ResponseActivity
+	c.call("GET", "/api/activity/"+id, nil, http.StatusOK, &history)
+	if len(history) != 1 || history[0].Id != first.Id {
+		t.Errorf("history = %+v, want %s only", history, first.Id)
+	}
+
+	// An empty range is a page without rows, not a missing answer
+	var empty []helper.ResponseActivity
+	res := c.call("GET", "/api/activity/"+id+"?start_date=2000-01-01&end_date=2000-01-02", nil, http.StatusOK, &empty)
+	if empty == nil || len(empty) != 0 || res.Pagination == nil || res.Pagination.HasMore {
+		t.Errorf("empty history = %+v %+v, want an empty list with its pagination", empty, res.Pagination)
+	}
+}
+
+func testHistoryOfOtherUsers(t *testing.T, ana, bob *apiClient) {
+	anaUser := ana.registerAndLogin("Ana", "ana@example.com")
+	bob.registerAndLogin("Bob", "bob@example.com")
+	ana.call("POST", "/api/v2/me/attendances", map[string]string{"label": entity.LabelCheckIn}, http.StatusCreated, nil)
+
+	var activity helper.ResponseActivity
+	ana.call("POST", "/api/v2/me/activities", map[string]string{"description": "private"}, http.StatusCreated, &activity)
+
+	id := strconv.Itoa(anaUser.Id)
+	bob.call("GET", "/api/v2/users/"+id+"/attendances", nil, http.StatusForbidden, nil)
+	bob.call("GET", "/api/v2/users/"+id+"/activities", nil, http.StatusForbidden, nil)
+	bob.call("GET", "/api/attendances/"+id, nil, http.StatusForbidden, nil)
+	bob.call("GET", "/api/v2/me/activities/"+activity.Id, nil, http.StatusNotFound, nil)
+
+	var own []helper.ResponseAttendance
+	bob.call("GET", "/api/v2/me/attendances", nil, http.StatusOK, &own)
+	if len(own) != 0 {
+		t.Errorf("bob sees attendances %+v", own)
+	}
+}
+
+func testWorkModeReport(t *testing.T, c *apiClient) {
+	user := c.registerAndLogin("Ana", "ana@example.com")
+	path := "/api/reports/work-modes/" + strconv.Itoa(user.Id)
+	today := time.Now().Format("2006-01-02")
+
+	c.call("GET", path+"?range=today", nil, http.StatusOK, nil)
+	c.call("GET", path+"?start_date="+today+"T00:00&end_date="+today+"&tz=UTC", nil, http.StatusOK, nil)
+	if res := c.call("GET", path+"?start_date="+today, nil, http.StatusBadRequest, nil); res.Code != helper.CodeInvalidDate {
+		t.Errorf("open range code = %s, want %s", res.Code, helper.CodeInvalidDate)
+	}
+	// The old names of the dates are still read
+	c.call("GET", path+"?startDate="+today+"&endDate="+today, nil, http.StatusOK, nil)
+}
+
+func testMetrics(t *testing.T, c *apiClient) {
+	checkIns, failedLogins := testutil.ToFloat64(metrics.CheckIns), testutil.ToFloat64(metrics.FailedLogins)
+
+	c.registerAndLogin("Ana", "ana@example.com")
+	c.call("POST", "/api/v2/me/attendances", map[string]string{"label": entity.LabelCheckIn}, http.StatusCreated, nil)
+	c.call("POST", "/api/v2/sessions", map[string]string{"email": "ana@example.com", "password": "wrong"}, http.StatusUnauthorized, nil)
+
+	if got := testutil.ToFloat64(metrics.CheckIns) - checkIns; got != 1 {
+		t.Errorf("check ins counted %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(metrics.FailedLogins) - failedLogins; got != 1 {
+		t.Errorf("failed logins counted %v, want 1", got)
+	}
+
+	// Prometheus scrapes without session
+	response, err := http.Get(c.server.URL + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	content, err := io.ReadAll(response.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `attendances_http_requests_total{method="POST",route="/api/v2/me/attendances",status="201"}`
+	if response.StatusCode != http.StatusOK || !strings.Contains(string(content), want) {
+		t.Errorf("GET /metrics = %d, want 200 with %s", response.StatusCode, want)
+	}
+}
+
+func testProbes(t *testing.T, c *apiClient) {
+	c.call("GET", "/livez", nil, http.StatusOK, nil)
+
+	var readiness helper.ResponseReadiness
+	c.call("GET", "/readyz", nil, http.StatusOK, &readiness)
+	for name, check := range readiness.Checks {
+		if check.Status != "up" {
+			t.Errorf("check %s = %+v, want up", name, check)
+		}
+	}
+	// Only the sqlite run has a database to check
+	if _, ok := readiness.Checks["database"]; ok {
+		latest := migration.Migrations[len(migration.Migrations)-1].Version
+		if _, ok := readiness.Checks["sessions"]; !ok || readiness.MigrationVersion != latest {
+			t.Errorf("readiness = %+v, want the sessions checked and migration %d", readiness, latest)
+		}
+	}
+	if readiness.Build.GoVersion == "" {
+		t.Errorf("build = %+v, want the go version", readiness.Build)
+	}
+
+	var health map[string]interface{}
+	c.call("GET", "/api/check/health", nil, http.StatusOK, &health)
+	if len(health) != 0 {
+		t.Errorf("health data = %v, want an empty object", health)
+	}
+}
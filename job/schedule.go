@@ -0,0 +1,61 @@
+package job
+
+import (
+	"armiariyan/attendances-system/logger"
+	"context"
+	"time"
+)
+
+// Daily calls run every day at the given time of day until ctx is done,
+// run receives ctx and the time it was scheduled for
+func Daily(ctx context.Context, at time.Duration, name string, run func(ctx context.Context, scheduled time.Time)) {
+	for {
+		next := nextRun(time.Now(), at)
+		timer := time.NewTimer(time.Until(next))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			log := logger.Log.WithField("job", name)
+			log.Info("job started")
+			run(ctx, next)
+			log.WithField("duration_ms", time.Since(next).Milliseconds()).Info("job finished")
+		}
+	}
+}
+
+// ParseTimeOfDay reads "15:04" into the duration since midnight
+func ParseTimeOfDay(value string) (time.Duration, error) {
+	at, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(at.Hour())*time.Hour + time.Duration(at.Minute())*time.Minute, nil
+}
+
+func nextRun(now time.Time, at time.Duration) time.Time {
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	next := midnight.Add(at)
+	if !next.After(now) {
+		next = time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location()).Add(at)
+	}
+	return next
+}
+
+// Every calls run at every interval until ctx is done, run gets ctx to stop
+// a long run early
+func Every(ctx context.Context, interval time.Duration, run func(ctx context.Context, now time.Time)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			run(ctx, now)
+		}
+	}
+}
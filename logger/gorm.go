@@ -0,0 +1,63 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// slowQuery is how long a query runs before it is logged as a warning
+const slowQuery = 200 * time.Millisecond
+
+// Gorm logs the queries of gorm through Log, every query at debug level, slow ones
+// as warnings and failed ones as errors. Missing rows are no error, the
+// repositories turn them into ErrNotFound. A query run with the context of a
+// request carries its request id and user id
+func Gorm() gormlogger.Interface {
+	return gormLogger{}
+}
+
+type gormLogger struct{}
+
+func (l gormLogger) LogMode(gormlogger.LogLevel) gormlogger.Interface {
+	return l
+}
+
+func (gormLogger) Info(ctx context.Context, message string, args ...interface{}) {
+	FromContext(ctx).Infof(message, args...)
+}
+
+func (gormLogger) Warn(ctx context.Context, message string, args ...interface{}) {
+	FromContext(ctx).Warnf(message, args...)
+}
+
+func (gormLogger) Error(ctx context.Context, message string, args ...interface{}) {
+	FromContext(ctx).Errorf(message, args...)
+}
+
+func (gormLogger) Trace(ctx context.Context, begin time.Time, query func() (string, int64), err error) {
+	elapsed := time.Since(begin)
+	failed := err != nil && !errors.Is(err, gorm.ErrRecordNotFound)
+	if !failed && elapsed < slowQuery && !Log.IsLevelEnabled(logrus.DebugLevel) {
+		return
+	}
+
+	sql, rows := query()
+	entry := FromContext(ctx).WithFields(logrus.Fields{
+		"sql":         sql,
+		"rows":        rows,
+		"duration_ms": elapsed.Milliseconds(),
+	})
+	switch {
+	case failed:
+		entry.WithError(err).Error("query failed")
+	case elapsed >= slowQuery:
+		entry.Warn("slow query")
+	default:
+		entry.Debug("query")
+	}
+}
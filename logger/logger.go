@@ -0,0 +1,48 @@
+// Package logger writes the logs of the app, one json object per line in
+// production and readable text in development
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Formats log.format may name
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// Log is the logger of everything outside a request, requests log through For
+var Log = newLogger(os.Stderr)
+
+func newLogger(output io.Writer) *logrus.Logger {
+	log := logrus.New()
+	log.SetOutput(output)
+	log.SetFormatter(&logrus.TextFormatter{FullTimestamp: true})
+	return log
+}
+
+// Setup sets the lowest level logged, debug, info, warn or error, and the format of the lines
+func Setup(level, format string) error {
+	parsed, err := logrus.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	Log.SetLevel(parsed)
+
+	switch format {
+	case FormatJSON:
+		Log.SetFormatter(&logrus.JSONFormatter{
+			FieldMap: logrus.FieldMap{logrus.FieldKeyTime: "time", logrus.FieldKeyMsg: "message"},
+		})
+	case FormatText:
+		Log.SetFormatter(&logrus.TextFormatter{FullTimestamp: true})
+	default:
+		return fmt.Errorf("log format %q must be json or text", format)
+	}
+	return nil
+}
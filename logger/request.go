@@ -0,0 +1,99 @@
+package logger
+
+import (
+	"armiariyan/attendances-system/helper"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"regexp"
+	"runtime/debug"
+	"time"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// RequestIdHeader carries the id of a request from the client or the proxy in front
+// of the app, the response echoes it so both sides log the same id
+const RequestIdHeader = "X-Request-ID"
+
+const requestIdKey = "request_id"
+
+// validRequestId keeps ids sent by clients short and printable
+var validRequestId = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// Middleware takes the request id of X-Request-ID, or makes one when the client
+// didn't send a valid one, and logs the request once it is answered
+func Middleware() gin.HandlerFunc {
+	return func(context *gin.Context) {
+		start := time.Now()
+		request_id := context.GetHeader(RequestIdHeader)
+		if !validRequestId.MatchString(request_id) {
+			request_id = newRequestId()
+		}
+		context.Set(requestIdKey, request_id)
+		context.Header(RequestIdHeader, request_id)
+
+		context.Next()
+
+		status := context.Writer.Status()
+		entry := For(context).WithFields(logrus.Fields{
+			"method":      context.Request.Method,
+			"path":        context.Request.URL.Path,
+			"route":       context.FullPath(),
+			"status":      status,
+			"duration_ms": time.Since(start).Milliseconds(),
+			"client_ip":   context.ClientIP(),
+			"bytes":       context.Writer.Size(),
+		})
+		if status >= http.StatusInternalServerError {
+			entry.Error("request answered")
+		} else {
+			entry.Info("request answered")
+		}
+	}
+}
+
+// Recovery answers a panicking request with a 500 and logs the panic with its stack
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(io.Discard, func(context *gin.Context, err interface{}) {
+		For(context).WithFields(logrus.Fields{
+			"panic": err,
+			"stack": string(debug.Stack()),
+		}).Error("request panicked")
+		response := helper.BuildErrorResponse(helper.CodeInternal, "Failed to process request", "Internal server error", helper.EmptyObj{})
+		context.AbortWithStatusJSON(http.StatusInternalServerError, response)
+	})
+}
+
+// For returns the logger of a request, its lines carry the request id and the id
+// of the logged in user
+func For(context *gin.Context) *logrus.Entry {
+	fields := logrus.Fields{requestIdKey: context.GetString(requestIdKey)}
+	if session, ok := context.Get(sessions.DefaultKey); ok {
+		if user_id, ok := session.(sessions.Session).Get("user_id").(int); ok {
+			fields["user_id"] = user_id
+		}
+	}
+	return Log.WithFields(fields)
+}
+
+// FromContext returns the logger of the request ctx belongs to, a ctx of no request
+// like the one of a job logs without request fields
+func FromContext(ctx context.Context) *logrus.Entry {
+	if context, ok := ctx.Value(gin.ContextKey).(*gin.Context); ok {
+		return For(context)
+	}
+	return logrus.NewEntry(Log)
+}
+
+func newRequestId() string {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(id)
+}
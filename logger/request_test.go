@@ -0,0 +1,143 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-contrib/sessions/cookie"
+	"github.com/gin-gonic/gin"
+)
+
+// newRouter logs json to output and answers /login by logging in user 7
+func newRouter(output *bytes.Buffer) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	Log.SetOutput(output)
+	if err := Setup("info", FormatJSON); err != nil {
+		panic(err)
+	}
+
+	r := gin.New()
+	r.Use(Middleware(), Recovery(), sessions.Sessions("session_id", cookie.NewStore([]byte("0123456789abcdef"))))
+	r.GET("/login", func(context *gin.Context) {
+		session := sessions.Default(context)
+		session.Set("user_id", 7)
+		session.Save()
+		For(context).Info("logged in")
+		context.Status(http.StatusOK)
+	})
+	r.GET("/query", func(context *gin.Context) {
+		session := sessions.Default(context)
+		session.Set("user_id", 7)
+		Gorm().Trace(context, time.Now(), func() (string, int64) { return "SELECT 1", 0 }, errors.New("connection refused"))
+		context.Status(http.StatusOK)
+	})
+	r.GET("/panic", func(context *gin.Context) {
+		panic("boom")
+	})
+	return r
+}
+
+// lines decodes the json log lines of output
+func lines(t *testing.T, output *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var lines []map[string]interface{}
+	decoder := json.NewDecoder(output)
+	for decoder.More() {
+		var line map[string]interface{}
+		if err := decoder.Decode(&line); err != nil {
+			t.Fatalf("log line isn't json: %v", err)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestMiddlewareKeepsRequestIdOfClient(t *testing.T) {
+	var output bytes.Buffer
+	r := newRouter(&output)
+
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/login", nil)
+	request.Header.Set(RequestIdHeader, "req-42")
+	r.ServeHTTP(recorder, request)
+
+	if got := recorder.Header().Get(RequestIdHeader); got != "req-42" {
+		t.Errorf("%s = %q, want req-42", RequestIdHeader, got)
+	}
+	logged := lines(t, &output)
+	if len(logged) != 2 {
+		t.Fatalf("logged %d lines, want the handler line and the access line", len(logged))
+	}
+	for _, line := range logged {
+		if line["request_id"] != "req-42" || line["user_id"] != float64(7) {
+			t.Errorf("line %v, want request_id req-42 and user_id 7", line)
+		}
+	}
+	if access := logged[1]; access["route"] != "/login" || access["status"] != float64(http.StatusOK) || access["level"] != "info" {
+		t.Errorf("access line %v, want route /login, status 200 at info", access)
+	}
+}
+
+func TestMiddlewareReplacesInvalidRequestId(t *testing.T) {
+	var output bytes.Buffer
+	r := newRouter(&output)
+
+	for _, sent := range []string{"", "has spaces", string(make([]byte, 200))} {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "/login", nil)
+		request.Header.Set(RequestIdHeader, sent)
+		r.ServeHTTP(recorder, request)
+
+		got := recorder.Header().Get(RequestIdHeader)
+		if got == sent || !validRequestId.MatchString(got) {
+			t.Errorf("sent %q, got %s = %q, want a generated id", sent, RequestIdHeader, got)
+		}
+	}
+}
+
+func TestQueryLogCarriesRequestFields(t *testing.T) {
+	var output bytes.Buffer
+	r := newRouter(&output)
+
+	request := httptest.NewRequest(http.MethodGet, "/query", nil)
+	request.Header.Set(RequestIdHeader, "req-42")
+	r.ServeHTTP(httptest.NewRecorder(), request)
+	Gorm().Trace(context.Background(), time.Now(), func() (string, int64) { return "SELECT 2", 0 }, errors.New("connection refused"))
+
+	logged := lines(t, &output)
+	if len(logged) != 3 {
+		t.Fatalf("logged %d lines, want the query, the access line and the query of no request", len(logged))
+	}
+	if query := logged[0]; query["sql"] != "SELECT 1" || query["request_id"] != "req-42" || query["user_id"] != float64(7) {
+		t.Errorf("query line %v, want request_id req-42 and user_id 7", query)
+	}
+	if _, ok := logged[2]["request_id"]; ok || logged[2]["sql"] != "SELECT 2" {
+		t.Errorf("query line %v of no request, want no request_id", logged[2])
+	}
+}
+
+func TestRecoveryLogsPanic(t *testing.T) {
+	var output bytes.Buffer
+	r := newRouter(&output)
+
+	recorder := httptest.NewRecorder()
+	r.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+	if recorder.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want 500", recorder.Code)
+	}
+	logged := lines(t, &output)
+	if len(logged) != 2 || logged[0]["panic"] != "boom" || logged[1]["level"] != "error" {
+		t.Errorf("logged %v, want the panic then the access line at error", logged)
+	}
+	if _, ok := logged[0]["user_id"]; ok {
+		t.Errorf("panic line %v has a user_id without login", logged[0])
+	}
+}
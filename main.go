@@ -3,45 +3,291 @@ package main
 import (
 	"armiariyan/attendances-system/config"
 	"armiariyan/attendances-system/controller"
+	"armiariyan/attendances-system/entity"
+	"armiariyan/attendances-system/helper"
+	"armiariyan/attendances-system/job"
+	"armiariyan/attendances-system/logger"
+	"armiariyan/attendances-system/metrics"
+	"armiariyan/attendances-system/migration"
 	"armiariyan/attendances-system/repository"
 	"armiariyan/attendances-system/service"
+	"context"
+	"flag"
+	"net"
+	"os"
+	"os/signal"
+	"runtime"
+	"sync"
+	"syscall"
+	"time"
 
+	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
+// version and commit are set when building a release, like
+// go build -ldflags "-X main.version=v1.4.0 -X main.commit=$(git rev-parse --short HEAD)"
 var (
-	db             *gorm.DB                  = config.SetupDatabaseConnection()
-	userRepository repository.UserRepository = repository.NewUserRepository(db)
-	userService    service.UserService       = service.NewUserService(userRepository)
-	userController controller.UserController = controller.NewUserController(userService)
+	version = "dev"
+	commit  = "unknown"
 )
 
+var (
+	db                   *gorm.DB
+	userRepository       repository.UserRepository
+	attendanceRepository repository.AttendanceRepository
+	activityRepository   repository.ActivityRepository
+	networkRepository    repository.NetworkRepository
+	kioskRepository      repository.KioskRepository
+	policyRepository     repository.PolicyRepository
+	departmentRepository repository.DepartmentRepository
+	leaveRepository      repository.LeaveRepository
+	holidayRepository    repository.HolidayRepository
+	absenceRepository    repository.AbsenceRepository
+	webhookRepository    repository.WebhookRepository
+	outboxRepository     repository.OutboxRepository
+	userService          service.UserService
+	attendanceService    service.AttendanceService
+	activityService      service.ActivityService
+	eventBus             service.EventBus
+	webhookService       service.WebhookService
+	networkService       service.NetworkService
+	kioskService         service.KioskService
+	policyService        service.PolicyService
+	reportService        service.ReportService
+	departmentService    service.DepartmentService
+	leaveService         service.LeaveService
+	presenceService      service.PresenceService
+	calendarService      service.CalendarService
+	timesheetService     service.TimesheetService
+	payrollService       service.PayrollService
+	absenceService       service.AbsenceService
+	userController       controller.UserController
+	attendanceController controller.AttendanceController
+	activityController   controller.ActivityController
+	networkController    controller.NetworkController
+	kioskController      controller.KioskController
+	policyController     controller.PolicyController
+	reportController     controller.ReportController
+	departmentController controller.DepartmentController
+	leaveController      controller.LeaveController
+	presenceController   controller.PresenceController
+	holidayController    controller.HolidayController
+	timesheetController  controller.TimesheetController
+	payrollController    controller.PayrollController
+	absenceController    controller.AbsenceController
+	webhookController    controller.WebhookController
+	docsController       controller.DocsController
+	metricsController    controller.MetricsController
+	healthController     controller.HealthController
+	healthChecks         []controller.HealthCheck
+	migrationVersion     controller.MigrationVersion
+	readiness            *controller.Readiness
+)
+
+// setup connects the database and builds every repository, service and controller
+func setup(cfg config.Config) {
+	db = config.SetupDatabaseConnection(cfg.Database)
+	if sqlDB, err := db.DB(); err == nil {
+		metrics.WatchDatabase(sqlDB, cfg.Database.Driver)
+	}
+	userRepository = repository.NewUserRepository(db)
+	attendanceRepository = repository.NewAttendanceRepository(db)
+	activityRepository = repository.NewActivityRepository(db)
+	networkRepository = repository.NewNetworkRepository(db)
+	kioskRepository = repository.NewKioskRepository(db)
+	policyRepository = repository.NewPolicyRepository(db)
+	departmentRepository = repository.NewDepartmentRepository(db)
+	leaveRepository = repository.NewLeaveRepository(db)
+	holidayRepository = repository.NewHolidayRepository(db)
+	absenceRepository = repository.NewAbsenceRepository(db)
+	webhookRepository = repository.NewWebhookRepository(db)
+	outboxRepository = repository.NewOutboxRepository(db)
+	healthChecks = []controller.HealthCheck{
+		{Name: "database", Run: func(ctx context.Context) error { return config.PingDatabase(ctx, db) }},
+		{Name: "sessions", Run: func(ctx context.Context) error { return config.PingSessionStore(ctx, db) }},
+	}
+	migrationVersion = func(ctx context.Context) (int, error) {
+		return migration.Version(db.WithContext(ctx))
+	}
+	setupServices(cfg)
+}
+
+// setupServices builds every service and controller on the repositories
+func setupServices(cfg config.Config) {
+	userService = service.NewUserService(userRepository)
+	attendanceService = service.NewAttendanceService(attendanceRepository)
+	activityService = service.NewActivityService(activityRepository)
+	eventBus = service.NewEventBus(outboxRepository)
+	webhookService = service.NewWebhookService(webhookRepository)
+	networkService = service.NewNetworkService(networkRepository)
+	kioskService = service.NewKioskService(kioskRepository, cfg.Kiosk.Secret, cfg.Kiosk.Key)
+	policyService = service.NewPolicyService(policyRepository, attendanceRepository)
+	reportService = service.NewReportService(attendanceRepository)
+	departmentService = service.NewDepartmentService(departmentRepository, userRepository)
+	leaveService = service.NewLeaveService(leaveRepository, userRepository, departmentService)
+	presenceService = service.NewPresenceService(userRepository, attendanceRepository, leaveRepository, departmentService)
+	calendarService = service.NewCalendarService(holidayRepository, cfg.Work.Weekdays())
+	timesheetService = service.NewTimesheetService(attendanceRepository, activityRepository, leaveRepository, calendarService)
+	payrollService = service.NewPayrollService(userRepository, timesheetService, calendarService, cfg.Work.DailyHours, cfg.Payroll.FixedWidthLayout)
+	absenceService = service.NewAbsenceService(absenceRepository, userRepository, attendanceRepository, leaveRepository, calendarService, departmentService)
+	readiness = &controller.Readiness{}
+	userController = controller.NewUserController(userService, readiness)
+	attendanceController = controller.NewAttendanceController(attendanceService, userService, networkService, kioskService, policyService, departmentService)
+	activityController = controller.NewActivityController(activityService, attendanceService, userService, departmentService)
+	networkController = controller.NewNetworkController(networkService, userService)
+	kioskController = controller.NewKioskController(kioskService)
+	policyController = controller.NewPolicyController(policyService, userService)
+	reportController = controller.NewReportController(reportService, userService)
+	departmentController = controller.NewDepartmentController(departmentService, userService)
+	leaveController = controller.NewLeaveController(leaveService, userService)
+	presenceController = controller.NewPresenceController(presenceService, departmentService, userService)
+	holidayController = controller.NewHolidayController(calendarService, userService)
+	timesheetController = controller.NewTimesheetController(timesheetService, userService)
+	payrollController = controller.NewPayrollController(payrollService, userService)
+	absenceController = controller.NewAbsenceController(absenceService, departmentService, userService)
+	webhookController = controller.NewWebhookController(webhookService, userService)
+	docsController = controller.NewDocsController()
+	metricsController = controller.NewMetricsController()
+	build := helper.ResponseBuild{Version: version, Commit: commit, GoVersion: runtime.Version()}
+	healthController = controller.NewHealthController(readiness, healthChecks, migrationVersion, build, cfg.Server.HealthCheckTimeout)
+}
+
+// registerRoutes registers the routes of every controller on r
+func registerRoutes(r *gin.Engine) {
+	controller.RegisterRoutes(r, controller.Controllers{
+		User:       userController,
+		Attendance: attendanceController,
+		Activity:   activityController,
+		Network:    networkController,
+		Kiosk:      kioskController,
+		Policy:     policyController,
+		Report:     reportController,
+		Department: departmentController,
+		Leave:      leaveController,
+		Presence:   presenceController,
+		Holiday:    holidayController,
+		Timesheet:  timesheetController,
+		Payroll:    payrollController,
+		Absence:    absenceController,
+		Webhook:    webhookController,
+		Docs:       docsController,
+		Metrics:    metricsController,
+		Health:     healthController,
+	})
+}
+
 func main() {
+	cfg, args, err := config.Load(os.Args[1:])
+	if err == flag.ErrHelp {
+		return
+	}
+	if err != nil {
+		logger.Log.Fatal(err)
+	}
+
+	// The config is printed before it is validated to show what is wrong with it
+	if len(args) > 0 && args[0] == "print-config" {
+		if err := printConfig(cfg, os.Stdout); err != nil {
+			logger.Log.Fatal(err)
+		}
+		return
+	}
+	if err := cfg.Validate(); err != nil {
+		logger.Log.Fatal(err)
+	}
+	if err := logger.Setup(cfg.Log.Level, cfg.Log.Format); err != nil {
+		logger.Log.Fatal(err)
+	}
+	// gin prints its routes and warnings only when debugging
+	if cfg.Log.Level != "debug" {
+		gin.SetMode(gin.ReleaseMode)
+	}
+
+	setup(cfg)
 	defer config.CloseDatabaseConnection(db)
 
-	r := config.InitWithSession()
+	if ok, err := runCommand(args); ok {
+		if err != nil {
+			config.CloseDatabaseConnection(db)
+			logger.Log.Fatal(err)
+		}
+		return
+	}
 
-	// seeder.DBSeed(db)
+	if err := migrateOnStart(cfg.Database); err != nil {
+		config.CloseDatabaseConnection(db)
+		logger.Log.Fatal(err)
+	}
+
+	r := config.InitWithSession(cfg, db)
 
-	r.GET("/", userController.Index)
+	// The jobs are stopped and waited for before the database is closed, a run
+	// in progress is cut by the context being done
+	jobsContext, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	var jobs sync.WaitGroup
+	runJob := func(run func()) {
+		jobs.Add(1)
+		go func() {
+			defer jobs.Done()
+			run()
+		}()
+	}
+
+	// Check yesterday for absences every night
+	runJob(func() {
+		job.Daily(jobsContext, cfg.Work.AbsenceDetectionAt(), "detect-absences", func(ctx context.Context, scheduled time.Time) {
+			if _, err := absenceService.DetectAbsences(ctx, scheduled.AddDate(0, 0, -1)); err != nil {
+				logger.Log.WithField("job", "detect-absences").WithError(err).Error("job failed")
+			}
+		})
+	})
+
+	// Hand the saved domain events to their subscribers
+	for _, eventType := range entity.WebhookEvents {
+		eventBus.Subscribe(eventType, webhookService.Publish)
+	}
+	runJob(func() {
+		job.Every(jobsContext, time.Second, func(ctx context.Context, now time.Time) {
+			eventBus.Dispatch(ctx, now)
+		})
+	})
 
-	userRoutes := r.Group("api/")
-	{
-		userRoutes.GET("/check/health", userController.Healthcheck)
-		userRoutes.POST("/register", userController.Register)
-		userRoutes.POST("/login", userController.Login)
-		userRoutes.POST("/logout", userController.Logout)
+	// Send queued webhook deliveries
+	runJob(func() {
+		job.Every(jobsContext, 5*time.Second, func(ctx context.Context, now time.Time) {
+			webhookService.DeliverDue(ctx, now)
+		})
+	})
 
-		userRoutes.POST("/checkin/:id", userController.CheckIn)
-		userRoutes.POST("/checkout/:id", userController.CheckOut)
+	// seeder.DBSeed(db)
+
+	registerRoutes(r)
 
-		userRoutes.POST("/activity/:id", userController.CreateActivity)
-		userRoutes.PUT("/activity/:id/:id_activity", userController.UpdateActivity)
-		userRoutes.DELETE("/activity/:id/:id_activity", userController.DeleteActivity)
+	server := newServer(cfg.Server, r)
+	listener, err := net.Listen("tcp", server.Addr)
+	if err != nil {
+		config.CloseDatabaseConnection(db)
+		logger.Log.Fatal(err)
+	}
+	logger.Log.WithField("address", listener.Addr().String()).Info("listening")
 
-		userRoutes.GET("/activity/:id", userController.GetActivityHistoryByDate)
-		userRoutes.GET("/attendances/:id", userController.GetAttendancesHistory)
+	// The signals are let go as soon as the first one is caught, so a second signal
+	// kills the process at once instead of waiting for the drain
+	signals, stopSignals := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-signals.Done()
+		stopSignals()
+	}()
+	err = serve(signals, server, listener, cfg.Server, readiness)
+	stopSignals()
+	if err != nil {
+		logger.Log.Error(err)
 	}
 
-	r.Run()
+	stopJobs()
+	if !waitFor(&jobs, cfg.Server.ShutdownTimeout) {
+		logger.Log.WithField("waited", cfg.Server.ShutdownTimeout.String()).Warn("jobs still running, closing the database")
+	}
 }
@@ -0,0 +1,112 @@
+// Package metrics counts what the app does for Prometheus, served by Handler
+// in the text format
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "attendances"
+
+// Registry holds every metric of the app, plus the ones of the go runtime and the process
+var Registry = prometheus.NewRegistry()
+
+var (
+	requests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "Requests answered, by method, route and status.",
+	}, []string{"method", "route", "status"})
+	requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Time to answer requests, by method, route and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	// CheckIns and the counters below count what succeeded, once it is saved
+	CheckIns = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "check_ins_total",
+		Help:      "Check ins saved.",
+	})
+	CheckOuts = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "check_outs_total",
+		Help:      "Check outs saved.",
+	})
+	ActivitiesCreated = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "activities_created_total",
+		Help:      "Activities created.",
+	})
+	FailedLogins = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "failed_logins_total",
+		Help:      "Logins refused for an unknown email or a wrong password.",
+	})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		requests, requestDuration,
+		CheckIns, CheckOuts, ActivitiesCreated, FailedLogins,
+	)
+}
+
+// unmatchedRoute labels requests no route matched, their paths would make a label value each
+const unmatchedRoute = "unmatched"
+
+// Middleware counts and times every request by the route it matched
+func Middleware() gin.HandlerFunc {
+	return func(context *gin.Context) {
+		start := time.Now()
+		context.Next()
+
+		route := context.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		labels := prometheus.Labels{
+			"method": context.Request.Method,
+			"route":  route,
+			"status": strconv.Itoa(context.Writer.Status()),
+		}
+		requests.With(labels).Inc()
+		requestDuration.With(labels).Observe(time.Since(start).Seconds())
+	}
+}
+
+var (
+	databaseMu sync.Mutex
+	database   prometheus.Collector
+)
+
+// WatchDatabase exports the connection pool stats of db as the go_sql_* metrics,
+// labelled with name. It replaces the database watched before
+func WatchDatabase(db *sql.DB, name string) {
+	databaseMu.Lock()
+	defer databaseMu.Unlock()
+
+	if database != nil {
+		Registry.Unregister(database)
+	}
+	database = collectors.NewDBStatsCollector(db, name)
+	Registry.MustRegister(database)
+}
+
+// Handler serves the metrics of Registry
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMiddlewareCountsByRouteAndStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Middleware())
+	r.GET("/items/:id", func(context *gin.Context) {
+		context.Status(http.StatusTeapot)
+	})
+
+	for _, path := range []string{"/items/1", "/items/2", "/nowhere"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	if got := testutil.ToFloat64(requests.WithLabelValues("GET", "/items/:id", "418")); got != 2 {
+		t.Errorf("requests of /items/:id = %v, want 2", got)
+	}
+	if got := testutil.ToFloat64(requests.WithLabelValues("GET", unmatchedRoute, "404")); got != 1 {
+		t.Errorf("unmatched requests = %v, want 1", got)
+	}
+}
+
+func TestHandlerServesTextFormat(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	// Watching a second database replaces the first instead of failing on duplicate metrics
+	WatchDatabase(db, "first")
+	WatchDatabase(db, "test")
+	FailedLogins.Inc()
+
+	recorder := httptest.NewRecorder()
+	Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	body := recorder.Body.String()
+	for _, want := range []string{
+		"attendances_failed_logins_total ",
+		`go_sql_max_open_connections{db_name="test"}`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics don't contain %q", want)
+		}
+	}
+	if strings.Contains(body, `db_name="first"`) {
+		t.Error("metrics still contain the replaced database")
+	}
+}
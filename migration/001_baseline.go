@@ -0,0 +1,53 @@
+package migration
+
+import "gorm.io/gorm"
+
+// The tables below are copies of the entities as they were when the migration was
+// written, the entities keep changing but a migration must create the same tables forever
+
+type baselineUser struct {
+	Id         int                  `gorm:"primary_key:auto_increment"`
+	Name       string               `gorm:"type:varchar(128)"`
+	Email      string               `gorm:"type:varchar(128)"`
+	Password   string               `gorm:"type:varchar(255)"`
+	Activity   []baselineActivity   `gorm:"foreignKey:UserId"`
+	Attendance []baselineAttendance `gorm:"foreignKey:UserId"`
+}
+
+func (baselineUser) TableName() string { return "users" }
+
+type baselineAttendance struct {
+	Id     string `gorm:"primaryKey;type:varchar(128)"`
+	UserId int
+	Label  string `gorm:"type:varchar(128)"`
+	Date   int64
+	Time   int64
+	User   baselineUser `gorm:"foreignKey:UserId"`
+}
+
+func (baselineAttendance) TableName() string { return "attendances" }
+
+type baselineActivity struct {
+	Id          string `gorm:"primaryKey;type:varchar(128)"`
+	UserId      int
+	Description string `gorm:"type:varchar(128)"`
+	DateCreated int64
+	TimeCreated int64
+	User        baselineUser `gorm:"foreignKey:UserId"`
+}
+
+func (baselineActivity) TableName() string { return "activities" }
+
+// baseline is the users, attendances and activities tables as AutoMigrate left them
+// before the attendance rules, an existing database keeps its tables and is only
+// marked as migrated. The columns added since then come with userAttendanceColumns
+var baseline = Migration{
+	Version: 1,
+	Name:    "baseline",
+	Up: func(tx *gorm.DB) error {
+		return createTables(tx, &baselineUser{}, &baselineAttendance{}, &baselineActivity{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return dropTables(tx, &baselineActivity{}, &baselineAttendance{}, &baselineUser{})
+	},
+}
@@ -0,0 +1,40 @@
+package migration
+
+import "gorm.io/gorm"
+
+type attendanceRulesOfficeNetwork struct {
+	Id   int    `gorm:"primary_key:auto_increment"`
+	Name string `gorm:"type:varchar(128)"`
+	CIDR string `gorm:"type:varchar(64);uniqueIndex"`
+}
+
+func (attendanceRulesOfficeNetwork) TableName() string { return "office_networks" }
+
+type attendanceRulesKioskToken struct {
+	Nonce  string       `gorm:"primaryKey;type:varchar(64)"`
+	UserId int          `gorm:"primaryKey;autoIncrement:false"`
+	UsedAt int64        `gorm:"index"`
+	User   baselineUser `gorm:"foreignKey:UserId"`
+}
+
+func (attendanceRulesKioskToken) TableName() string { return "kiosk_tokens" }
+
+type attendanceRulesWorkPolicy struct {
+	UserId               int `gorm:"primaryKey;autoIncrement:false"`
+	MaxRemoteDaysPerWeek int
+	User                 baselineUser `gorm:"foreignKey:UserId"`
+}
+
+func (attendanceRulesWorkPolicy) TableName() string { return "work_policies" }
+
+// attendanceRules adds the office networks, kiosk tokens and work policies checked on check in
+var attendanceRules = Migration{
+	Version: 2,
+	Name:    "attendance_rules",
+	Up: func(tx *gorm.DB) error {
+		return createTables(tx, &attendanceRulesOfficeNetwork{}, &attendanceRulesKioskToken{}, &attendanceRulesWorkPolicy{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return dropTables(tx, &attendanceRulesWorkPolicy{}, &attendanceRulesKioskToken{}, &attendanceRulesOfficeNetwork{})
+	},
+}
@@ -0,0 +1,58 @@
+package migration
+
+import "gorm.io/gorm"
+
+type organizationDepartment struct {
+	Id        int    `gorm:"primary_key:auto_increment"`
+	Name      string `gorm:"type:varchar(128)"`
+	ManagerId *int
+	ParentId  *int
+	Manager   *baselineUser           `gorm:"foreignKey:ManagerId"`
+	Parent    *organizationDepartment `gorm:"foreignKey:ParentId"`
+}
+
+func (organizationDepartment) TableName() string { return "departments" }
+
+type organizationLeave struct {
+	Id         int    `gorm:"primary_key:auto_increment"`
+	UserId     int    `gorm:"index"`
+	Type       string `gorm:"type:varchar(32)"`
+	StartDate  string `gorm:"type:varchar(10);index"`
+	EndDate    string `gorm:"type:varchar(10);index"`
+	Reason     string `gorm:"type:varchar(255)"`
+	Status     string `gorm:"type:varchar(16);default:pending"`
+	ReviewerId *int
+	User       baselineUser `gorm:"foreignKey:UserId"`
+}
+
+func (organizationLeave) TableName() string { return "leaves" }
+
+type organizationHoliday struct {
+	Id   int    `gorm:"primary_key:auto_increment"`
+	Date string `gorm:"type:varchar(10);uniqueIndex"`
+	Name string `gorm:"type:varchar(128)"`
+}
+
+func (organizationHoliday) TableName() string { return "holidays" }
+
+type organizationAbsence struct {
+	Id         int    `gorm:"primary_key:auto_increment"`
+	UserId     int    `gorm:"uniqueIndex:idx_absence_user_date"`
+	Date       string `gorm:"type:varchar(10);uniqueIndex:idx_absence_user_date;index"`
+	DetectedAt int64
+	User       baselineUser `gorm:"foreignKey:UserId"`
+}
+
+func (organizationAbsence) TableName() string { return "absences" }
+
+// organization adds departments, leaves, holidays and the absences detected from them
+var organization = Migration{
+	Version: 3,
+	Name:    "organization",
+	Up: func(tx *gorm.DB) error {
+		return createTables(tx, &organizationDepartment{}, &organizationLeave{}, &organizationHoliday{}, &organizationAbsence{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return dropTables(tx, &organizationAbsence{}, &organizationHoliday{}, &organizationLeave{}, &organizationDepartment{})
+	},
+}
@@ -0,0 +1,58 @@
+package migration
+
+import "gorm.io/gorm"
+
+type webhooksSubscription struct {
+	Id         int    `gorm:"primary_key:auto_increment"`
+	URL        string `gorm:"type:varchar(512)"`
+	Secret     string `gorm:"type:varchar(128)"`
+	EventTypes string `gorm:"type:varchar(512)"`
+	Active     bool
+	CreatedAt  int64 `gorm:"autoCreateTime:milli"`
+}
+
+func (webhooksSubscription) TableName() string { return "webhook_subscriptions" }
+
+type webhooksDelivery struct {
+	Id             int    `gorm:"primary_key:auto_increment"`
+	SubscriptionId int    `gorm:"uniqueIndex:idx_delivery_subscription_event"`
+	EventId        string `gorm:"type:varchar(64);uniqueIndex:idx_delivery_subscription_event"`
+	EventType      string `gorm:"type:varchar(64)"`
+	Payload        string `gorm:"type:text"`
+	Status         string `gorm:"type:varchar(16);index"`
+	Attempts       int
+	NextAttemptAt  int64 `gorm:"index"`
+	ResponseStatus int
+	LastError      string               `gorm:"type:varchar(512)"`
+	CreatedAt      int64                `gorm:"autoCreateTime:milli"`
+	UpdatedAt      int64                `gorm:"autoUpdateTime:milli"`
+	Subscription   webhooksSubscription `gorm:"foreignKey:SubscriptionId"`
+}
+
+func (webhooksDelivery) TableName() string { return "webhook_deliveries" }
+
+type webhooksOutboxEvent struct {
+	Id           int    `gorm:"primary_key:auto_increment"`
+	EventId      string `gorm:"type:varchar(64);uniqueIndex"`
+	Type         string `gorm:"type:varchar(64);index"`
+	Payload      string `gorm:"type:text"`
+	Status       string `gorm:"type:varchar(16);index"`
+	Attempts     int
+	LastError    string `gorm:"type:varchar(512)"`
+	OccurredAt   int64
+	DispatchedAt int64
+}
+
+func (webhooksOutboxEvent) TableName() string { return "outbox_events" }
+
+// webhooks adds the webhook subscriptions, their deliveries and the outbox feeding them
+var webhooks = Migration{
+	Version: 4,
+	Name:    "webhooks",
+	Up: func(tx *gorm.DB) error {
+		return createTables(tx, &webhooksSubscription{}, &webhooksDelivery{}, &webhooksOutboxEvent{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return dropTables(tx, &webhooksOutboxEvent{}, &webhooksDelivery{}, &webhooksSubscription{})
+	},
+}
@@ -0,0 +1,69 @@
+package migration
+
+import "gorm.io/gorm"
+
+type userAttendanceColumnsUser struct {
+	Id           int    `gorm:"primary_key:auto_increment"`
+	Name         string `gorm:"type:varchar(128)"`
+	Email        string `gorm:"type:varchar(128)"`
+	Password     string `gorm:"type:varchar(255)"`
+	Role         string `gorm:"type:varchar(32);default:employee"`
+	DepartmentId *int
+	CreatedAt    int64 `gorm:"autoCreateTime:milli"`
+}
+
+func (userAttendanceColumnsUser) TableName() string { return "users" }
+
+type userAttendanceColumnsAttendance struct {
+	Id       string `gorm:"primaryKey;type:varchar(128)"`
+	UserId   int    `gorm:"index:idx_attendance_user_date"`
+	Label    string `gorm:"type:varchar(128)"`
+	Location string `gorm:"type:varchar(16)"`
+	WorkMode string `gorm:"type:varchar(32)"`
+	Date     int64  `gorm:"index:idx_attendance_user_date"`
+	Time     int64
+}
+
+func (userAttendanceColumnsAttendance) TableName() string { return "attendances" }
+
+type userAttendanceColumnsActivity struct {
+	Id          string `gorm:"primaryKey;type:varchar(128)"`
+	UserId      int    `gorm:"index:idx_activity_user_date"`
+	Description string `gorm:"type:varchar(128)"`
+	DateCreated int64  `gorm:"index:idx_activity_user_date"`
+	TimeCreated int64
+}
+
+func (userAttendanceColumnsActivity) TableName() string { return "activities" }
+
+// userAttendanceColumns adds the roles, departments and creation time of the users, the
+// location and work mode of the attendances and the indexes of the history queries.
+// A database AutoMigrate kept up to date already has some of them, they are skipped
+var userAttendanceColumns = Migration{
+	Version: 5,
+	Name:    "user_attendance_columns",
+	Up: func(tx *gorm.DB) error {
+		if err := addColumns(tx, &userAttendanceColumnsUser{}, "Role", "DepartmentId", "CreatedAt"); err != nil {
+			return err
+		}
+		if err := addColumns(tx, &userAttendanceColumnsAttendance{}, "Location", "WorkMode"); err != nil {
+			return err
+		}
+		if err := createIndexes(tx, &userAttendanceColumnsAttendance{}, "idx_attendance_user_date"); err != nil {
+			return err
+		}
+		return createIndexes(tx, &userAttendanceColumnsActivity{}, "idx_activity_user_date")
+	},
+	Down: func(tx *gorm.DB) error {
+		if err := dropIndexes(tx, &userAttendanceColumnsActivity{}, "idx_activity_user_date"); err != nil {
+			return err
+		}
+		if err := dropIndexes(tx, &userAttendanceColumnsAttendance{}, "idx_attendance_user_date"); err != nil {
+			return err
+		}
+		if err := dropColumns(tx, &userAttendanceColumnsAttendance{}, "Location", "WorkMode"); err != nil {
+			return err
+		}
+		return dropColumns(tx, &userAttendanceColumnsUser{}, "Role", "DepartmentId", "CreatedAt")
+	},
+}
@@ -0,0 +1,260 @@
+package migration
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Migration moves the schema from the version before it to Version, Down moves it back.
+// A released migration is never edited, a change of the schema gets a new migration
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+}
+
+// Migrations lists every migration in version order
+var Migrations = []Migration{
+	baseline,
+	attendanceRules,
+	organization,
+	webhooks,
+	userAttendanceColumns,
+}
+
+// SchemaMigration is the row of an applied migration
+type SchemaMigration struct {
+	Version   int    `gorm:"primaryKey;autoIncrement:false"`
+	Name      string `gorm:"type:varchar(128)"`
+	AppliedAt int64
+}
+
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Status tells whether a migration is applied, AppliedAt is zero while it is pending
+type Status struct {
+	Version   int
+	Name      string
+	AppliedAt int64
+}
+
+// Up applies the pending migrations up to version, every one when version is 0, and
+// returns the applied ones. Each migration runs in its own transaction, so a failure
+// keeps the migrations before it. MySQL commits schema changes at once, a failed
+// migration there may have to be cleaned up by hand
+func Up(db *gorm.DB, version int) ([]Migration, error) {
+	return up(db, Migrations, version)
+}
+
+// Down reverts the last steps applied migrations and returns the reverted ones
+func Down(db *gorm.DB, steps int) ([]Migration, error) {
+	return down(db, Migrations, steps)
+}
+
+// Statuses returns every migration with the time it was applied, applied versions
+// this build doesn't know come last with an empty name
+func Statuses(db *gorm.DB) ([]Status, error) {
+	return statuses(db, Migrations)
+}
+
+// Pending returns the migrations not applied yet
+func Pending(db *gorm.DB) ([]Migration, error) {
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return nil, err
+	}
+	var pending []Migration
+	for _, migration := range Migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			pending = append(pending, migration)
+		}
+	}
+	return pending, nil
+}
+
+// Version returns the highest applied version, 0 for an empty database
+func Version(db *gorm.DB) (int, error) {
+	if !db.Migrator().HasTable(&SchemaMigration{}) {
+		return 0, nil
+	}
+	var version int
+	err := db.Model(&SchemaMigration{}).Select("COALESCE(MAX(version), 0)").Scan(&version).Error
+	return version, err
+}
+
+func up(db *gorm.DB, migrations []Migration, version int) ([]Migration, error) {
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, migration := range migrations {
+		if version != 0 && migration.Version > version {
+			break
+		}
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := migration.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&SchemaMigration{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now().UnixMilli()}).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("migration %d %s: %w", migration.Version, migration.Name, err)
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+func down(db *gorm.DB, migrations []Migration, steps int) ([]Migration, error) {
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for i := len(migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		migration := migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := migration.Down(tx); err != nil {
+				return err
+			}
+			return tx.Delete(&SchemaMigration{Version: migration.Version}).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("migration %d %s: %w", migration.Version, migration.Name, err)
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+func statuses(db *gorm.DB, migrations []Migration) ([]Status, error) {
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Status
+	for _, migration := range migrations {
+		result = append(result, Status{Version: migration.Version, Name: migration.Name, AppliedAt: applied[migration.Version]})
+		delete(applied, migration.Version)
+	}
+	var unknown []int
+	for version := range applied {
+		unknown = append(unknown, version)
+	}
+	sort.Ints(unknown)
+	for _, version := range unknown {
+		result = append(result, Status{Version: version, AppliedAt: applied[version]})
+	}
+	return result, nil
+}
+
+// appliedVersions returns when every applied version was applied, creating the table
+// that tracks them on first use
+func appliedVersions(db *gorm.DB) (map[int]int64, error) {
+	if err := db.AutoMigrate(&SchemaMigration{}); err != nil {
+		return nil, err
+	}
+	var rows []SchemaMigration
+	if err := db.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	applied := map[int]int64{}
+	for _, row := range rows {
+		applied[row.Version] = row.AppliedAt
+	}
+	return applied, nil
+}
+
+// createTables creates the tables of models that don't exist yet, in order, so a
+// database created by AutoMigrate before migrations existed is taken as it is
+func createTables(tx *gorm.DB, models ...interface{}) error {
+	for _, model := range models {
+		if tx.Migrator().HasTable(model) {
+			continue
+		}
+		if err := tx.Migrator().CreateTable(model); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dropTables drops the tables of models, in order
+func dropTables(tx *gorm.DB, models ...interface{}) error {
+	for _, model := range models {
+		if err := tx.Migrator().DropTable(model); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addColumns adds the fields of model its table doesn't have yet, in order
+func addColumns(tx *gorm.DB, model interface{}, fields ...string) error {
+	for _, field := range fields {
+		if tx.Migrator().HasColumn(model, field) {
+			continue
+		}
+		if err := tx.Migrator().AddColumn(model, field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dropColumns drops the fields of model its table has, in order
+func dropColumns(tx *gorm.DB, model interface{}, fields ...string) error {
+	for _, field := range fields {
+		if !tx.Migrator().HasColumn(model, field) {
+			continue
+		}
+		if err := tx.Migrator().DropColumn(model, field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createIndexes creates the named indexes of model its table doesn't have yet, in order
+func createIndexes(tx *gorm.DB, model interface{}, names ...string) error {
+	for _, name := range names {
+		if tx.Migrator().HasIndex(model, name) {
+			continue
+		}
+		if err := tx.Migrator().CreateIndex(model, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dropIndexes drops the named indexes of model its table has, in order
+func dropIndexes(tx *gorm.DB, model interface{}, names ...string) error {
+	for _, name := range names {
+		if !tx.Migrator().HasIndex(model, name) {
+			continue
+		}
+		if err := tx.Migrator().DropIndex(model, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+package migration
+
+import (
+	"armiariyan/attendances-system/config"
+	"armiariyan/attendances-system/entity"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func openDatabase(t *testing.T, name string) *gorm.DB {
+	t.Helper()
+	db := config.SetupDatabaseConnection(config.DatabaseConfig{Driver: config.DriverSQLite, Name: filepath.Join(t.TempDir(), name)})
+	t.Cleanup(func() { config.CloseDatabaseConnection(db) })
+	return db
+}
+
+// schema describes every table and index but the migrations table, a table by its columns
+// and foreign keys in name order since the columns a migration adds come after the others
+func schema(t *testing.T, db *gorm.DB) map[string]string {
+	t.Helper()
+	var rows []struct {
+		Type string
+		Name string
+		Sql  string
+	}
+	if err := db.Raw("SELECT type, name, sql FROM sqlite_master WHERE name NOT LIKE 'schema_migrations%' AND sql IS NOT NULL").Scan(&rows).Error; err != nil {
+		t.Fatal(err)
+	}
+	statements := map[string]string{}
+	for _, row := range rows {
+		if row.Type != "table" {
+			statements[row.Name] = row.Sql
+			continue
+		}
+		var columns []struct {
+			Name      string
+			Type      string
+			Notnull   bool
+			DfltValue *string
+			Pk        int
+		}
+		if err := db.Raw("SELECT name, type, \"notnull\", dflt_value, pk FROM pragma_table_info(?)", row.Name).Scan(&columns).Error; err != nil {
+			t.Fatal(err)
+		}
+		var foreignKeys []struct {
+			Table string
+			From  string
+			To    string
+		}
+		if err := db.Raw("SELECT \"table\", \"from\", \"to\" FROM pragma_foreign_key_list(?)", row.Name).Scan(&foreignKeys).Error; err != nil {
+			t.Fatal(err)
+		}
+		var described []string
+		for _, column := range columns {
+			dflt := ""
+			if column.DfltValue != nil {
+				dflt = *column.DfltValue
+			}
+			described = append(described, fmt.Sprintf("%s %s notnull=%t default=%s pk=%d", column.Name, column.Type, column.Notnull, dflt, column.Pk))
+		}
+		for _, foreignKey := range foreignKeys {
+			described = append(described, fmt.Sprintf("%s references %s(%s)", foreignKey.From, foreignKey.Table, foreignKey.To))
+		}
+		sort.Strings(described)
+		statements[row.Name] = strings.Join(described, ", ")
+	}
+	return statements
+}
+
+func TestMigrationsMatchEntities(t *testing.T) {
+	migrated := openDatabase(t, "migrated.db")
+	if _, err := Up(migrated, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	// The tables the entities describe, a difference means an entity changed without migration
+	described := openDatabase(t, "described.db")
+	if err := described.AutoMigrate(&entity.Attendance{}, &entity.Activity{}, &entity.User{}, &entity.OfficeNetwork{}, &entity.KioskToken{}, &entity.WorkPolicy{}, &entity.Department{}, &entity.Leave{}, &entity.Holiday{}, &entity.Absence{}, &entity.WebhookSubscription{}, &entity.WebhookDelivery{}, &entity.OutboxEvent{}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, want := schema(t, migrated), schema(t, described)
+	for name, statement := range want {
+		if got[name] != statement {
+			t.Errorf("%s\n got: %s\nwant: %s", name, got[name], statement)
+		}
+	}
+	for name := range got {
+		if _, ok := want[name]; !ok {
+			t.Errorf("%s is created by the migrations but not described by an entity", name)
+		}
+	}
+}
+
+func TestUpDownStatus(t *testing.T) {
+	db := openDatabase(t, "test.db")
+
+	applied, err := Up(db, 2)
+	if err != nil || len(applied) != 2 {
+		t.Fatalf("Up to 2 = %d migrations, %v", len(applied), err)
+	}
+	if version, _ := Version(db); version != 2 {
+		t.Errorf("version = %d, want 2", version)
+	}
+
+	if applied, err = Up(db, 0); err != nil || len(applied) != len(Migrations)-2 {
+		t.Fatalf("Up = %d migrations, %v", len(applied), err)
+	}
+	statuses, err := Statuses(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, status := range statuses {
+		if status.AppliedAt == 0 {
+			t.Errorf("migration %d %s isn't applied", status.Version, status.Name)
+		}
+	}
+
+	reverted, err := Down(db, 1)
+	if err != nil || len(reverted) != 1 || reverted[0].Name != "user_attendance_columns" {
+		t.Fatalf("Down 1 = %+v, %v", reverted, err)
+	}
+	if db.Migrator().HasColumn(&entity.Attendance{}, "WorkMode") || !db.Migrator().HasTable("webhook_subscriptions") {
+		t.Error("Down must drop the columns of the last migration only")
+	}
+	if pending, _ := Pending(db); len(pending) != 1 || pending[0].Version != 5 {
+		t.Errorf("pending = %+v, want user_attendance_columns", pending)
+	}
+
+	if _, err := Down(db, len(Migrations)); err != nil {
+		t.Fatal(err)
+	}
+	if db.Migrator().HasTable("users") {
+		t.Error("users must be dropped when every migration is reverted")
+	}
+	if version, _ := Version(db); version != 0 {
+		t.Errorf("version = %d, want 0", version)
+	}
+}
+
+func TestBaselineKeepsExistingTables(t *testing.T) {
+	db := openDatabase(t, "test.db")
+	if err := db.AutoMigrate(&entity.User{}, &entity.Attendance{}, &entity.Activity{}); err != nil {
+		t.Fatal(err)
+	}
+	db.Create(&entity.User{Name: "Ana", Email: "ana@example.com"})
+
+	if _, err := Up(db, 0); err != nil {
+		t.Fatal(err)
+	}
+	var users int64
+	db.Model(&entity.User{}).Count(&users)
+	if users != 1 {
+		t.Errorf("users = %d, want the existing row kept", users)
+	}
+}
+
+func TestBaselineAddsColumnsToOriginalTables(t *testing.T) {
+	db := openDatabase(t, "test.db")
+	if err := db.AutoMigrate(&baselineUser{}, &baselineAttendance{}, &baselineActivity{}); err != nil {
+		t.Fatal(err)
+	}
+	db.Create(&baselineUser{Name: "Ana", Email: "ana@example.com"})
+
+	if _, err := Up(db, 0); err != nil {
+		t.Fatal(err)
+	}
+	for _, field := range []string{"Role", "DepartmentId", "CreatedAt"} {
+		if !db.Migrator().HasColumn(&entity.User{}, field) {
+			t.Errorf("users has no %s column", field)
+		}
+	}
+	if !db.Migrator().HasColumn(&entity.Attendance{}, "WorkMode") || !db.Migrator().HasIndex(&entity.Activity{}, "idx_activity_user_date") {
+		t.Error("the attendance columns and history indexes must be added")
+	}
+	var user entity.User
+	if err := db.First(&user).Error; err != nil || user.Role != entity.RoleEmployee {
+		t.Errorf("user = %+v, %v, want the existing row with the default role", user, err)
+	}
+}
+
+func TestFailedMigrationIsRolledBack(t *testing.T) {
+	db := openDatabase(t, "test.db")
+	failing := Migration{
+		Version: 2,
+		Name:    "failing",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Exec("CREATE TABLE half_done (id integer)").Error; err != nil {
+				return err
+			}
+			return errors.New("backfill failed")
+		},
+	}
+
+	applied, err := up(db, []Migration{baseline, failing}, 0)
+	if err == nil || len(applied) != 1 {
+		t.Fatalf("up = %d migrations, %v, want the baseline and an error", len(applied), err)
+	}
+	if db.Migrator().HasTable("half_done") {
+		t.Error("the failed migration must be rolled back")
+	}
+	if version, _ := Version(db); version != 1 {
+		t.Errorf("version = %d, want 1", version)
+	}
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"armiariyan/attendances-system/entity"
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AbsenceRepository interface {
+	SaveAbsences(ctx context.Context, date string, absences []entity.Absence) error
+	GetAbsencesByDate(ctx context.Context, startDate, endDate string) ([]entity.Absence, error)
+}
+
+type absenceConnection struct {
+	connection *gorm.DB
+}
+
+// Construct
+func NewAbsenceRepository(db *gorm.DB) AbsenceRepository {
+	return &absenceConnection{
+		connection: db,
+	}
+}
+
+// SaveAbsences replaces the absences of a date, so detecting a date twice is safe
+func (db *absenceConnection) SaveAbsences(ctx context.Context, date string, absences []entity.Absence) error {
+	return translate(db.connection.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var user_ids []int
+		for _, absence := range absences {
+			user_ids = append(user_ids, absence.UserId)
+		}
+
+		// Days explained since the last run are no absence anymore
+		query := tx.Where("date = ?", date)
+		if len(user_ids) > 0 {
+			query = query.Where("user_id NOT IN ?", user_ids)
+		}
+		if err := query.Delete(&entity.Absence{}).Error; err != nil {
+			return err
+		}
+
+		if len(absences) == 0 {
+			return nil
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&absences).Error
+	}))
+}
+
+func (db *absenceConnection) GetAbsencesByDate(ctx context.Context, startDate, endDate string) ([]entity.Absence, error) {
+	var absences []entity.Absence
+	err := db.connection.WithContext(ctx).Where("date >= ? AND date <= ?", startDate, endDate).Order("date").Find(&absences).Error
+	return absences, translate(err)
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"armiariyan/attendances-system/entity"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// ActivityRepository stores what users report to have worked on
+type ActivityRepository interface {
+	GetActivityById(ctx context.Context, act_id string) (entity.Activity, error)
+	CreateActivity(ctx context.Context, data entity.Activity) (entity.Activity, error)
+	UpdateActivity(ctx context.Context, data entity.Activity) (entity.Activity, error)
+	DeleteActivity(ctx context.Context, activity entity.Activity) error
+	GetActivitiesByDate(ctx context.Context, user_id int, startDate, endDate int64) ([]entity.Activity, error)
+	GetActivitiesPage(ctx context.Context, user_id int, options HistoryOptions) ([]entity.Activity, error)
+	AddEvent(ctx context.Context, event entity.OutboxEvent) error
+	Transaction(ctx context.Context, fn func(tx ActivityRepository) error) error
+}
+
+type activityConnection struct {
+	connection *gorm.DB
+}
+
+// Construct
+func NewActivityRepository(db *gorm.DB) ActivityRepository {
+	return &activityConnection{
+		connection: db,
+	}
+}
+
+// Transaction runs fn with a repository bound to one database transaction,
+// it is rolled back when fn returns an error
+func (db *activityConnection) Transaction(ctx context.Context, fn func(tx ActivityRepository) error) error {
+	return translate(db.connection.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&activityConnection{connection: tx})
+	}))
+}
+
+func (db *activityConnection) AddEvent(ctx context.Context, event entity.OutboxEvent) error {
+	return translate(addEvent(db.connection.WithContext(ctx), event))
+}
+
+func (db *activityConnection) GetActivityById(ctx context.Context, act_id string) (entity.Activity, error) {
+	var activity entity.Activity
+	err := db.connection.WithContext(ctx).First(&activity, "id = ?", act_id).Error
+	return activity, translate(err)
+}
+
+func (db *activityConnection) CreateActivity(ctx context.Context, data entity.Activity) (entity.Activity, error) {
+	err := db.connection.WithContext(ctx).Create(&data).Error
+	return data, translate(err)
+}
+
+// UpdateActivity returns ErrNotFound when the activity doesn't exist anymore
+func (db *activityConnection) UpdateActivity(ctx context.Context, activity entity.Activity) (entity.Activity, error) {
+	if err := notFoundUnlessAffected(db.connection.WithContext(ctx).Where("id = ?", activity.Id).Updates(&activity)); err != nil {
+		return activity, err
+	}
+	err := db.connection.WithContext(ctx).Take(&activity, "id = ?", activity.Id).Error
+	return activity, translate(err)
+}
+
+// DeleteActivity returns ErrNotFound when the activity was already deleted
+func (db *activityConnection) DeleteActivity(ctx context.Context, activity entity.Activity) error {
+	return notFoundUnlessAffected(db.connection.WithContext(ctx).Delete(&activity))
+}
+
+func (db *activityConnection) GetActivitiesByDate(ctx context.Context, user_id int, startDate, endDate int64) ([]entity.Activity, error) {
+	var activities []entity.Activity
+	err := db.connection.WithContext(ctx).Where("user_id = ? AND date_created >= ? AND date_created <= ?", user_id, startDate, endDate).Order("date_created").Find(&activities).Error
+	return activities, translate(err)
+}
+
+// GetActivitiesPage returns up to options.Limit+1 activities, the search looks into the description
+func (db *activityConnection) GetActivitiesPage(ctx context.Context, user_id int, options HistoryOptions) ([]entity.Activity, error) {
+	var activities []entity.Activity
+	query := db.connection.WithContext(ctx).Where("user_id = ?", user_id)
+	if options.Search != "" {
+		query = query.Where("LOWER(description) LIKE ? ESCAPE '!'", likePattern(options.Search))
+	}
+	err := options.page(query, "date_created").Find(&activities).Error
+	return activities, translate(err)
+}
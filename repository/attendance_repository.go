@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"armiariyan/attendances-system/entity"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// AttendanceRepository stores the punches of every user, check ins, breaks and check outs alike
+type AttendanceRepository interface {
+	CreateAttendance(ctx context.Context, data entity.Attendance) (entity.Attendance, error)
+	GetAttendancesHistory(ctx context.Context, user_id int) ([]entity.Attendance, error)
+	GetAttendancesByDate(ctx context.Context, user_id int, startDate, endDate int64) ([]entity.Attendance, error)
+	GetAllAttendancesByDate(ctx context.Context, startDate, endDate int64) ([]entity.Attendance, error)
+	GetAttendancesPage(ctx context.Context, user_id int, options HistoryOptions) ([]entity.Attendance, error)
+	AddEvent(ctx context.Context, event entity.OutboxEvent) error
+	Transaction(ctx context.Context, fn func(tx AttendanceRepository) error) error
+}
+
+type attendanceConnection struct {
+	connection *gorm.DB
+}
+
+// Construct
+func NewAttendanceRepository(db *gorm.DB) AttendanceRepository {
+	return &attendanceConnection{
+		connection: db,
+	}
+}
+
+// Transaction runs fn with a repository bound to one database transaction,
+// it is rolled back when fn returns an error
+func (db *attendanceConnection) Transaction(ctx context.Context, fn func(tx AttendanceRepository) error) error {
+	return translate(db.connection.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&attendanceConnection{connection: tx})
+	}))
+}
+
+func (db *attendanceConnection) AddEvent(ctx context.Context, event entity.OutboxEvent) error {
+	return translate(addEvent(db.connection.WithContext(ctx), event))
+}
+
+func (db *attendanceConnection) CreateAttendance(ctx context.Context, data entity.Attendance) (entity.Attendance, error) {
+	err := db.connection.WithContext(ctx).Create(&data).Error
+	return data, translate(err)
+}
+
+func (db *attendanceConnection) GetAttendancesHistory(ctx context.Context, user_id int) ([]entity.Attendance, error) {
+	var attendances []entity.Attendance
+	err := db.connection.WithContext(ctx).Find(&attendances, "user_id = ?", user_id).Error
+	return attendances, translate(err)
+}
+
+func (db *attendanceConnection) GetAttendancesByDate(ctx context.Context, user_id int, startDate, endDate int64) ([]entity.Attendance, error) {
+	var attendances []entity.Attendance
+	err := db.connection.WithContext(ctx).Where("user_id = ? AND date >= ? AND date <= ?", user_id, startDate, endDate).Order("date").Find(&attendances).Error
+	return attendances, translate(err)
+}
+
+func (db *attendanceConnection) GetAllAttendancesByDate(ctx context.Context, startDate, endDate int64) ([]entity.Attendance, error) {
+	var attendances []entity.Attendance
+	err := db.connection.WithContext(ctx).Where("date >= ? AND date <= ?", startDate, endDate).Order("date").Find(&attendances).Error
+	return attendances, translate(err)
+}
+
+// GetAttendancesPage returns up to options.Limit+1 attendances, the label filter
+// is exact and the search looks into location and work mode
+func (db *attendanceConnection) GetAttendancesPage(ctx context.Context, user_id int, options HistoryOptions) ([]entity.Attendance, error) {
+	var attendances []entity.Attendance
+	query := db.connection.WithContext(ctx).Where("user_id = ?", user_id)
+	if options.Label != "" {
+		query = query.Where("label = ?", options.Label)
+	}
+	if options.Search != "" {
+		pattern := likePattern(options.Search)
+		query = query.Where("LOWER(location) LIKE ? ESCAPE '!' OR LOWER(work_mode) LIKE ? ESCAPE '!'", pattern, pattern)
+	}
+	err := options.page(query, "date").Find(&attendances).Error
+	return attendances, translate(err)
+}
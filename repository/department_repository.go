@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"armiariyan/attendances-system/entity"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// DepartmentRepository returns its errors translated like the user repository,
+// ErrNotFound for a missing department
+type DepartmentRepository interface {
+	GetDepartments(ctx context.Context) ([]entity.Department, error)
+	GetDepartmentById(ctx context.Context, department_id int) (entity.Department, error)
+	CreateDepartment(ctx context.Context, data entity.Department) (entity.Department, error)
+	UpdateDepartment(ctx context.Context, data entity.Department) (entity.Department, error)
+	DeleteDepartment(ctx context.Context, department entity.Department) error
+	GetMembers(ctx context.Context, department_id int) ([]entity.User, error)
+	MoveUser(ctx context.Context, user_id int, department_id *int) error
+}
+
+type departmentConnection struct {
+	connection *gorm.DB
+}
+
+// Construct
+func NewDepartmentRepository(db *gorm.DB) DepartmentRepository {
+	return &departmentConnection{
+		connection: db,
+	}
+}
+
+func (db *departmentConnection) GetDepartments(ctx context.Context) ([]entity.Department, error) {
+	var departments []entity.Department
+	err := db.connection.WithContext(ctx).Find(&departments).Error
+	return departments, translate(err)
+}
+
+func (db *departmentConnection) GetDepartmentById(ctx context.Context, department_id int) (entity.Department, error) {
+	var department entity.Department
+	err := db.connection.WithContext(ctx).First(&department, "id = ?", department_id).Error
+	return department, translate(err)
+}
+
+func (db *departmentConnection) CreateDepartment(ctx context.Context, data entity.Department) (entity.Department, error) {
+	err := db.connection.WithContext(ctx).Create(&data).Error
+	return data, translate(err)
+}
+
+func (db *departmentConnection) UpdateDepartment(ctx context.Context, data entity.Department) (entity.Department, error) {
+	// Select all so manager and parent can be set back to null
+	err := db.connection.WithContext(ctx).Model(&data).Select("name", "manager_id", "parent_id").Updates(&data).Error
+	return data, translate(err)
+}
+
+func (db *departmentConnection) DeleteDepartment(ctx context.Context, department entity.Department) error {
+	return translate(db.connection.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Members without department are moved out first
+		if err := tx.Model(&entity.User{}).Where("department_id = ?", department.Id).Update("department_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&department).Error
+	}))
+}
+
+func (db *departmentConnection) GetMembers(ctx context.Context, department_id int) ([]entity.User, error) {
+	var users []entity.User
+	err := db.connection.WithContext(ctx).Find(&users, "department_id = ?", department_id).Error
+	return users, translate(err)
+}
+
+func (db *departmentConnection) MoveUser(ctx context.Context, user_id int, department_id *int) error {
+	return translate(db.connection.WithContext(ctx).Model(&entity.User{}).Where("id = ?", user_id).Update("department_id", department_id).Error)
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgconn"
+	"github.com/mattn/go-sqlite3"
+	"gorm.io/gorm"
+)
+
+// Domain errors the repositories wrap the database errors into, so the layers above
+// can tell a missing row from a duplicate or a database that can't be reached
+// whatever the driver. The database error stays wrapped for the logs
+var (
+	ErrNotFound    = errors.New("not found")
+	ErrConflict    = errors.New("conflicts with existing data")
+	ErrUnavailable = errors.New("database unavailable")
+)
+
+// translate wraps err into the domain error it means, an error of no known kind is
+// returned as it is and nil stays nil
+func translate(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, ErrNotFound), errors.Is(err, ErrConflict), errors.Is(err, ErrUnavailable):
+		return err
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return fmt.Errorf("%w: %v", ErrNotFound, err)
+	case isConflict(err):
+		return fmt.Errorf("%w: %v", ErrConflict, err)
+	case isUnavailable(err):
+		return fmt.Errorf("%w: %v", ErrUnavailable, err)
+	default:
+		return err
+	}
+}
+
+// notFoundUnlessAffected returns ErrNotFound when the query touched no row
+func notFoundUnlessAffected(res *gorm.DB) error {
+	if res.Error != nil {
+		return translate(res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func isConflict(err error) bool {
+	var mysqlErr *mysqldriver.MySQLError
+	if errors.As(err, &mysqlErr) {
+		// Duplicate entry, duplicate key, foreign key failing
+		return mysqlErr.Number == 1062 || mysqlErr.Number == 1586 || mysqlErr.Number == 1451 || mysqlErr.Number == 1452
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		// Integrity constraint violations
+		return strings.HasPrefix(pgErr.Code, "23")
+	}
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.Code == sqlite3.ErrConstraint
+	}
+	return false
+}
+
+func isUnavailable(err error) bool {
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) || errors.Is(err, mysqldriver.ErrInvalidConn) ||
+		errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return true
+	}
+	// database/sql doesn't export the error of a closed pool, seen while shutting down
+	if strings.Contains(err.Error(), "sql: database is closed") {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	var mysqlErr *mysqldriver.MySQLError
+	if errors.As(err, &mysqlErr) {
+		// Too many connections, lock wait timeout, deadlock
+		return mysqlErr.Number == 1040 || mysqlErr.Number == 1205 || mysqlErr.Number == 1213
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		// Connection exceptions, insufficient resources, operator intervention, serialization failures
+		return strings.HasPrefix(pgErr.Code, "08") || strings.HasPrefix(pgErr.Code, "53") || strings.HasPrefix(pgErr.Code, "57") || strings.HasPrefix(pgErr.Code, "40")
+	}
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.Code == sqlite3.ErrBusy || sqliteErr.Code == sqlite3.ErrLocked
+	}
+	return pgconn.Timeout(err)
+}
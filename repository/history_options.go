@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// HistoryOptions narrows and orders a history query, zero values don't filter.
+// The page starts after the row at CursorTime/CursorId in the sort direction
+type HistoryOptions struct {
+	Limit      int
+	Descending bool
+	CursorTime int64
+	CursorId   string
+	Label      string
+	StartDate  int64
+	EndDate    int64
+	Search     string
+}
+
+// page applies the date range, cursor, order and limit on timeColumn with id
+// as tie breaker, one extra row is fetched to know whether there is a next page
+func (options HistoryOptions) page(query *gorm.DB, timeColumn string) *gorm.DB {
+	if options.StartDate != 0 {
+		query = query.Where(timeColumn+" >= ?", options.StartDate)
+	}
+	if options.EndDate != 0 {
+		query = query.Where(timeColumn+" <= ?", options.EndDate)
+	}
+
+	direction, compare := "asc", ">"
+	if options.Descending {
+		direction, compare = "desc", "<"
+	}
+	if options.CursorId != "" {
+		query = query.Where(timeColumn+" "+compare+" ? OR ("+timeColumn+" = ? AND id "+compare+" ?)", options.CursorTime, options.CursorTime, options.CursorId)
+	}
+	return query.Order(timeColumn + " " + direction).Order("id " + direction).Limit(options.Limit + 1)
+}
+
+// likePattern matches value anywhere, with ! escaping the wildcards of the value
+func likePattern(value string) string {
+	value = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_").Replace(strings.ToLower(value))
+	return "%" + value + "%"
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"armiariyan/attendances-system/entity"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// HolidayRepository returns its errors translated like the user repository,
+// ErrNotFound for a missing holiday and ErrConflict for a taken date
+type HolidayRepository interface {
+	GetHolidaysByDate(ctx context.Context, startDate, endDate string) ([]entity.Holiday, error)
+	GetHolidayById(ctx context.Context, holiday_id int) (entity.Holiday, error)
+	GetHolidayByDate(ctx context.Context, date string) (entity.Holiday, error)
+	CreateHoliday(ctx context.Context, data entity.Holiday) (entity.Holiday, error)
+	DeleteHoliday(ctx context.Context, holiday entity.Holiday) error
+}
+
+type holidayConnection struct {
+	connection *gorm.DB
+}
+
+// Construct
+func NewHolidayRepository(db *gorm.DB) HolidayRepository {
+	return &holidayConnection{
+		connection: db,
+	}
+}
+
+func (db *holidayConnection) GetHolidaysByDate(ctx context.Context, startDate, endDate string) ([]entity.Holiday, error) {
+	var holidays []entity.Holiday
+	err := db.connection.WithContext(ctx).Where("date >= ? AND date <= ?", startDate, endDate).Order("date").Find(&holidays).Error
+	return holidays, translate(err)
+}
+
+func (db *holidayConnection) GetHolidayById(ctx context.Context, holiday_id int) (entity.Holiday, error) {
+	var holiday entity.Holiday
+	err := db.connection.WithContext(ctx).First(&holiday, "id = ?", holiday_id).Error
+	return holiday, translate(err)
+}
+
+func (db *holidayConnection) GetHolidayByDate(ctx context.Context, date string) (entity.Holiday, error) {
+	var holiday entity.Holiday
+	err := db.connection.WithContext(ctx).First(&holiday, "date = ?", date).Error
+	return holiday, translate(err)
+}
+
+func (db *holidayConnection) CreateHoliday(ctx context.Context, data entity.Holiday) (entity.Holiday, error) {
+	err := db.connection.WithContext(ctx).Create(&data).Error
+	return data, translate(err)
+}
+
+func (db *holidayConnection) DeleteHoliday(ctx context.Context, holiday entity.Holiday) error {
+	return translate(db.connection.WithContext(ctx).Delete(&holiday).Error)
+}
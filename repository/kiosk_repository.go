@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"armiariyan/attendances-system/entity"
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// KioskRepository returns its errors translated like the user repository
+type KioskRepository interface {
+	UseToken(ctx context.Context, data entity.KioskToken) (bool, error)
+	DeleteTokensUsedBefore(ctx context.Context, usedAt int64) error
+}
+
+type kioskConnection struct {
+	connection *gorm.DB
+}
+
+// Construct
+func NewKioskRepository(db *gorm.DB) KioskRepository {
+	return &kioskConnection{
+		connection: db,
+	}
+}
+
+// UseToken stores the token and returns false when it was already used by the same user
+func (db *kioskConnection) UseToken(ctx context.Context, data entity.KioskToken) (bool, error) {
+	res := db.connection.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&data)
+	if res.Error != nil {
+		return false, translate(res.Error)
+	}
+	return res.RowsAffected == 1, nil
+}
+
+func (db *kioskConnection) DeleteTokensUsedBefore(ctx context.Context, usedAt int64) error {
+	return translate(db.connection.WithContext(ctx).Where("used_at < ?", usedAt).Delete(&entity.KioskToken{}).Error)
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"armiariyan/attendances-system/entity"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type LeaveRepository interface {
+	CreateLeave(ctx context.Context, data entity.Leave) (entity.Leave, error)
+	GetLeaveById(ctx context.Context, leave_id int) entity.Leave
+	GetLeavesByUser(ctx context.Context, user_id int) []entity.Leave
+	UpdateLeave(ctx context.Context, data entity.Leave) (entity.Leave, error)
+	GetApprovedLeavesByDate(ctx context.Context, startDate, endDate string) []entity.Leave
+}
+
+type leaveConnection struct {
+	connection *gorm.DB
+}
+
+// Construct
+func NewLeaveRepository(db *gorm.DB) LeaveRepository {
+	return &leaveConnection{
+		connection: db,
+	}
+}
+
+func (db *leaveConnection) CreateLeave(ctx context.Context, data entity.Leave) (entity.Leave, error) {
+	err := db.connection.WithContext(ctx).Create(&data).Error
+	return data, translate(err)
+}
+
+func (db *leaveConnection) GetLeaveById(ctx context.Context, leave_id int) entity.Leave {
+	var leave entity.Leave
+	db.connection.WithContext(ctx).First(&leave, "id = ?", leave_id)
+	return leave
+}
+
+func (db *leaveConnection) GetLeavesByUser(ctx context.Context, user_id int) []entity.Leave {
+	var leaves []entity.Leave
+	db.connection.WithContext(ctx).Where("user_id = ?", user_id).Order("start_date desc").Find(&leaves)
+	return leaves
+}
+
+func (db *leaveConnection) UpdateLeave(ctx context.Context, data entity.Leave) (entity.Leave, error) {
+	err := db.connection.WithContext(ctx).Save(&data).Error
+	return data, translate(err)
+}
+
+// GetApprovedLeavesByDate returns approved leaves overlapping the range, dates are 2006-01-02
+func (db *leaveConnection) GetApprovedLeavesByDate(ctx context.Context, startDate, endDate string) []entity.Leave {
+	var leaves []entity.Leave
+	db.connection.WithContext(ctx).Where("status = ? AND start_date <= ? AND end_date >= ?", entity.LeaveStatusApproved, endDate, startDate).Find(&leaves)
+	return leaves
+}
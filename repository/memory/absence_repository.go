@@ -0,0 +1,66 @@
+package memory
+
+import (
+	"armiariyan/attendances-system/entity"
+	"armiariyan/attendances-system/repository"
+	"context"
+	"sort"
+)
+
+type absenceRepository struct {
+	store *Store
+}
+
+// Construct
+func NewAbsenceRepository(store *Store) repository.AbsenceRepository {
+	return &absenceRepository{
+		store: store,
+	}
+}
+
+// SaveAbsences replaces the absences of a date, so detecting a date twice is safe
+func (db *absenceRepository) SaveAbsences(_ context.Context, date string, absences []entity.Absence) error {
+	db.store.do(func(tables *tables) {
+		absent := map[int]bool{}
+		for _, absence := range absences {
+			absent[absence.UserId] = true
+		}
+
+		// Days explained since the last run are no absence anymore
+		var kept []entity.Absence
+		saved := map[int]bool{}
+		for _, absence := range tables.absences {
+			if absence.Date != date || absent[absence.UserId] {
+				kept = append(kept, absence)
+			}
+			if absence.Date == date {
+				saved[absence.UserId] = true
+			}
+		}
+		tables.absences = kept
+
+		for _, absence := range absences {
+			if saved[absence.UserId] || absence.Date != date {
+				continue
+			}
+			absence.Id = tables.nextId("absences")
+			tables.absences = append(tables.absences, absence)
+			saved[absence.UserId] = true
+		}
+	})
+	return nil
+}
+
+func (db *absenceRepository) GetAbsencesByDate(_ context.Context, startDate, endDate string) (absences []entity.Absence, err error) {
+	db.store.do(func(tables *tables) {
+		for _, absence := range tables.absences {
+			if absence.Date >= startDate && absence.Date <= endDate {
+				absences = append(absences, absence)
+			}
+		}
+	})
+	sort.SliceStable(absences, func(i, j int) bool {
+		return absences[i].Date < absences[j].Date
+	})
+	return absences, nil
+}
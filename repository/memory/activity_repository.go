@@ -0,0 +1,141 @@
+package memory
+
+import (
+	"armiariyan/attendances-system/entity"
+	"armiariyan/attendances-system/repository"
+	"context"
+	"sort"
+)
+
+type activityRepository struct {
+	store *Store
+	inTx  bool
+}
+
+// Construct
+func NewActivityRepository(store *Store) repository.ActivityRepository {
+	return &activityRepository{
+		store: store,
+	}
+}
+
+func (db *activityRepository) Transaction(_ context.Context, fn func(tx repository.ActivityRepository) error) error {
+	if db.inTx {
+		return fn(db)
+	}
+	return db.store.inTransaction(func() error {
+		return fn(&activityRepository{store: db.store, inTx: true})
+	})
+}
+
+func (db *activityRepository) AddEvent(_ context.Context, event entity.OutboxEvent) (err error) {
+	db.store.do(func(tables *tables) {
+		err = tables.addEvent(event)
+	})
+	return err
+}
+
+func (db *activityRepository) GetActivityById(_ context.Context, act_id string) (activity entity.Activity, err error) {
+	err = repository.ErrNotFound
+	db.store.do(func(tables *tables) {
+		if i := tables.activityIndex(act_id); i >= 0 {
+			activity, err = tables.activities[i], nil
+		}
+	})
+	return activity, err
+}
+
+// CreateActivity returns ErrConflict for a taken id or an unknown user
+func (db *activityRepository) CreateActivity(_ context.Context, data entity.Activity) (entity.Activity, error) {
+	var err error
+	db.store.do(func(tables *tables) {
+		if !tables.userExists(data.UserId) || tables.activityIndex(data.Id) >= 0 {
+			err = repository.ErrConflict
+			return
+		}
+		tables.activities = append(tables.activities, data)
+	})
+	return data, err
+}
+
+// UpdateActivity updates the fields set in activity, ErrNotFound when the activity doesn't exist anymore
+func (db *activityRepository) UpdateActivity(_ context.Context, activity entity.Activity) (entity.Activity, error) {
+	err := repository.ErrNotFound
+	db.store.do(func(tables *tables) {
+		i := tables.activityIndex(activity.Id)
+		if i < 0 {
+			return
+		}
+		saved := &tables.activities[i]
+		if activity.UserId != 0 {
+			saved.UserId = activity.UserId
+		}
+		if activity.Description != "" {
+			saved.Description = activity.Description
+		}
+		if activity.DateCreated != 0 {
+			saved.DateCreated = activity.DateCreated
+		}
+		if activity.TimeCreated != 0 {
+			saved.TimeCreated = activity.TimeCreated
+		}
+		activity, err = *saved, nil
+	})
+	return activity, err
+}
+
+// DeleteActivity returns ErrNotFound when the activity was already deleted
+func (db *activityRepository) DeleteActivity(_ context.Context, activity entity.Activity) error {
+	err := repository.ErrNotFound
+	db.store.do(func(tables *tables) {
+		if i := tables.activityIndex(activity.Id); i >= 0 {
+			tables.activities = append(tables.activities[:i], tables.activities[i+1:]...)
+			err = nil
+		}
+	})
+	return err
+}
+
+func (db *activityRepository) GetActivitiesByDate(_ context.Context, user_id int, startDate, endDate int64) ([]entity.Activity, error) {
+	activities := db.filter(func(activity entity.Activity) bool {
+		return activity.UserId == user_id && activity.DateCreated >= startDate && activity.DateCreated <= endDate
+	})
+	sort.SliceStable(activities, func(i, j int) bool {
+		return activities[i].DateCreated < activities[j].DateCreated
+	})
+	return activities, nil
+}
+
+// GetActivitiesPage returns up to options.Limit+1 activities, the search looks into the description
+func (db *activityRepository) GetActivitiesPage(_ context.Context, user_id int, options repository.HistoryOptions) ([]entity.Activity, error) {
+	matching := db.filter(func(activity entity.Activity) bool {
+		return activity.UserId == user_id && (options.Search == "" || contains(activity.Description, options.Search))
+	})
+
+	var activities []entity.Activity
+	for _, i := range page(options, len(matching), func(i int) int64 { return matching[i].DateCreated }, func(i int) string { return matching[i].Id }) {
+		activities = append(activities, matching[i])
+	}
+	return activities, nil
+}
+
+// filter returns the activities keep is true for, in the order they were created
+func (db *activityRepository) filter(keep func(activity entity.Activity) bool) (activities []entity.Activity) {
+	db.store.do(func(tables *tables) {
+		for _, activity := range tables.activities {
+			if keep(activity) {
+				activities = append(activities, activity)
+			}
+		}
+	})
+	return activities
+}
+
+func (tables *tables) activityIndex(act_id string) int {
+	for i, activity := range tables.activities {
+		if activity.Id == act_id {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,115 @@
+package memory
+
+import (
+	"armiariyan/attendances-system/entity"
+	"armiariyan/attendances-system/repository"
+	"context"
+	"sort"
+)
+
+type attendanceRepository struct {
+	store *Store
+	inTx  bool
+}
+
+// Construct
+func NewAttendanceRepository(store *Store) repository.AttendanceRepository {
+	return &attendanceRepository{
+		store: store,
+	}
+}
+
+func (db *attendanceRepository) Transaction(_ context.Context, fn func(tx repository.AttendanceRepository) error) error {
+	if db.inTx {
+		return fn(db)
+	}
+	return db.store.inTransaction(func() error {
+		return fn(&attendanceRepository{store: db.store, inTx: true})
+	})
+}
+
+func (db *attendanceRepository) AddEvent(_ context.Context, event entity.OutboxEvent) (err error) {
+	db.store.do(func(tables *tables) {
+		err = tables.addEvent(event)
+	})
+	return err
+}
+
+// CreateAttendance returns ErrConflict for a taken id or an unknown user
+func (db *attendanceRepository) CreateAttendance(_ context.Context, data entity.Attendance) (entity.Attendance, error) {
+	var err error
+	db.store.do(func(tables *tables) {
+		if !tables.userExists(data.UserId) {
+			err = repository.ErrConflict
+			return
+		}
+		for _, saved := range tables.attendances {
+			if saved.Id == data.Id {
+				err = repository.ErrConflict
+				return
+			}
+		}
+		tables.attendances = append(tables.attendances, data)
+	})
+	return data, err
+}
+
+func (db *attendanceRepository) GetAttendancesHistory(_ context.Context, user_id int) ([]entity.Attendance, error) {
+	return db.filter(func(attendance entity.Attendance) bool {
+		return attendance.UserId == user_id
+	}), nil
+}
+
+func (db *attendanceRepository) GetAttendancesByDate(_ context.Context, user_id int, startDate, endDate int64) ([]entity.Attendance, error) {
+	attendances := db.filter(func(attendance entity.Attendance) bool {
+		return attendance.UserId == user_id && attendance.Date >= startDate && attendance.Date <= endDate
+	})
+	sortByDate(attendances)
+	return attendances, nil
+}
+
+func (db *attendanceRepository) GetAllAttendancesByDate(_ context.Context, startDate, endDate int64) ([]entity.Attendance, error) {
+	attendances := db.filter(func(attendance entity.Attendance) bool {
+		return attendance.Date >= startDate && attendance.Date <= endDate
+	})
+	sortByDate(attendances)
+	return attendances, nil
+}
+
+// GetAttendancesPage returns up to options.Limit+1 attendances, the label filter
+// is exact and the search looks into location and work mode
+func (db *attendanceRepository) GetAttendancesPage(_ context.Context, user_id int, options repository.HistoryOptions) ([]entity.Attendance, error) {
+	matching := db.filter(func(attendance entity.Attendance) bool {
+		if attendance.UserId != user_id {
+			return false
+		}
+		if options.Label != "" && attendance.Label != options.Label {
+			return false
+		}
+		return options.Search == "" || contains(attendance.Location, options.Search) || contains(attendance.WorkMode, options.Search)
+	})
+
+	var attendances []entity.Attendance
+	for _, i := range page(options, len(matching), func(i int) int64 { return matching[i].Date }, func(i int) string { return matching[i].Id }) {
+		attendances = append(attendances, matching[i])
+	}
+	return attendances, nil
+}
+
+// filter returns the attendances keep is true for, in the order they were created
+func (db *attendanceRepository) filter(keep func(attendance entity.Attendance) bool) (attendances []entity.Attendance) {
+	db.store.do(func(tables *tables) {
+		for _, attendance := range tables.attendances {
+			if keep(attendance) {
+				attendances = append(attendances, attendance)
+			}
+		}
+	})
+	return attendances
+}
+
+func sortByDate(attendances []entity.Attendance) {
+	sort.SliceStable(attendances, func(i, j int) bool {
+		return attendances[i].Date < attendances[j].Date
+	})
+}
@@ -0,0 +1,99 @@
+package memory
+
+import (
+	"armiariyan/attendances-system/entity"
+	"armiariyan/attendances-system/repository"
+	"context"
+)
+
+type departmentRepository struct {
+	store *Store
+}
+
+// Construct
+func NewDepartmentRepository(store *Store) repository.DepartmentRepository {
+	return &departmentRepository{
+		store: store,
+	}
+}
+
+func (db *departmentRepository) GetDepartments(_ context.Context) (departments []entity.Department, err error) {
+	db.store.do(func(tables *tables) {
+		departments = append(departments, tables.departments...)
+	})
+	return departments, nil
+}
+
+func (db *departmentRepository) GetDepartmentById(_ context.Context, department_id int) (department entity.Department, err error) {
+	err = repository.ErrNotFound
+	db.store.do(func(tables *tables) {
+		for _, saved := range tables.departments {
+			if saved.Id == department_id {
+				department, err = saved, nil
+				return
+			}
+		}
+	})
+	return department, err
+}
+
+func (db *departmentRepository) CreateDepartment(_ context.Context, data entity.Department) (entity.Department, error) {
+	db.store.do(func(tables *tables) {
+		data.Id = tables.nextId("departments")
+		tables.departments = append(tables.departments, data)
+	})
+	return data, nil
+}
+
+func (db *departmentRepository) UpdateDepartment(_ context.Context, data entity.Department) (entity.Department, error) {
+	db.store.do(func(tables *tables) {
+		for i, saved := range tables.departments {
+			if saved.Id == data.Id {
+				tables.departments[i].Name = data.Name
+				tables.departments[i].ManagerId = data.ManagerId
+				tables.departments[i].ParentId = data.ParentId
+			}
+		}
+	})
+	return data, nil
+}
+
+func (db *departmentRepository) DeleteDepartment(_ context.Context, department entity.Department) error {
+	db.store.do(func(tables *tables) {
+		// Members without department are moved out first
+		for i, user := range tables.users {
+			if user.DepartmentId != nil && *user.DepartmentId == department.Id {
+				tables.users[i].DepartmentId = nil
+			}
+		}
+		for i, saved := range tables.departments {
+			if saved.Id == department.Id {
+				tables.departments = append(tables.departments[:i], tables.departments[i+1:]...)
+				return
+			}
+		}
+	})
+	return nil
+}
+
+func (db *departmentRepository) GetMembers(_ context.Context, department_id int) (users []entity.User, err error) {
+	db.store.do(func(tables *tables) {
+		for _, user := range tables.users {
+			if user.DepartmentId != nil && *user.DepartmentId == department_id {
+				users = append(users, user)
+			}
+		}
+	})
+	return users, nil
+}
+
+func (db *departmentRepository) MoveUser(_ context.Context, user_id int, department_id *int) error {
+	db.store.do(func(tables *tables) {
+		for i, user := range tables.users {
+			if user.Id == user_id {
+				tables.users[i].DepartmentId = department_id
+			}
+		}
+	})
+	return nil
+}
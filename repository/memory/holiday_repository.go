@@ -0,0 +1,87 @@
+package memory
+
+import (
+	"armiariyan/attendances-system/entity"
+	"armiariyan/attendances-system/repository"
+	"context"
+	"sort"
+)
+
+type holidayRepository struct {
+	store *Store
+}
+
+// Construct
+func NewHolidayRepository(store *Store) repository.HolidayRepository {
+	return &holidayRepository{
+		store: store,
+	}
+}
+
+func (db *holidayRepository) GetHolidaysByDate(_ context.Context, startDate, endDate string) (holidays []entity.Holiday, err error) {
+	db.store.do(func(tables *tables) {
+		for _, holiday := range tables.holidays {
+			if holiday.Date >= startDate && holiday.Date <= endDate {
+				holidays = append(holidays, holiday)
+			}
+		}
+	})
+	sort.SliceStable(holidays, func(i, j int) bool {
+		return holidays[i].Date < holidays[j].Date
+	})
+	return holidays, nil
+}
+
+func (db *holidayRepository) GetHolidayById(_ context.Context, holiday_id int) (holiday entity.Holiday, err error) {
+	return db.find(func(saved entity.Holiday) bool {
+		return saved.Id == holiday_id
+	})
+}
+
+func (db *holidayRepository) GetHolidayByDate(_ context.Context, date string) (holiday entity.Holiday, err error) {
+	return db.find(func(saved entity.Holiday) bool {
+		return saved.Date == date
+	})
+}
+
+// CreateHoliday returns ErrConflict for a taken date
+func (db *holidayRepository) CreateHoliday(_ context.Context, data entity.Holiday) (entity.Holiday, error) {
+	var err error
+	db.store.do(func(tables *tables) {
+		for _, saved := range tables.holidays {
+			if saved.Date == data.Date {
+				err = repository.ErrConflict
+				return
+			}
+		}
+		data.Id = tables.nextId("holidays")
+		tables.holidays = append(tables.holidays, data)
+	})
+	return data, err
+}
+
+func (db *holidayRepository) DeleteHoliday(_ context.Context, holiday entity.Holiday) error {
+	db.store.do(func(tables *tables) {
+		for i, saved := range tables.holidays {
+			if saved.Id == holiday.Id {
+				tables.holidays = append(tables.holidays[:i], tables.holidays[i+1:]...)
+				return
+			}
+		}
+	})
+	return nil
+}
+
+// find returns the first holiday matching, ErrNotFound when none does
+func (db *holidayRepository) find(match func(holiday entity.Holiday) bool) (holiday entity.Holiday, err error) {
+	err = repository.ErrNotFound
+	db.store.do(func(tables *tables) {
+		for _, saved := range tables.holidays {
+			if match(saved) {
+				holiday, err = saved, nil
+				return
+			}
+		}
+	})
+	return holiday, err
+}
@@ -0,0 +1,50 @@
+package memory
+
+import (
+	"armiariyan/attendances-system/entity"
+	"armiariyan/attendances-system/repository"
+	"context"
+)
+
+type kioskRepository struct {
+	store *Store
+}
+
+// Construct
+func NewKioskRepository(store *Store) repository.KioskRepository {
+	return &kioskRepository{
+		store: store,
+	}
+}
+
+// UseToken stores the token and returns false when it was already used by the same user,
+// ErrConflict for an unknown user
+func (db *kioskRepository) UseToken(_ context.Context, data entity.KioskToken) (used bool, err error) {
+	db.store.do(func(tables *tables) {
+		if !tables.userExists(data.UserId) {
+			err = repository.ErrConflict
+			return
+		}
+		for _, saved := range tables.kioskTokens {
+			if saved.Nonce == data.Nonce && saved.UserId == data.UserId {
+				return
+			}
+		}
+		tables.kioskTokens = append(tables.kioskTokens, data)
+		used = true
+	})
+	return used, err
+}
+
+func (db *kioskRepository) DeleteTokensUsedBefore(_ context.Context, usedAt int64) error {
+	db.store.do(func(tables *tables) {
+		var kept []entity.KioskToken
+		for _, token := range tables.kioskTokens {
+			if token.UsedAt >= usedAt {
+				kept = append(kept, token)
+			}
+		}
+		tables.kioskTokens = kept
+	})
+	return nil
+}
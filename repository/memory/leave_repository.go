@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"armiariyan/attendances-system/entity"
+	"armiariyan/attendances-system/repository"
+	"context"
+	"sort"
+)
+
+type leaveRepository struct {
+	store *Store
+}
+
+// Construct
+func NewLeaveRepository(store *Store) repository.LeaveRepository {
+	return &leaveRepository{
+		store: store,
+	}
+}
+
+func (db *leaveRepository) CreateLeave(_ context.Context, data entity.Leave) (entity.Leave, error) {
+	db.store.do(func(tables *tables) {
+		if data.Status == "" {
+			data.Status = entity.LeaveStatusPending
+		}
+		data.Id = tables.nextId("leaves")
+		tables.leaves = append(tables.leaves, data)
+	})
+	return data, nil
+}
+
+func (db *leaveRepository) GetLeaveById(_ context.Context, leave_id int) (leave entity.Leave) {
+	db.store.do(func(tables *tables) {
+		for _, saved := range tables.leaves {
+			if saved.Id == leave_id {
+				leave = saved
+			}
+		}
+	})
+	return leave
+}
+
+func (db *leaveRepository) GetLeavesByUser(_ context.Context, user_id int) (leaves []entity.Leave) {
+	db.store.do(func(tables *tables) {
+		for _, leave := range tables.leaves {
+			if leave.UserId == user_id {
+				leaves = append(leaves, leave)
+			}
+		}
+	})
+	sort.SliceStable(leaves, func(i, j int) bool {
+		return leaves[i].StartDate > leaves[j].StartDate
+	})
+	return leaves
+}
+
+func (db *leaveRepository) UpdateLeave(_ context.Context, data entity.Leave) (entity.Leave, error) {
+	db.store.do(func(tables *tables) {
+		for i, saved := range tables.leaves {
+			if saved.Id == data.Id {
+				tables.leaves[i] = data
+				return
+			}
+		}
+		data.Id = tables.nextId("leaves")
+		tables.leaves = append(tables.leaves, data)
+	})
+	return data, nil
+}
+
+// GetApprovedLeavesByDate returns approved leaves overlapping the range, dates are 2006-01-02
+func (db *leaveRepository) GetApprovedLeavesByDate(_ context.Context, startDate, endDate string) (leaves []entity.Leave) {
+	db.store.do(func(tables *tables) {
+		for _, leave := range tables.leaves {
+			if leave.Status == entity.LeaveStatusApproved && leave.StartDate <= endDate && leave.EndDate >= startDate {
+				leaves = append(leaves, leave)
+			}
+		}
+	})
+	return leaves
+}
@@ -0,0 +1,66 @@
+package memory
+
+import (
+	"armiariyan/attendances-system/entity"
+	"armiariyan/attendances-system/repository"
+	"context"
+)
+
+type networkRepository struct {
+	store *Store
+}
+
+// Construct
+func NewNetworkRepository(store *Store) repository.NetworkRepository {
+	return &networkRepository{
+		store: store,
+	}
+}
+
+func (db *networkRepository) GetNetworks(_ context.Context) (networks []entity.OfficeNetwork, err error) {
+	db.store.do(func(tables *tables) {
+		networks = append(networks, tables.networks...)
+	})
+	return networks, nil
+}
+
+func (db *networkRepository) GetNetworkById(_ context.Context, network_id int) (network entity.OfficeNetwork, err error) {
+	err = repository.ErrNotFound
+	db.store.do(func(tables *tables) {
+		for _, saved := range tables.networks {
+			if saved.Id == network_id {
+				network, err = saved, nil
+				return
+			}
+		}
+	})
+	return network, err
+}
+
+// CreateNetwork returns ErrConflict for a taken CIDR
+func (db *networkRepository) CreateNetwork(_ context.Context, data entity.OfficeNetwork) (entity.OfficeNetwork, error) {
+	var err error
+	db.store.do(func(tables *tables) {
+		for _, saved := range tables.networks {
+			if saved.CIDR == data.CIDR {
+				err = repository.ErrConflict
+				return
+			}
+		}
+		data.Id = tables.nextId("office_networks")
+		tables.networks = append(tables.networks, data)
+	})
+	return data, err
+}
+
+func (db *networkRepository) DeleteNetwork(_ context.Context, network entity.OfficeNetwork) error {
+	db.store.do(func(tables *tables) {
+		for i, saved := range tables.networks {
+			if saved.Id == network.Id {
+				tables.networks = append(tables.networks[:i], tables.networks[i+1:]...)
+				return
+			}
+		}
+	})
+	return nil
+}
@@ -0,0 +1,40 @@
+package memory
+
+import (
+	"armiariyan/attendances-system/entity"
+	"armiariyan/attendances-system/repository"
+	"context"
+)
+
+type outboxRepository struct {
+	store *Store
+}
+
+// Construct
+func NewOutboxRepository(store *Store) repository.OutboxRepository {
+	return &outboxRepository{
+		store: store,
+	}
+}
+
+func (db *outboxRepository) GetPendingEvents(_ context.Context, limit int) (events []entity.OutboxEvent, err error) {
+	db.store.do(func(tables *tables) {
+		for _, event := range tables.events {
+			if event.Status == entity.OutboxPending && len(events) < limit {
+				events = append(events, event)
+			}
+		}
+	})
+	return events, nil
+}
+
+func (db *outboxRepository) UpdateEvent(_ context.Context, data entity.OutboxEvent) (entity.OutboxEvent, error) {
+	db.store.do(func(tables *tables) {
+		for i, saved := range tables.events {
+			if saved.Id == data.Id {
+				tables.events[i] = data
+			}
+		}
+	})
+	return data, nil
+}
@@ -0,0 +1,62 @@
+package memory
+
+import (
+	"armiariyan/attendances-system/entity"
+	"armiariyan/attendances-system/repository"
+	"context"
+)
+
+type policyRepository struct {
+	store *Store
+}
+
+// Construct
+func NewPolicyRepository(store *Store) repository.PolicyRepository {
+	return &policyRepository{
+		store: store,
+	}
+}
+
+func (db *policyRepository) GetPolicyByUserId(_ context.Context, user_id int) (policy entity.WorkPolicy, err error) {
+	err = repository.ErrNotFound
+	db.store.do(func(tables *tables) {
+		for _, saved := range tables.policies {
+			if saved.UserId == user_id {
+				policy, err = saved, nil
+				return
+			}
+		}
+	})
+	return policy, err
+}
+
+// SavePolicy returns ErrConflict for an unknown user
+func (db *policyRepository) SavePolicy(_ context.Context, data entity.WorkPolicy) (entity.WorkPolicy, error) {
+	var err error
+	db.store.do(func(tables *tables) {
+		for i, saved := range tables.policies {
+			if saved.UserId == data.UserId {
+				tables.policies[i] = data
+				return
+			}
+		}
+		if !tables.userExists(data.UserId) {
+			err = repository.ErrConflict
+			return
+		}
+		tables.policies = append(tables.policies, data)
+	})
+	return data, err
+}
+
+func (db *policyRepository) DeletePolicy(_ context.Context, policy entity.WorkPolicy) error {
+	db.store.do(func(tables *tables) {
+		for i, saved := range tables.policies {
+			if saved.UserId == policy.UserId {
+				tables.policies = append(tables.policies[:i], tables.policies[i+1:]...)
+				return
+			}
+		}
+	})
+	return nil
+}
@@ -0,0 +1,160 @@
+// Package memory implements the repositories without database, for tests that
+// don't need one. The rows live as long as the Store holding them
+package memory
+
+import (
+	"armiariyan/attendances-system/entity"
+	"armiariyan/attendances-system/repository"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Store holds the rows of every in-memory repository, the repositories built on
+// the same store see the rows of each other like the tables of one database
+type Store struct {
+	mu          sync.Mutex // guards tables
+	transaction sync.Mutex // runs one transaction at a time
+	tables      tables
+}
+
+type tables struct {
+	users         []entity.User
+	attendances   []entity.Attendance
+	activities    []entity.Activity
+	networks      []entity.OfficeNetwork
+	kioskTokens   []entity.KioskToken
+	policies      []entity.WorkPolicy
+	departments   []entity.Department
+	leaves        []entity.Leave
+	holidays      []entity.Holiday
+	absences      []entity.Absence
+	subscriptions []entity.WebhookSubscription
+	deliveries    []entity.WebhookDelivery
+	events        []entity.OutboxEvent
+	lastIds       map[string]int
+}
+
+func NewStore() *Store {
+	return &Store{tables: tables{lastIds: map[string]int{}}}
+}
+
+// do runs fn with the tables locked
+func (store *Store) do(fn func(tables *tables)) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	fn(&store.tables)
+}
+
+// inTransaction runs fn and puts the tables back as they were when it fails. It
+// doesn't isolate fn from writes made outside of a transaction meanwhile, those
+// are lost too on failure
+func (store *Store) inTransaction(fn func() error) error {
+	store.transaction.Lock()
+	defer store.transaction.Unlock()
+
+	var saved tables
+	store.do(func(tables *tables) {
+		saved = tables.clone()
+	})
+	if err := fn(); err != nil {
+		store.do(func(tables *tables) {
+			*tables = saved
+		})
+		return err
+	}
+	return nil
+}
+
+// nextId returns the next auto increment id of table
+func (tables *tables) nextId(table string) int {
+	tables.lastIds[table]++
+	return tables.lastIds[table]
+}
+
+func (current *tables) clone() tables {
+	lastIds := map[string]int{}
+	for table, id := range current.lastIds {
+		lastIds[table] = id
+	}
+	return tables{
+		users:         append([]entity.User(nil), current.users...),
+		attendances:   append([]entity.Attendance(nil), current.attendances...),
+		activities:    append([]entity.Activity(nil), current.activities...),
+		networks:      append([]entity.OfficeNetwork(nil), current.networks...),
+		kioskTokens:   append([]entity.KioskToken(nil), current.kioskTokens...),
+		policies:      append([]entity.WorkPolicy(nil), current.policies...),
+		departments:   append([]entity.Department(nil), current.departments...),
+		leaves:        append([]entity.Leave(nil), current.leaves...),
+		holidays:      append([]entity.Holiday(nil), current.holidays...),
+		absences:      append([]entity.Absence(nil), current.absences...),
+		subscriptions: append([]entity.WebhookSubscription(nil), current.subscriptions...),
+		deliveries:    append([]entity.WebhookDelivery(nil), current.deliveries...),
+		events:        append([]entity.OutboxEvent(nil), current.events...),
+		lastIds:       lastIds,
+	}
+}
+
+// userExists stands for the foreign keys on user_id of the database
+func (tables *tables) userExists(user_id int) bool {
+	for _, user := range tables.users {
+		if user.Id == user_id {
+			return true
+		}
+	}
+	return false
+}
+
+func (tables *tables) addEvent(event entity.OutboxEvent) error {
+	for _, saved := range tables.events {
+		if saved.EventId == event.EventId {
+			return repository.ErrConflict
+		}
+	}
+	event.Id = tables.nextId("outbox_events")
+	tables.events = append(tables.events, event)
+	return nil
+}
+
+// page returns the indexes of the rows the options select, in their order, with
+// one extra row to know whether there is a next page like the sql version
+func page(options repository.HistoryOptions, count int, timeOf func(i int) int64, idOf func(i int) string) []int {
+	// after tells whether row i comes after the row at time and id in the sort direction
+	after := func(i int, time int64, id string) bool {
+		if options.Descending {
+			return timeOf(i) < time || (timeOf(i) == time && idOf(i) < id)
+		}
+		return timeOf(i) > time || (timeOf(i) == time && idOf(i) > id)
+	}
+
+	var indexes []int
+	for i := 0; i < count; i++ {
+		if options.StartDate != 0 && timeOf(i) < options.StartDate {
+			continue
+		}
+		if options.EndDate != 0 && timeOf(i) > options.EndDate {
+			continue
+		}
+		if options.CursorId != "" && !after(i, options.CursorTime, options.CursorId) {
+			continue
+		}
+		indexes = append(indexes, i)
+	}
+	sort.Slice(indexes, func(a, b int) bool {
+		return after(indexes[b], timeOf(indexes[a]), idOf(indexes[a]))
+	})
+	if len(indexes) > options.Limit+1 {
+		indexes = indexes[:options.Limit+1]
+	}
+	return indexes
+}
+
+// contains matches value anywhere without case, like the LIKE of the sql version
+func contains(value, search string) bool {
+	return strings.Contains(strings.ToLower(value), strings.ToLower(search))
+}
+
+func nowMilli() int64 {
+	return time.Now().UnixMilli()
+}
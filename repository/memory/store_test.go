@@ -0,0 +1,72 @@
+package memory
+
+import (
+	"armiariyan/attendances-system/entity"
+	"armiariyan/attendances-system/repository"
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestTransactionRollsBack(t *testing.T) {
+	store := NewStore()
+	users := NewUserRepository(store)
+	failure := errors.New("failure")
+
+	err := users.Transaction(context.Background(), func(tx repository.UserRepository) error {
+		if _, err := tx.RegisterUser(context.Background(), entity.User{Email: "ana@example.com"}); err != nil {
+			return err
+		}
+		if err := tx.AddEvent(context.Background(), entity.OutboxEvent{EventId: "event"}); err != nil {
+			return err
+		}
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("Transaction = %v, want the error of fn", err)
+	}
+	if all, _ := users.GetUsers(context.Background()); len(all) != 0 {
+		t.Errorf("users = %+v, want none after the rollback", all)
+	}
+	if events, _ := NewOutboxRepository(store).GetPendingEvents(context.Background(), 10); len(events) != 0 {
+		t.Errorf("events = %+v, want none after the rollback", events)
+	}
+
+	// The ids taken by the rolled back transaction are given again
+	if user, _ := users.RegisterUser(context.Background(), entity.User{Email: "bob@example.com"}); user.Id != 1 {
+		t.Errorf("id = %d, want 1", user.Id)
+	}
+}
+
+func TestPageOrder(t *testing.T) {
+	times := []int64{2000, 1000, 2000, 3000}
+	ids := []string{"B", "A", "C", "D"}
+	timeOf := func(i int) int64 { return times[i] }
+	idOf := func(i int) string { return ids[i] }
+
+	tests := []struct {
+		name    string
+		options repository.HistoryOptions
+		want    []string
+	}{
+		{"newest first with a spare row", repository.HistoryOptions{Limit: 2, Descending: true}, []string{"D", "C", "B"}},
+		{"after a cursor on a tie", repository.HistoryOptions{Limit: 10, Descending: true, CursorTime: 2000, CursorId: "C"}, []string{"B", "A"}},
+		{"oldest first in a range", repository.HistoryOptions{Limit: 10, StartDate: 2000, EndDate: 3000}, []string{"B", "C", "D"}},
+	}
+	for _, test := range tests {
+		var got []string
+		for _, i := range page(test.options, len(ids), timeOf, idOf) {
+			got = append(got, ids[i])
+		}
+		if len(got) != len(test.want) {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != test.want[i] {
+				t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+				break
+			}
+		}
+	}
+}
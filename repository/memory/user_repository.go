@@ -0,0 +1,118 @@
+package memory
+
+import (
+	"armiariyan/attendances-system/entity"
+	"armiariyan/attendances-system/repository"
+	"context"
+	"strings"
+)
+
+type userRepository struct {
+	store *Store
+	inTx  bool
+}
+
+// Construct
+func NewUserRepository(store *Store) repository.UserRepository {
+	return &userRepository{
+		store: store,
+	}
+}
+
+func (db *userRepository) Transaction(_ context.Context, fn func(tx repository.UserRepository) error) error {
+	if db.inTx {
+		return fn(db)
+	}
+	return db.store.inTransaction(func() error {
+		return fn(&userRepository{store: db.store, inTx: true})
+	})
+}
+
+func (db *userRepository) AddEvent(_ context.Context, event entity.OutboxEvent) (err error) {
+	db.store.do(func(tables *tables) {
+		err = tables.addEvent(event)
+	})
+	return err
+}
+
+func (db *userRepository) RegisterUser(_ context.Context, user entity.User) (entity.User, error) {
+	db.store.do(func(tables *tables) {
+		user.Id = tables.nextId("users")
+		if user.Role == "" {
+			user.Role = entity.RoleEmployee
+		}
+		user.CreatedAt = nowMilli()
+		tables.users = append(tables.users, user)
+	})
+	return user, nil
+}
+
+// Emails are compared without case everywhere, like the default collation of mysql does
+func (db *userRepository) GetDataByEmail(_ context.Context, email string) (user entity.User, err error) {
+	err = repository.ErrNotFound
+	db.store.do(func(tables *tables) {
+		for _, saved := range tables.users {
+			if strings.EqualFold(saved.Email, email) {
+				user, err = saved, nil
+				return
+			}
+		}
+	})
+	return user, err
+}
+
+// VerifyCredential returns the user to check the password of, ErrNotFound for an unknown email
+func (db *userRepository) VerifyCredential(ctx context.Context, email string) (entity.User, error) {
+	return db.GetDataByEmail(ctx, email)
+}
+
+// ChangeStatusLogin updates the fields set in data, like gorm does with Updates
+func (db *userRepository) ChangeStatusLogin(_ context.Context, data entity.User) (user entity.User, err error) {
+	err = repository.ErrNotFound
+	db.store.do(func(tables *tables) {
+		for i := range tables.users {
+			if tables.users[i].Id != data.Id {
+				continue
+			}
+			saved := &tables.users[i]
+			if data.Name != "" {
+				saved.Name = data.Name
+			}
+			if data.Email != "" {
+				saved.Email = data.Email
+			}
+			if data.Password != "" {
+				saved.Password = data.Password
+			}
+			if data.Role != "" {
+				saved.Role = data.Role
+			}
+			if data.DepartmentId != nil {
+				saved.DepartmentId = data.DepartmentId
+			}
+			user, err = *saved, nil
+			return
+		}
+	})
+	return user, err
+}
+
+func (db *userRepository) GetUserById(_ context.Context, user_id int) (user entity.User, err error) {
+	err = repository.ErrNotFound
+	db.store.do(func(tables *tables) {
+		for _, saved := range tables.users {
+			if saved.Id == user_id {
+				user, err = saved, nil
+				return
+			}
+		}
+	})
+	return user, err
+}
+
+func (db *userRepository) GetUsers(_ context.Context) (users []entity.User, err error) {
+	db.store.do(func(tables *tables) {
+		users = append(users, tables.users...)
+	})
+	return users, nil
+}
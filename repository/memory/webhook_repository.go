@@ -0,0 +1,138 @@
+package memory
+
+import (
+	"armiariyan/attendances-system/entity"
+	"armiariyan/attendances-system/repository"
+	"context"
+	"sort"
+)
+
+type webhookRepository struct {
+	store *Store
+}
+
+// Construct
+func NewWebhookRepository(store *Store) repository.WebhookRepository {
+	return &webhookRepository{
+		store: store,
+	}
+}
+
+func (db *webhookRepository) GetSubscriptions(_ context.Context) (subscriptions []entity.WebhookSubscription, err error) {
+	db.store.do(func(tables *tables) {
+		subscriptions = append(subscriptions, tables.subscriptions...)
+	})
+	return subscriptions, nil
+}
+
+func (db *webhookRepository) GetSubscriptionById(_ context.Context, subscription_id int) (subscription entity.WebhookSubscription, err error) {
+	err = repository.ErrNotFound
+	db.store.do(func(tables *tables) {
+		for _, saved := range tables.subscriptions {
+			if saved.Id == subscription_id {
+				subscription, err = saved, nil
+				return
+			}
+		}
+	})
+	return subscription, err
+}
+
+func (db *webhookRepository) CreateSubscription(_ context.Context, data entity.WebhookSubscription) (entity.WebhookSubscription, error) {
+	db.store.do(func(tables *tables) {
+		data.Id = tables.nextId("webhook_subscriptions")
+		data.CreatedAt = nowMilli()
+		tables.subscriptions = append(tables.subscriptions, data)
+	})
+	return data, nil
+}
+
+func (db *webhookRepository) DeleteSubscription(_ context.Context, subscription entity.WebhookSubscription) error {
+	db.store.do(func(tables *tables) {
+		var deliveries []entity.WebhookDelivery
+		for _, delivery := range tables.deliveries {
+			if delivery.SubscriptionId != subscription.Id {
+				deliveries = append(deliveries, delivery)
+			}
+		}
+		tables.deliveries = deliveries
+
+		for i, saved := range tables.subscriptions {
+			if saved.Id == subscription.Id {
+				tables.subscriptions = append(tables.subscriptions[:i], tables.subscriptions[i+1:]...)
+				return
+			}
+		}
+	})
+	return nil
+}
+
+// CreateDelivery skips a delivery of an event the subscription already has
+func (db *webhookRepository) CreateDelivery(_ context.Context, data entity.WebhookDelivery) (entity.WebhookDelivery, error) {
+	db.store.do(func(tables *tables) {
+		for _, saved := range tables.deliveries {
+			if saved.SubscriptionId == data.SubscriptionId && saved.EventId == data.EventId {
+				return
+			}
+		}
+		data.Id = tables.nextId("webhook_deliveries")
+		data.CreatedAt = nowMilli()
+		data.UpdatedAt = data.CreatedAt
+		tables.deliveries = append(tables.deliveries, data)
+	})
+	return data, nil
+}
+
+func (db *webhookRepository) UpdateDelivery(_ context.Context, data entity.WebhookDelivery) (entity.WebhookDelivery, error) {
+	db.store.do(func(tables *tables) {
+		data.UpdatedAt = nowMilli()
+		for i, saved := range tables.deliveries {
+			if saved.Id == data.Id {
+				tables.deliveries[i] = data
+			}
+		}
+	})
+	return data, nil
+}
+
+func (db *webhookRepository) GetDeliveryById(_ context.Context, delivery_id int) (delivery entity.WebhookDelivery, err error) {
+	err = repository.ErrNotFound
+	db.store.do(func(tables *tables) {
+		for _, saved := range tables.deliveries {
+			if saved.Id == delivery_id {
+				delivery, err = saved, nil
+				return
+			}
+		}
+	})
+	return delivery, err
+}
+
+func (db *webhookRepository) GetDeliveriesBySubscription(_ context.Context, subscription_id int) (deliveries []entity.WebhookDelivery, err error) {
+	db.store.do(func(tables *tables) {
+		// Newest first, like the order by id desc of the sql version
+		for i := len(tables.deliveries) - 1; i >= 0 && len(deliveries) < 100; i-- {
+			if tables.deliveries[i].SubscriptionId == subscription_id {
+				deliveries = append(deliveries, tables.deliveries[i])
+			}
+		}
+	})
+	return deliveries, nil
+}
+
+func (db *webhookRepository) GetDueDeliveries(_ context.Context, now int64, limit int) (deliveries []entity.WebhookDelivery, err error) {
+	db.store.do(func(tables *tables) {
+		for _, delivery := range tables.deliveries {
+			if delivery.Status == entity.DeliveryPending && delivery.NextAttemptAt <= now {
+				deliveries = append(deliveries, delivery)
+			}
+		}
+	})
+	sort.SliceStable(deliveries, func(i, j int) bool {
+		return deliveries[i].NextAttemptAt < deliveries[j].NextAttemptAt
+	})
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, nil
+}
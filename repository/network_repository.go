@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"armiariyan/attendances-system/entity"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// NetworkRepository returns its errors translated like the user repository,
+// ErrNotFound for a missing network and ErrConflict for a taken CIDR
+type NetworkRepository interface {
+	GetNetworks(ctx context.Context) ([]entity.OfficeNetwork, error)
+	GetNetworkById(ctx context.Context, network_id int) (entity.OfficeNetwork, error)
+	CreateNetwork(ctx context.Context, data entity.OfficeNetwork) (entity.OfficeNetwork, error)
+	DeleteNetwork(ctx context.Context, network entity.OfficeNetwork) error
+}
+
+type networkConnection struct {
+	connection *gorm.DB
+}
+
+// Construct
+func NewNetworkRepository(db *gorm.DB) NetworkRepository {
+	return &networkConnection{
+		connection: db,
+	}
+}
+
+func (db *networkConnection) GetNetworks(ctx context.Context) ([]entity.OfficeNetwork, error) {
+	var networks []entity.OfficeNetwork
+	err := db.connection.WithContext(ctx).Find(&networks).Error
+	return networks, translate(err)
+}
+
+func (db *networkConnection) GetNetworkById(ctx context.Context, network_id int) (entity.OfficeNetwork, error) {
+	var network entity.OfficeNetwork
+	err := db.connection.WithContext(ctx).First(&network, "id = ?", network_id).Error
+	return network, translate(err)
+}
+
+func (db *networkConnection) CreateNetwork(ctx context.Context, data entity.OfficeNetwork) (entity.OfficeNetwork, error) {
+	err := db.connection.WithContext(ctx).Create(&data).Error
+	return data, translate(err)
+}
+
+func (db *networkConnection) DeleteNetwork(ctx context.Context, network entity.OfficeNetwork) error {
+	return translate(db.connection.WithContext(ctx).Delete(&network).Error)
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"armiariyan/attendances-system/entity"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// OutboxRepository returns its errors translated like the user repository
+type OutboxRepository interface {
+	GetPendingEvents(ctx context.Context, limit int) ([]entity.OutboxEvent, error)
+	UpdateEvent(ctx context.Context, data entity.OutboxEvent) (entity.OutboxEvent, error)
+}
+
+type outboxConnection struct {
+	connection *gorm.DB
+}
+
+// Construct
+func NewOutboxRepository(db *gorm.DB) OutboxRepository {
+	return &outboxConnection{
+		connection: db,
+	}
+}
+
+func (db *outboxConnection) GetPendingEvents(ctx context.Context, limit int) ([]entity.OutboxEvent, error) {
+	var events []entity.OutboxEvent
+	err := db.connection.WithContext(ctx).Where("status = ?", entity.OutboxPending).Order("id").Limit(limit).Find(&events).Error
+	return events, translate(err)
+}
+
+func (db *outboxConnection) UpdateEvent(ctx context.Context, data entity.OutboxEvent) (entity.OutboxEvent, error) {
+	err := db.connection.WithContext(ctx).Save(&data).Error
+	return data, translate(err)
+}
+
+// addEvent writes the event with the given connection, pass the transaction
+// of the change so both are committed or rolled back together
+func addEvent(connection *gorm.DB, event entity.OutboxEvent) error {
+	return connection.Create(&event).Error
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"armiariyan/attendances-system/entity"
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PolicyRepository returns its errors translated like the user repository,
+// ErrNotFound for a user without policy and ErrConflict for an unknown user
+type PolicyRepository interface {
+	GetPolicyByUserId(ctx context.Context, user_id int) (entity.WorkPolicy, error)
+	SavePolicy(ctx context.Context, data entity.WorkPolicy) (entity.WorkPolicy, error)
+	DeletePolicy(ctx context.Context, policy entity.WorkPolicy) error
+}
+
+type policyConnection struct {
+	connection *gorm.DB
+}
+
+// Construct
+func NewPolicyRepository(db *gorm.DB) PolicyRepository {
+	return &policyConnection{
+		connection: db,
+	}
+}
+
+func (db *policyConnection) GetPolicyByUserId(ctx context.Context, user_id int) (entity.WorkPolicy, error) {
+	var policy entity.WorkPolicy
+	err := db.connection.WithContext(ctx).First(&policy, "user_id = ?", user_id).Error
+	return policy, translate(err)
+}
+
+func (db *policyConnection) SavePolicy(ctx context.Context, data entity.WorkPolicy) (entity.WorkPolicy, error) {
+	err := db.connection.WithContext(ctx).Clauses(clause.OnConflict{UpdateAll: true}).Create(&data).Error
+	return data, translate(err)
+}
+
+func (db *policyConnection) DeletePolicy(ctx context.Context, policy entity.WorkPolicy) error {
+	return translate(db.connection.WithContext(ctx).Delete(&policy).Error)
+}
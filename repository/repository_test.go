@@ -0,0 +1,312 @@
+package repository
+
+import (
+	"armiariyan/attendances-system/config"
+	"armiariyan/attendances-system/entity"
+	"armiariyan/attendances-system/migration"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// openTestDatabase opens a fresh sqlite file, or the database of the DB_* env when
+// TEST_DB_DRIVER names another driver so the same queries can be checked on mysql
+// and postgres. It is migrated and its rows are deleted when the test ends
+func openTestDatabase(t *testing.T) *gorm.DB {
+	t.Helper()
+	cfg := config.DatabaseConfig{Driver: config.DriverSQLite, Name: filepath.Join(t.TempDir(), "test.db")}
+	if driver := os.Getenv("TEST_DB_DRIVER"); driver != "" && driver != config.DriverSQLite {
+		loaded, _, err := config.Load(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cfg = loaded.Database
+		cfg.Driver = driver
+	}
+
+	db := config.SetupDatabaseConnection(cfg)
+	if _, err := migration.Up(db, 0); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		all := db.Session(&gorm.Session{AllowGlobalUpdate: true})
+		for _, model := range []interface{}{&entity.OutboxEvent{}, &entity.KioskToken{}, &entity.WorkPolicy{}, &entity.Absence{}, &entity.Attendance{}, &entity.Activity{}, &entity.User{}} {
+			all.Delete(model)
+		}
+		config.CloseDatabaseConnection(db)
+	})
+	return db
+}
+
+// mustRegister registers a user and fails the test when it can't
+func mustRegister(t *testing.T, users UserRepository, user entity.User) entity.User {
+	t.Helper()
+	registered, err := users.RegisterUser(context.Background(), user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return registered
+}
+
+func TestEmailIgnoresCase(t *testing.T) {
+	users := NewUserRepository(openTestDatabase(t))
+	registered := mustRegister(t, users, entity.User{Name: "Ana", Email: "Ana@Example.com", Password: "hash"})
+
+	if user, err := users.GetDataByEmail(context.Background(), "ana@example.COM"); err != nil || user.Id != registered.Id {
+		t.Errorf("GetDataByEmail = %+v %v, want user %d", user, err, registered.Id)
+	}
+	if _, err := users.VerifyCredential(context.Background(), "ANA@example.com"); err != nil {
+		t.Errorf("VerifyCredential didn't find the user: %v", err)
+	}
+}
+
+func TestAttendancesPage(t *testing.T) {
+	db := openTestDatabase(t)
+	users := NewUserRepository(db)
+	attendances := NewAttendanceRepository(db)
+	user := mustRegister(t, users, entity.User{Name: "Ana", Email: "ana@example.com"})
+	for _, attendance := range []entity.Attendance{
+		{Id: "ATD-1", Label: entity.LabelCheckIn, Location: entity.LocationOnsite, WorkMode: entity.WorkModeOnsite, Date: 1000},
+		{Id: "ATD-2", Label: entity.LabelCheckOut, Location: entity.LocationOnsite, WorkMode: entity.WorkModeOnsite, Date: 2000},
+		{Id: "ATD-3", Label: entity.LabelCheckIn, Location: entity.LocationRemote, WorkMode: entity.WorkModeRemote, Date: 2000},
+		{Id: "ATD-4", Label: entity.LabelCheckIn, Location: entity.LocationRemote, WorkMode: entity.WorkModeClientSite, Date: 3000},
+	} {
+		attendance.UserId = user.Id
+		if _, err := attendances.CreateAttendance(context.Background(), attendance); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ids := func(attendances []entity.Attendance) (ids []string) {
+		for _, attendance := range attendances {
+			ids = append(ids, attendance.Id)
+		}
+		return ids
+	}
+	tests := []struct {
+		name    string
+		options HistoryOptions
+		want    []string
+	}{
+		{"newest first with a spare row", HistoryOptions{Limit: 2, Descending: true}, []string{"ATD-4", "ATD-3", "ATD-2"}},
+		{"after a cursor on a tie", HistoryOptions{Limit: 10, Descending: true, CursorTime: 2000, CursorId: "ATD-3"}, []string{"ATD-2", "ATD-1"}},
+		{"oldest first", HistoryOptions{Limit: 10, CursorTime: 2000, CursorId: "ATD-2"}, []string{"ATD-3", "ATD-4"}},
+		{"date bounds are inclusive", HistoryOptions{Limit: 10, StartDate: 2000, EndDate: 2000}, []string{"ATD-2", "ATD-3"}},
+		{"label", HistoryOptions{Limit: 10, Label: entity.LabelCheckOut}, []string{"ATD-2"}},
+		{"search ignores case", HistoryOptions{Limit: 10, Search: "REMOTE"}, []string{"ATD-3", "ATD-4"}},
+		{"search escapes wildcards", HistoryOptions{Limit: 10, Search: "client_"}, []string{"ATD-4"}},
+		{"underscore isn't a wildcard", HistoryOptions{Limit: 10, Search: "on_ite"}, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			page, err := attendances.GetAttendancesPage(context.Background(), user.Id, test.options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := ids(page)
+			if len(got) != len(test.want) {
+				t.Fatalf("got %v, want %v", got, test.want)
+			}
+			for i := range got {
+				if got[i] != test.want[i] {
+					t.Fatalf("got %v, want %v", got, test.want)
+				}
+			}
+		})
+	}
+}
+
+func TestActivityLifecycle(t *testing.T) {
+	db := openTestDatabase(t)
+	user := mustRegister(t, NewUserRepository(db), entity.User{Name: "Ana", Email: "ana@example.com"})
+	activities := NewActivityRepository(db)
+
+	for _, activity := range []entity.Activity{
+		{Id: "ACT-1", UserId: user.Id, Description: "100% done", DateCreated: 1000},
+		{Id: "ACT-2", UserId: user.Id, Description: "1000 done", DateCreated: 2000},
+	} {
+		if _, err := activities.CreateActivity(context.Background(), activity); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if found, err := activities.GetActivitiesPage(context.Background(), user.Id, HistoryOptions{Limit: 10, Search: "100%"}); err != nil || len(found) != 1 || found[0].Id != "ACT-1" {
+		t.Errorf("search 100%% = %+v %v, want ACT-1 only", found, err)
+	}
+
+	updated, err := activities.UpdateActivity(context.Background(), entity.Activity{Id: "ACT-1", Description: "all done"})
+	if err != nil || updated.Description != "all done" || updated.UserId != user.Id {
+		t.Errorf("UpdateActivity = %+v %v", updated, err)
+	}
+
+	if err := activities.DeleteActivity(context.Background(), entity.Activity{Id: "ACT-2"}); err != nil {
+		t.Fatal(err)
+	}
+	if remaining, err := activities.GetActivitiesByDate(context.Background(), user.Id, 0, 5000); err != nil || len(remaining) != 1 || remaining[0].Id != "ACT-1" {
+		t.Errorf("after delete = %+v %v, want ACT-1 only", remaining, err)
+	}
+}
+
+func TestDomainErrors(t *testing.T) {
+	db := openTestDatabase(t)
+	users := NewUserRepository(db)
+	attendances := NewAttendanceRepository(db)
+	activities := NewActivityRepository(db)
+	user := mustRegister(t, users, entity.User{Name: "Ana", Email: "ana@example.com"})
+
+	if _, err := users.GetUserById(context.Background(), user.Id+1); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetUserById of a missing user = %v, want ErrNotFound", err)
+	}
+	if _, err := activities.UpdateActivity(context.Background(), entity.Activity{Id: "ACT-404", Description: "gone"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("UpdateActivity of a missing activity = %v, want ErrNotFound", err)
+	}
+	if err := activities.DeleteActivity(context.Background(), entity.Activity{Id: "ACT-404"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("DeleteActivity of a missing activity = %v, want ErrNotFound", err)
+	}
+
+	attendance := entity.Attendance{Id: "ATD-1", UserId: user.Id, Label: entity.LabelCheckIn, Date: 1000}
+	if _, err := attendances.CreateAttendance(context.Background(), attendance); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := attendances.CreateAttendance(context.Background(), attendance); !errors.Is(err, ErrConflict) {
+		t.Errorf("CreateAttendance with a taken id = %v, want ErrConflict", err)
+	}
+	if _, err := NewDepartmentRepository(db).GetDepartmentById(context.Background(), 404); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetDepartmentById of a missing department = %v, want ErrNotFound", err)
+	}
+	if _, err := NewPolicyRepository(db).SavePolicy(context.Background(), entity.WorkPolicy{UserId: user.Id + 1}); !errors.Is(err, ErrConflict) {
+		t.Errorf("SavePolicy of a missing user = %v, want ErrConflict", err)
+	}
+
+	config.CloseDatabaseConnection(db)
+	if _, err := users.GetUsers(context.Background()); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("GetUsers on a closed database = %v, want ErrUnavailable", err)
+	}
+	if _, err := NewWebhookRepository(db).GetSubscriptionById(context.Background(), 1); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("GetSubscriptionById on a closed database = %v, want ErrUnavailable", err)
+	}
+	if _, err := NewKioskRepository(db).UseToken(context.Background(), entity.KioskToken{Nonce: "nonce", UserId: user.Id}); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("UseToken on a closed database = %v, want ErrUnavailable", err)
+	}
+}
+
+func TestUpserts(t *testing.T) {
+	db := openTestDatabase(t)
+	user := mustRegister(t, NewUserRepository(db), entity.User{Name: "Ana", Email: "ana@example.com"})
+
+	policies := NewPolicyRepository(db)
+	for _, days := range []int{1, 3} {
+		if _, err := policies.SavePolicy(context.Background(), entity.WorkPolicy{UserId: user.Id, MaxRemoteDaysPerWeek: days}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if policy, err := policies.GetPolicyByUserId(context.Background(), user.Id); err != nil || policy.MaxRemoteDaysPerWeek != 3 {
+		t.Errorf("policy = %+v %v, want the second save", policy, err)
+	}
+
+	kiosk := NewKioskRepository(db)
+	token := entity.KioskToken{Nonce: "nonce", UserId: user.Id, UsedAt: 1000}
+	if used, err := kiosk.UseToken(context.Background(), token); !used || err != nil {
+		t.Errorf("first UseToken = %v %v, want used", used, err)
+	}
+	if used, err := kiosk.UseToken(context.Background(), token); used || err != nil {
+		t.Errorf("second UseToken = %v %v, want a replay", used, err)
+	}
+
+	absences := NewAbsenceRepository(db)
+	for i := 0; i < 2; i++ {
+		if err := absences.SaveAbsences(context.Background(), "2022-07-01", []entity.Absence{{UserId: user.Id, Date: "2022-07-01"}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if saved, err := absences.GetAbsencesByDate(context.Background(), "2022-07-01", "2022-07-01"); err != nil || len(saved) != 1 {
+		t.Errorf("absences = %+v %v, want one after detecting twice", saved, err)
+	}
+	if err := absences.SaveAbsences(context.Background(), "2022-07-01", nil); err != nil {
+		t.Fatal(err)
+	}
+	if saved, err := absences.GetAbsencesByDate(context.Background(), "2022-07-01", "2022-07-01"); err != nil || len(saved) != 0 {
+		t.Errorf("absences = %+v %v, want none once explained", saved, err)
+	}
+}
+
+// outboxEvent is a pending event as services build them
+func outboxEvent(id string) entity.OutboxEvent {
+	return entity.OutboxEvent{EventId: id, Type: entity.EventCheckedIn, Payload: "{}", Status: entity.OutboxPending, OccurredAt: 1000}
+}
+
+func TestOutboxEvents(t *testing.T) {
+	db := openTestDatabase(t)
+	attendances := NewAttendanceRepository(db)
+	outbox := NewOutboxRepository(db)
+	for _, id := range []string{"EVT-1", "EVT-2", "EVT-3"} {
+		if err := attendances.AddEvent(context.Background(), outboxEvent(id)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := attendances.AddEvent(context.Background(), outboxEvent("EVT-1")); !errors.Is(err, ErrConflict) {
+		t.Errorf("AddEvent with a taken event id = %v, want ErrConflict", err)
+	}
+
+	pending, err := outbox.GetPendingEvents(context.Background(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 2 || pending[0].EventId != "EVT-1" || pending[1].EventId != "EVT-2" {
+		t.Fatalf("pending = %+v, want EVT-1 and EVT-2 in the order they were saved", pending)
+	}
+
+	dispatched, failed := pending[0], pending[1]
+	dispatched.Status, dispatched.Attempts, dispatched.DispatchedAt = entity.OutboxDispatched, 1, 2000
+	failed.Status, failed.Attempts, failed.LastError = entity.OutboxFailed, 10, "subscriber answered 500"
+	for _, event := range []entity.OutboxEvent{dispatched, failed} {
+		if _, err := outbox.UpdateEvent(context.Background(), event); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if pending, err := outbox.GetPendingEvents(context.Background(), 10); err != nil || len(pending) != 1 || pending[0].EventId != "EVT-3" {
+		t.Errorf("pending = %+v %v, want only EVT-3", pending, err)
+	}
+	var saved entity.OutboxEvent
+	if err := db.Where("event_id = ?", "EVT-2").First(&saved).Error; err != nil {
+		t.Fatal(err)
+	}
+	if saved.Status != entity.OutboxFailed || saved.Attempts != 10 || saved.LastError != failed.LastError {
+		t.Errorf("failed event = %+v, want its status, attempts and error saved", saved)
+	}
+}
+
+func TestTransactionRollsBackEvents(t *testing.T) {
+	db := openTestDatabase(t)
+	user := mustRegister(t, NewUserRepository(db), entity.User{Name: "Ana", Email: "ana@example.com"})
+	attendances := NewAttendanceRepository(db)
+	attendance := entity.Attendance{Id: "ATD-1", UserId: user.Id, Label: entity.LabelCheckIn, Date: 1000}
+	if _, err := attendances.CreateAttendance(context.Background(), attendance); err != nil {
+		t.Fatal(err)
+	}
+
+	// The event is saved first, the taken id fails the attendance after it
+	err := attendances.Transaction(context.Background(), func(tx AttendanceRepository) error {
+		if err := tx.AddEvent(context.Background(), outboxEvent("EVT-1")); err != nil {
+			return err
+		}
+		_, err := tx.CreateAttendance(context.Background(), attendance)
+		return err
+	})
+	if !errors.Is(err, ErrConflict) {
+		t.Fatalf("Transaction = %v, want the ErrConflict of CreateAttendance", err)
+	}
+
+	var events int64
+	if err := db.Model(&entity.OutboxEvent{}).Count(&events).Error; err != nil {
+		t.Fatal(err)
+	}
+	if events != 0 {
+		t.Errorf("outbox_events has %d rows, want none after the rollback", events)
+	}
+}
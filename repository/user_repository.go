@@ -2,23 +2,22 @@ package repository
 
 import (
 	"armiariyan/attendances-system/entity"
+	"context"
 
 	"gorm.io/gorm"
 )
 
+// UserRepository wraps every database error into ErrNotFound, ErrConflict or
+// ErrUnavailable when it means one of them, like the attendance and activity repositories
 type UserRepository interface {
-	RegisterUser(data entity.User) entity.User
-	VerifyCredential(email string) interface{}
-	GetDataByEmail(email string) entity.User
-	ChangeStatusLogin(data entity.User) entity.User
-	GetUserById(user_id int) entity.User
-	GetActivityById(act_id string) entity.Activity
-	CheckIn(data entity.Attendance) entity.Attendance
-	CreateActivity(data entity.Activity) entity.Activity
-	UpdateActivity(data entity.Activity) entity.Activity
-	DeleteActivity(activity entity.Activity)
-	GetActivityHistoryByDate(startDate, endDate int64) []entity.Activity
-	GetAttendancesHistory(user_id int) []entity.Attendance
+	RegisterUser(ctx context.Context, data entity.User) (entity.User, error)
+	VerifyCredential(ctx context.Context, email string) (entity.User, error)
+	GetDataByEmail(ctx context.Context, email string) (entity.User, error)
+	ChangeStatusLogin(ctx context.Context, data entity.User) (entity.User, error)
+	GetUserById(ctx context.Context, user_id int) (entity.User, error)
+	GetUsers(ctx context.Context) ([]entity.User, error)
+	AddEvent(ctx context.Context, event entity.OutboxEvent) error
+	Transaction(ctx context.Context, fn func(tx UserRepository) error) error
 }
 
 type userConnection struct {
@@ -32,79 +31,51 @@ func NewUserRepository(db *gorm.DB) UserRepository {
 	}
 }
 
-func (db *userConnection) GetAttendancesHistory(user_id int) []entity.Attendance {
-	var attendances []entity.Attendance
-	db.connection.Find(&attendances, "user_id = ?", user_id)
-	return attendances
+// Transaction runs fn with a repository bound to one database transaction,
+// it is rolled back when fn returns an error
+func (db *userConnection) Transaction(ctx context.Context, fn func(tx UserRepository) error) error {
+	return translate(db.connection.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&userConnection{connection: tx})
+	}))
 }
 
-func (db *userConnection) RegisterUser(user entity.User) entity.User {
-	// user.Password = hashAndSalt([]byte(user.Password))
-	db.connection.Create(&user)
-	// db.connection.Preload("User").Find(&b)
-	return user
+func (db *userConnection) AddEvent(ctx context.Context, event entity.OutboxEvent) error {
+	return translate(addEvent(db.connection.WithContext(ctx), event))
 }
 
-func (db *userConnection) GetDataByEmail(email string) entity.User {
-	var user entity.User
-	db.connection.Where("email = ?", email).Take(&user)
-	return user
-}
-
-func (db *userConnection) VerifyCredential(email string) interface{} {
-	var user entity.User
-
-	res := db.connection.Where("email = ?", email).Take(&user)
-	if res.Error == nil {
-		return user
-	}
-	return nil
-}
-
-func (db *userConnection) ChangeStatusLogin(data entity.User) entity.User {
-	// db.connection.Where("id = ?", data.Id).Update("status_login", data.StatusLogin).Take(&data)
-	db.connection.Updates(&data)
-	db.connection.Find(&data)
-	return data
+func (db *userConnection) RegisterUser(ctx context.Context, user entity.User) (entity.User, error) {
+	err := db.connection.WithContext(ctx).Create(&user).Error
+	return user, translate(err)
 }
 
-func (db *userConnection) GetUserById(user_id int) entity.User {
+// Emails are compared without case everywhere, like the default collation of mysql does
+func (db *userConnection) GetDataByEmail(ctx context.Context, email string) (entity.User, error) {
 	var user entity.User
-	db.connection.First(&user, "id = ?", user_id)
-	return user
-}
-
-func (db *userConnection) GetActivityById(act_id string) entity.Activity {
-	var activity entity.Activity
-	db.connection.First(&activity, "id = ?", act_id)
-	return activity
+	err := db.connection.WithContext(ctx).Where("LOWER(email) = LOWER(?)", email).Take(&user).Error
+	return user, translate(err)
 }
 
-func (db *userConnection) CheckIn(data entity.Attendance) entity.Attendance {
-	db.connection.Create(&data)
-	db.connection.Find(&data)
-	return data
+// VerifyCredential returns the user to check the password of, ErrNotFound for an unknown email
+func (db *userConnection) VerifyCredential(ctx context.Context, email string) (entity.User, error) {
+	return db.GetDataByEmail(ctx, email)
 }
 
-func (db *userConnection) CreateActivity(data entity.Activity) entity.Activity {
-	db.connection.Create(&data)
-	db.connection.Find(&data)
-	return data
-}
-
-func (db *userConnection) UpdateActivity(activity entity.Activity) entity.Activity {
-	db.connection.Where("id = ?", activity.Id).Updates(&activity).Take(&activity)
-	// db.connection.Updates(&activity)
-	db.connection.Find(&activity)
-	return activity
+func (db *userConnection) ChangeStatusLogin(ctx context.Context, data entity.User) (entity.User, error) {
+	if err := db.connection.WithContext(ctx).Updates(&data).Error; err != nil {
+		return data, translate(err)
+	}
+	err := db.connection.WithContext(ctx).Take(&data).Error
+	return data, translate(err)
 }
 
-func (db *userConnection) DeleteActivity(activity entity.Activity) {
-	db.connection.Delete(&activity)
+func (db *userConnection) GetUserById(ctx context.Context, user_id int) (entity.User, error) {
+	var user entity.User
+	err := db.connection.WithContext(ctx).First(&user, "id = ?", user_id).Error
+	return user, translate(err)
 }
 
-func (db *userConnection) GetActivityHistoryByDate(startDate, endDate int64) []entity.Activity {
-	var activities []entity.Activity
-	db.connection.Where("date_created >= ? AND date_created <= ?", startDate, endDate).Find(&activities)
-	return activities
+func (db *userConnection) GetUsers(ctx context.Context) ([]entity.User, error) {
+	var users []entity.User
+	err := db.connection.WithContext(ctx).Find(&users).Error
+	return users, translate(err)
 }
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"armiariyan/attendances-system/entity"
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// WebhookRepository returns its errors translated like the user repository,
+// ErrNotFound for a missing subscription or delivery
+type WebhookRepository interface {
+	GetSubscriptions(ctx context.Context) ([]entity.WebhookSubscription, error)
+	GetSubscriptionById(ctx context.Context, subscription_id int) (entity.WebhookSubscription, error)
+	CreateSubscription(ctx context.Context, data entity.WebhookSubscription) (entity.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, subscription entity.WebhookSubscription) error
+	CreateDelivery(ctx context.Context, data entity.WebhookDelivery) (entity.WebhookDelivery, error)
+	UpdateDelivery(ctx context.Context, data entity.WebhookDelivery) (entity.WebhookDelivery, error)
+	GetDeliveryById(ctx context.Context, delivery_id int) (entity.WebhookDelivery, error)
+	GetDeliveriesBySubscription(ctx context.Context, subscription_id int) ([]entity.WebhookDelivery, error)
+	GetDueDeliveries(ctx context.Context, now int64, limit int) ([]entity.WebhookDelivery, error)
+}
+
+type webhookConnection struct {
+	connection *gorm.DB
+}
+
+// Construct
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookConnection{
+		connection: db,
+	}
+}
+
+func (db *webhookConnection) GetSubscriptions(ctx context.Context) ([]entity.WebhookSubscription, error) {
+	var subscriptions []entity.WebhookSubscription
+	err := db.connection.WithContext(ctx).Find(&subscriptions).Error
+	return subscriptions, translate(err)
+}
+
+func (db *webhookConnection) GetSubscriptionById(ctx context.Context, subscription_id int) (entity.WebhookSubscription, error) {
+	var subscription entity.WebhookSubscription
+	err := db.connection.WithContext(ctx).First(&subscription, "id = ?", subscription_id).Error
+	return subscription, translate(err)
+}
+
+func (db *webhookConnection) CreateSubscription(ctx context.Context, data entity.WebhookSubscription) (entity.WebhookSubscription, error) {
+	err := db.connection.WithContext(ctx).Create(&data).Error
+	return data, translate(err)
+}
+
+func (db *webhookConnection) DeleteSubscription(ctx context.Context, subscription entity.WebhookSubscription) error {
+	return translate(db.connection.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("subscription_id = ?", subscription.Id).Delete(&entity.WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&subscription).Error
+	}))
+}
+
+// CreateDelivery skips a delivery of an event the subscription already has
+func (db *webhookConnection) CreateDelivery(ctx context.Context, data entity.WebhookDelivery) (entity.WebhookDelivery, error) {
+	err := db.connection.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&data).Error
+	return data, translate(err)
+}
+
+func (db *webhookConnection) UpdateDelivery(ctx context.Context, data entity.WebhookDelivery) (entity.WebhookDelivery, error) {
+	err := db.connection.WithContext(ctx).Save(&data).Error
+	return data, translate(err)
+}
+
+func (db *webhookConnection) GetDeliveryById(ctx context.Context, delivery_id int) (entity.WebhookDelivery, error) {
+	var delivery entity.WebhookDelivery
+	err := db.connection.WithContext(ctx).First(&delivery, "id = ?", delivery_id).Error
+	return delivery, translate(err)
+}
+
+func (db *webhookConnection) GetDeliveriesBySubscription(ctx context.Context, subscription_id int) ([]entity.WebhookDelivery, error) {
+	var deliveries []entity.WebhookDelivery
+	err := db.connection.WithContext(ctx).Where("subscription_id = ?", subscription_id).Order("id desc").Limit(100).Find(&deliveries).Error
+	return deliveries, translate(err)
+}
+
+func (db *webhookConnection) GetDueDeliveries(ctx context.Context, now int64, limit int) ([]entity.WebhookDelivery, error) {
+	var deliveries []entity.WebhookDelivery
+	err := db.connection.WithContext(ctx).Where("status = ? AND next_attempt_at <= ?", entity.DeliveryPending, now).Order("next_attempt_at").Limit(limit).Find(&deliveries).Error
+	return deliveries, translate(err)
+}
@@ -17,6 +17,7 @@ func User(db *gorm.DB) []entity.User {
 			Name:     "User 1",
 			Email:    "user1@gmail.com",
 			Password: "password",
+			Role:     "admin",
 		},
 		{
 			Id:       2,
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"armiariyan/attendances-system/config"
+	"armiariyan/attendances-system/controller"
+	"armiariyan/attendances-system/logger"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// newServer serves handler on the port of cfg with its timeouts
+func newServer(cfg config.ServerConfig, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              fmt.Sprintf(":%d", cfg.Port),
+		Handler:           handler,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+	}
+}
+
+// serve runs server on listener until ctx is done, then marks the server not ready,
+// waits the drain delay and gives the requests in flight the shutdown timeout to
+// finish. The connections still open after it are closed
+func serve(ctx context.Context, server *http.Server, listener net.Listener, cfg config.ServerConfig, readiness *controller.Readiness) error {
+	failed := make(chan error, 1)
+	go func() {
+		if err := server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+			failed <- err
+		}
+	}()
+
+	select {
+	case err := <-failed:
+		return err
+	case <-ctx.Done():
+	}
+
+	logger.Log.WithField("drain_for", (cfg.DrainDelay + cfg.ShutdownTimeout).String()).Info("shutting down, draining requests")
+	readiness.Drain()
+	time.Sleep(cfg.DrainDelay)
+
+	shutdown, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdown); err != nil {
+		server.Close()
+		return fmt.Errorf("requests still running after %s were cut: %w", cfg.ShutdownTimeout, err)
+	}
+	return nil
+}
+
+// waitFor waits up to timeout for wg and reports whether it was done in time
+func waitFor(wg *sync.WaitGroup, timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
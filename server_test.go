@@ -0,0 +1,98 @@
+package main
+
+import (
+	"armiariyan/attendances-system/config"
+	"armiariyan/attendances-system/controller"
+	"context"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+// startServe serves handler on a free port until the returned cancel is called,
+// serve's result comes on the returned channel
+func startServe(t *testing.T, cfg config.ServerConfig, handler http.Handler, readiness *controller.Readiness) (string, context.CancelFunc, <-chan error) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- serve(ctx, newServer(cfg, handler), listener, cfg, readiness)
+	}()
+	return "http://" + listener.Addr().String(), cancel, done
+}
+
+func TestServeDrainsRequests(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusOK)
+	})
+	readiness := &controller.Readiness{}
+	url, cancel, done := startServe(t, config.ServerConfig{ShutdownTimeout: 5 * time.Second}, handler, readiness)
+
+	answered := make(chan int, 1)
+	go func() {
+		response, err := http.Get(url)
+		if err != nil {
+			answered <- 0
+			return
+		}
+		response.Body.Close()
+		answered <- response.StatusCode
+	}()
+	<-started
+
+	cancel()
+	for readiness.Ready() {
+		time.Sleep(time.Millisecond)
+	}
+	select {
+	case err := <-done:
+		t.Fatalf("serve returned %v with a request in flight", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	if status := <-answered; status != http.StatusOK {
+		t.Errorf("request in flight answered %d, want 200", status)
+	}
+	if err := <-done; err != nil {
+		t.Errorf("serve = %v", err)
+	}
+}
+
+func TestServeCutsRequestsAfterTimeout(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	defer close(release)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	})
+	url, cancel, done := startServe(t, config.ServerConfig{ShutdownTimeout: 20 * time.Millisecond}, handler, &controller.Readiness{})
+
+	go http.Get(url)
+	<-started
+	cancel()
+	if err := <-done; err == nil {
+		t.Error("serve = nil, want the error of the timed out shutdown")
+	}
+}
+
+func TestWaitForGivesUpAfterTimeout(t *testing.T) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	if waitFor(&wg, 10*time.Millisecond) {
+		t.Error("waitFor = true while the group is still running")
+	}
+	wg.Done()
+	if !waitFor(&wg, time.Second) {
+		t.Error("waitFor = false for a finished group")
+	}
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"armiariyan/attendances-system/entity"
+	"armiariyan/attendances-system/helper"
+	"armiariyan/attendances-system/repository"
+	"context"
+	"sort"
+	"time"
+)
+
+type AbsenceService interface {
+	DetectAbsences(ctx context.Context, date time.Time) ([]entity.Absence, error)
+	GetAbsenceReport(ctx context.Context, user_ids []int, department_id *int, startDate, endDate string) ([]helper.ResponseAbsenceReport, error)
+}
+
+type absenceService struct {
+	absenceRepository    repository.AbsenceRepository
+	userRepository       repository.UserRepository
+	attendanceRepository repository.AttendanceRepository
+	leaveRepository      repository.LeaveRepository
+	calendarService      CalendarService
+	departmentService    DepartmentService
+}
+
+func NewAbsenceService(absenceRepository repository.AbsenceRepository, userRepository repository.UserRepository, attendanceRepository repository.AttendanceRepository, leaveRepository repository.LeaveRepository, calendarService CalendarService, departmentService DepartmentService) AbsenceService {
+	return &absenceService{
+		absenceRepository:    absenceRepository,
+		userRepository:       userRepository,
+		attendanceRepository: attendanceRepository,
+		leaveRepository:      leaveRepository,
+		calendarService:      calendarService,
+		departmentService:    departmentService,
+	}
+}
+
+// DetectAbsences compares the expected work of the day with the check ins and stores
+// every unexplained day, running it again for the same day reconciles the result
+func (service *absenceService) DetectAbsences(ctx context.Context, date time.Time) ([]entity.Absence, error) {
+	day := date.Format("2006-01-02")
+	startDate, endDate := helper.DayRange(date)
+
+	holidays, err := service.calendarService.GetHolidays(ctx, day, day)
+	if err != nil {
+		return nil, err
+	}
+
+	// Nobody is expected outside the work week or on a holiday
+	absences := []entity.Absence{}
+	if !service.calendarService.IsWorkDay(date.Weekday()) || len(holidays) > 0 {
+		return service.saveAbsences(ctx, day, absences)
+	}
+
+	attendances, err := service.attendanceRepository.GetAllAttendancesByDate(ctx, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	users, err := service.userRepository.GetUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	checkedIn := map[int]bool{}
+	for _, attendance := range attendances {
+		if attendance.Label == entity.LabelCheckIn {
+			checkedIn[attendance.UserId] = true
+		}
+	}
+	onLeave := map[int]bool{}
+	for _, leave := range service.leaveRepository.GetApprovedLeavesByDate(ctx, day, day) {
+		onLeave[leave.UserId] = true
+	}
+
+	now := time.Now().UnixMilli()
+	for _, user := range users {
+		// Users registered after the day weren't expected yet
+		if user.CreatedAt > endDate || checkedIn[user.Id] || onLeave[user.Id] {
+			continue
+		}
+		absences = append(absences, entity.Absence{
+			UserId:     user.Id,
+			Date:       day,
+			DetectedAt: now,
+		})
+	}
+
+	return service.saveAbsences(ctx, day, absences)
+}
+
+// saveAbsences replaces the absences of the day and returns them as stored
+func (service *absenceService) saveAbsences(ctx context.Context, day string, absences []entity.Absence) ([]entity.Absence, error) {
+	if err := service.absenceRepository.SaveAbsences(ctx, day, absences); err != nil {
+		return nil, err
+	}
+	return service.absenceRepository.GetAbsencesByDate(ctx, day, day)
+}
+
+// GetAbsenceReport groups the stored absences of the range by user, user_ids limits the
+// report to a team and department_id to a department with its sub departments
+func (service *absenceService) GetAbsenceReport(ctx context.Context, user_ids []int, department_id *int, startDate, endDate string) ([]helper.ResponseAbsenceReport, error) {
+	users, err := service.userRepository.GetUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	absences, err := service.absenceRepository.GetAbsencesByDate(ctx, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	datesOf := map[int][]string{}
+	for _, absence := range absences {
+		datesOf[absence.UserId] = append(datesOf[absence.UserId], absence.Date)
+	}
+
+	scoped, err := service.departmentService.ScopeUsers(ctx, users, user_ids, department_id)
+	if err != nil {
+		return nil, err
+	}
+
+	report := []helper.ResponseAbsenceReport{}
+	for _, user := range scoped {
+		if len(datesOf[user.Id]) == 0 {
+			continue
+		}
+		report = append(report, helper.ResponseAbsenceReport{
+			UserId:       user.Id,
+			Name:         user.Name,
+			DepartmentId: user.DepartmentId,
+			Absences:     len(datesOf[user.Id]),
+			Dates:        datesOf[user.Id],
+		})
+	}
+
+	sort.SliceStable(report, func(i, j int) bool {
+		return report[i].Absences > report[j].Absences
+	})
+	return report, nil
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"armiariyan/attendances-system/dto"
+	"armiariyan/attendances-system/entity"
+	"armiariyan/attendances-system/helper"
+	"armiariyan/attendances-system/metrics"
+	"armiariyan/attendances-system/repository"
+	"context"
+	"fmt"
+	"time"
+)
+
+// ActivityService keeps the activities of the users, ErrNotFound for a missing activity
+type ActivityService interface {
+	GetActivityById(ctx context.Context, act_id string) (entity.Activity, error)
+	CreateActivity(ctx context.Context, data entity.Activity) (entity.Activity, error)
+	UpdateActivity(ctx context.Context, data entity.Activity) (entity.Activity, error)
+	DeleteActivity(ctx context.Context, data entity.Activity) error
+	GetActivitiesPage(ctx context.Context, user_id int, query dto.ActivityQueryDTO) ([]entity.Activity, helper.Pagination, error)
+}
+
+type activityService struct {
+	activityRepository repository.ActivityRepository
+}
+
+func NewActivityService(activityRepository repository.ActivityRepository) ActivityService {
+	return &activityService{
+		activityRepository: activityRepository,
+	}
+}
+
+func (service *activityService) GetActivityById(ctx context.Context, act_id string) (entity.Activity, error) {
+	return service.activityRepository.GetActivityById(ctx, act_id)
+}
+
+func (service *activityService) CreateActivity(ctx context.Context, data entity.Activity) (entity.Activity, error) {
+	var res entity.Activity
+	err := service.activityRepository.Transaction(ctx, func(tx repository.ActivityRepository) error {
+		var err error
+		if res, err = tx.CreateActivity(ctx, data); err != nil {
+			return err
+		}
+		return emit(ctx, tx, entity.EventActivityCreated, helper.CreateActivityResponse(res))
+	})
+	if err != nil {
+		return entity.Activity{}, fmt.Errorf("create activity of user %d: %w", data.UserId, err)
+	}
+	metrics.ActivitiesCreated.Inc()
+	return res, nil
+}
+
+func (service *activityService) UpdateActivity(ctx context.Context, data entity.Activity) (entity.Activity, error) {
+	var res entity.Activity
+	err := service.activityRepository.Transaction(ctx, func(tx repository.ActivityRepository) error {
+		var err error
+		if res, err = tx.UpdateActivity(ctx, data); err != nil {
+			return err
+		}
+		return emit(ctx, tx, entity.EventActivityUpdated, helper.CreateActivityResponse(res))
+	})
+	if err != nil {
+		return entity.Activity{}, fmt.Errorf("update activity %s: %w", data.Id, err)
+	}
+	return res, nil
+}
+
+func (service *activityService) DeleteActivity(ctx context.Context, data entity.Activity) error {
+	err := service.activityRepository.Transaction(ctx, func(tx repository.ActivityRepository) error {
+		if err := tx.DeleteActivity(ctx, data); err != nil {
+			return err
+		}
+		return emit(ctx, tx, entity.EventActivityDeleted, helper.CreateActivityResponse(data))
+	})
+	if err != nil {
+		return fmt.Errorf("delete activity %s: %w", data.Id, err)
+	}
+	return nil
+}
+
+func (service *activityService) GetActivitiesPage(ctx context.Context, user_id int, query dto.ActivityQueryDTO) ([]entity.Activity, helper.Pagination, error) {
+	options, pagination, err := historyOptions(query.PageDTO, query.DateRangeDTO, time.Now())
+	if err != nil {
+		return nil, pagination, err
+	}
+	options.Search = query.Query
+
+	activities, err := service.activityRepository.GetActivitiesPage(ctx, user_id, options)
+	if err != nil {
+		return nil, pagination, err
+	}
+	if len(activities) > options.Limit {
+		activities = activities[:options.Limit]
+		last := activities[len(activities)-1]
+		pagination.HasMore = true
+		pagination.NextCursor = helper.EncodeCursor(last.DateCreated, last.Id)
+	}
+	pagination.Count = len(activities)
+	return activities, pagination, nil
+}
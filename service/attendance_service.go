@@ -0,0 +1,90 @@
+package service
+
+import (
+	"armiariyan/attendances-system/dto"
+	"armiariyan/attendances-system/entity"
+	"armiariyan/attendances-system/helper"
+	"armiariyan/attendances-system/metrics"
+	"armiariyan/attendances-system/repository"
+	"context"
+	"fmt"
+	"time"
+)
+
+// AttendanceService saves the punches of the users and reads their history
+type AttendanceService interface {
+	SaveAttendance(ctx context.Context, data entity.Attendance) (entity.Attendance, error)
+	GetAttendancesHistory(ctx context.Context, user_id int) ([]entity.Attendance, error)
+	GetAttendancesByDate(ctx context.Context, user_id int, startDate, endDate int64) ([]entity.Attendance, error)
+	GetAttendancesPage(ctx context.Context, user_id int, query dto.AttendanceQueryDTO) ([]entity.Attendance, helper.Pagination, error)
+}
+
+type attendanceService struct {
+	attendanceRepository repository.AttendanceRepository
+}
+
+func NewAttendanceService(attendanceRepository repository.AttendanceRepository) AttendanceService {
+	return &attendanceService{
+		attendanceRepository: attendanceRepository,
+	}
+}
+
+// attendanceEvents maps the label of an attendance to the event it emits
+var attendanceEvents = map[string]string{
+	entity.LabelCheckIn:    entity.EventCheckedIn,
+	entity.LabelBreakStart: entity.EventBreakStarted,
+	entity.LabelBreakEnd:   entity.EventBreakEnded,
+	entity.LabelCheckOut:   entity.EventCheckedOut,
+}
+
+// SaveAttendance saves any punch of the attendance and emits the event of its label
+func (service *attendanceService) SaveAttendance(ctx context.Context, data entity.Attendance) (entity.Attendance, error) {
+	var res entity.Attendance
+	err := service.attendanceRepository.Transaction(ctx, func(tx repository.AttendanceRepository) error {
+		var err error
+		if res, err = tx.CreateAttendance(ctx, data); err != nil {
+			return err
+		}
+		return emit(ctx, tx, attendanceEvents[res.Label], helper.CreateAttendanceResponse(res))
+	})
+	if err != nil {
+		return entity.Attendance{}, fmt.Errorf("save %s of user %d: %w", data.Label, data.UserId, err)
+	}
+	switch res.Label {
+	case entity.LabelCheckIn:
+		metrics.CheckIns.Inc()
+	case entity.LabelCheckOut:
+		metrics.CheckOuts.Inc()
+	}
+	return res, nil
+}
+
+func (service *attendanceService) GetAttendancesHistory(ctx context.Context, user_id int) ([]entity.Attendance, error) {
+	return service.attendanceRepository.GetAttendancesHistory(ctx, user_id)
+}
+
+func (service *attendanceService) GetAttendancesByDate(ctx context.Context, user_id int, startDate, endDate int64) ([]entity.Attendance, error) {
+	return service.attendanceRepository.GetAttendancesByDate(ctx, user_id, startDate, endDate)
+}
+
+func (service *attendanceService) GetAttendancesPage(ctx context.Context, user_id int, query dto.AttendanceQueryDTO) ([]entity.Attendance, helper.Pagination, error) {
+	options, pagination, err := historyOptions(query.PageDTO, query.DateRangeDTO, time.Now())
+	if err != nil {
+		return nil, pagination, err
+	}
+	options.Label = query.Label
+	options.Search = query.Query
+
+	attendances, err := service.attendanceRepository.GetAttendancesPage(ctx, user_id, options)
+	if err != nil {
+		return nil, pagination, err
+	}
+	if len(attendances) > options.Limit {
+		attendances = attendances[:options.Limit]
+		last := attendances[len(attendances)-1]
+		pagination.HasMore = true
+		pagination.NextCursor = helper.EncodeCursor(last.Date, last.Id)
+	}
+	pagination.Count = len(attendances)
+	return attendances, pagination, nil
+}
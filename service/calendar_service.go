@@ -0,0 +1,75 @@
+package service
+
+import (
+	"armiariyan/attendances-system/dto"
+	"armiariyan/attendances-system/entity"
+	"armiariyan/attendances-system/repository"
+	"context"
+	"errors"
+	"time"
+)
+
+// defaultWorkDays is used when no work day is given
+var defaultWorkDays = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+
+// CalendarService returns the domain errors of the repository wrapped, ErrNotFound for a missing holiday
+type CalendarService interface {
+	GetHolidays(ctx context.Context, startDate, endDate string) ([]entity.Holiday, error)
+	GetHolidayById(ctx context.Context, holiday_id int) (entity.Holiday, error)
+	CreateHoliday(ctx context.Context, data dto.HolidayDTO) (entity.Holiday, error)
+	DeleteHoliday(ctx context.Context, holiday entity.Holiday) error
+	IsDuplicateHoliday(ctx context.Context, date string) (bool, error)
+	IsWorkDay(day time.Weekday) bool
+}
+
+type calendarService struct {
+	holidayRepository repository.HolidayRepository
+	workDays          map[time.Weekday]bool
+}
+
+func NewCalendarService(repository repository.HolidayRepository, workDays []time.Weekday) CalendarService {
+	if len(workDays) == 0 {
+		workDays = defaultWorkDays
+	}
+
+	service := &calendarService{
+		holidayRepository: repository,
+		workDays:          map[time.Weekday]bool{},
+	}
+	for _, day := range workDays {
+		service.workDays[day] = true
+	}
+	return service
+}
+
+func (service *calendarService) GetHolidays(ctx context.Context, startDate, endDate string) ([]entity.Holiday, error) {
+	return service.holidayRepository.GetHolidaysByDate(ctx, startDate, endDate)
+}
+
+func (service *calendarService) GetHolidayById(ctx context.Context, holiday_id int) (entity.Holiday, error) {
+	return service.holidayRepository.GetHolidayById(ctx, holiday_id)
+}
+
+func (service *calendarService) CreateHoliday(ctx context.Context, data dto.HolidayDTO) (entity.Holiday, error) {
+	return service.holidayRepository.CreateHoliday(ctx, entity.Holiday{
+		Date: data.Date,
+		Name: data.Name,
+	})
+}
+
+func (service *calendarService) DeleteHoliday(ctx context.Context, holiday entity.Holiday) error {
+	return service.holidayRepository.DeleteHoliday(ctx, holiday)
+}
+
+func (service *calendarService) IsDuplicateHoliday(ctx context.Context, date string) (bool, error) {
+	_, err := service.holidayRepository.GetHolidayByDate(ctx, date)
+	if errors.Is(err, repository.ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// IsWorkDay tells if the weekday belongs to the work week, holidays are not considered
+func (service *calendarService) IsWorkDay(day time.Weekday) bool {
+	return service.workDays[day]
+}
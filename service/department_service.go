@@ -0,0 +1,270 @@
+package service
+
+import (
+	"armiariyan/attendances-system/dto"
+	"armiariyan/attendances-system/entity"
+	"armiariyan/attendances-system/repository"
+	"context"
+	"errors"
+)
+
+var (
+	ErrParentNotFound        = errors.New("Parent department not found")
+	ErrManagerNotFound       = errors.New("Manager not found")
+	ErrDepartmentCycle       = errors.New("Department can't be placed under itself")
+	ErrDepartmentHasChildren = errors.New("Department still has sub departments")
+)
+
+// DepartmentService returns the domain errors of the repository wrapped, ErrNotFound for a missing department
+type DepartmentService interface {
+	GetDepartments(ctx context.Context) ([]entity.Department, error)
+	GetDepartmentById(ctx context.Context, department_id int) (entity.Department, error)
+	CreateDepartment(ctx context.Context, data dto.DepartmentDTO) (entity.Department, error)
+	UpdateDepartment(ctx context.Context, department entity.Department, data dto.DepartmentDTO) (entity.Department, error)
+	DeleteDepartment(ctx context.Context, department entity.Department) error
+	GetMembers(ctx context.Context, department_id int) ([]entity.User, error)
+	MoveUser(ctx context.Context, user_id int, department_id *int) error
+	GetReports(ctx context.Context, manager_id int, directOnly bool) ([]entity.User, error)
+	GetReportIds(ctx context.Context, manager_id int) ([]int, error)
+	GetSubDepartmentIds(ctx context.Context, department_id int) ([]int, error)
+	ScopeUsers(ctx context.Context, users []entity.User, user_ids []int, department_id *int) ([]entity.User, error)
+}
+
+type departmentService struct {
+	departmentRepository repository.DepartmentRepository
+	userRepository       repository.UserRepository
+}
+
+func NewDepartmentService(departmentRepository repository.DepartmentRepository, userRepository repository.UserRepository) DepartmentService {
+	return &departmentService{
+		departmentRepository: departmentRepository,
+		userRepository:       userRepository,
+	}
+}
+
+func (service *departmentService) GetDepartments(ctx context.Context) ([]entity.Department, error) {
+	return service.departmentRepository.GetDepartments(ctx)
+}
+
+func (service *departmentService) GetDepartmentById(ctx context.Context, department_id int) (entity.Department, error) {
+	return service.departmentRepository.GetDepartmentById(ctx, department_id)
+}
+
+func (service *departmentService) CreateDepartment(ctx context.Context, data dto.DepartmentDTO) (entity.Department, error) {
+	department := entity.Department{
+		Name:      data.Name,
+		ManagerId: data.ManagerId,
+		ParentId:  data.ParentId,
+	}
+	if err := service.validateDepartment(ctx, department); err != nil {
+		return entity.Department{}, err
+	}
+	return service.departmentRepository.CreateDepartment(ctx, department)
+}
+
+func (service *departmentService) UpdateDepartment(ctx context.Context, department entity.Department, data dto.DepartmentDTO) (entity.Department, error) {
+	department.Name = data.Name
+	department.ManagerId = data.ManagerId
+	department.ParentId = data.ParentId
+	if err := service.validateDepartment(ctx, department); err != nil {
+		return entity.Department{}, err
+	}
+	return service.departmentRepository.UpdateDepartment(ctx, department)
+}
+
+func (service *departmentService) DeleteDepartment(ctx context.Context, department entity.Department) error {
+	departments, err := service.departmentRepository.GetDepartments(ctx)
+	if err != nil {
+		return err
+	}
+	for _, other := range departments {
+		if other.ParentId != nil && *other.ParentId == department.Id {
+			return ErrDepartmentHasChildren
+		}
+	}
+	return service.departmentRepository.DeleteDepartment(ctx, department)
+}
+
+func (service *departmentService) GetMembers(ctx context.Context, department_id int) ([]entity.User, error) {
+	return service.departmentRepository.GetMembers(ctx, department_id)
+}
+
+func (service *departmentService) MoveUser(ctx context.Context, user_id int, department_id *int) error {
+	return service.departmentRepository.MoveUser(ctx, user_id, department_id)
+}
+
+// GetReports returns the users reporting to the manager, with directOnly false
+// the reports of those users are included down the whole hierarchy
+func (service *departmentService) GetReports(ctx context.Context, manager_id int, directOnly bool) ([]entity.User, error) {
+	users, err := service.userRepository.GetUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	departments, err := service.departmentsById(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build who reports to whom once
+	reportsOf := map[int][]entity.User{}
+	for _, user := range users {
+		if manager := managerOf(user, departments); manager != 0 {
+			reportsOf[manager] = append(reportsOf[manager], user)
+		}
+	}
+
+	if directOnly {
+		return reportsOf[manager_id], nil
+	}
+
+	var reports []entity.User
+	visited := map[int]bool{manager_id: true}
+	queue := []int{manager_id}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, user := range reportsOf[current] {
+			if visited[user.Id] {
+				continue
+			}
+			visited[user.Id] = true
+			reports = append(reports, user)
+			queue = append(queue, user.Id)
+		}
+	}
+	return reports, nil
+}
+
+// GetReportIds returns the ids of every direct and indirect report, used to scope data by team
+func (service *departmentService) GetReportIds(ctx context.Context, manager_id int) ([]int, error) {
+	reports, err := service.GetReports(ctx, manager_id, false)
+	if err != nil {
+		return nil, err
+	}
+	var ids []int
+	for _, user := range reports {
+		ids = append(ids, user.Id)
+	}
+	return ids, nil
+}
+
+// GetSubDepartmentIds returns the department id with the ids of every department below it
+func (service *departmentService) GetSubDepartmentIds(ctx context.Context, department_id int) ([]int, error) {
+	departments, err := service.departmentRepository.GetDepartments(ctx)
+	if err != nil {
+		return nil, err
+	}
+	childrenOf := map[int][]int{}
+	for _, department := range departments {
+		if department.ParentId != nil {
+			childrenOf[*department.ParentId] = append(childrenOf[*department.ParentId], department.Id)
+		}
+	}
+
+	ids := []int{department_id}
+	visited := map[int]bool{department_id: true}
+	for i := 0; i < len(ids); i++ {
+		for _, child := range childrenOf[ids[i]] {
+			if !visited[child] {
+				visited[child] = true
+				ids = append(ids, child)
+			}
+		}
+	}
+	return ids, nil
+}
+
+// ScopeUsers keeps the users listed in user_ids that belong to the department or one of
+// its sub departments, a nil user_ids or department_id doesn't filter
+func (service *departmentService) ScopeUsers(ctx context.Context, users []entity.User, user_ids []int, department_id *int) ([]entity.User, error) {
+	var inScope, inDepartment map[int]bool
+	if user_ids != nil {
+		inScope = map[int]bool{}
+		for _, id := range user_ids {
+			inScope[id] = true
+		}
+	}
+	if department_id != nil {
+		department_ids, err := service.GetSubDepartmentIds(ctx, *department_id)
+		if err != nil {
+			return nil, err
+		}
+		inDepartment = map[int]bool{}
+		for _, id := range department_ids {
+			inDepartment[id] = true
+		}
+	}
+
+	scoped := []entity.User{}
+	for _, user := range users {
+		if inScope != nil && !inScope[user.Id] {
+			continue
+		}
+		if inDepartment != nil && (user.DepartmentId == nil || !inDepartment[*user.DepartmentId]) {
+			continue
+		}
+		scoped = append(scoped, user)
+	}
+	return scoped, nil
+}
+
+func (service *departmentService) departmentsById(ctx context.Context) (map[int]entity.Department, error) {
+	saved, err := service.departmentRepository.GetDepartments(ctx)
+	if err != nil {
+		return nil, err
+	}
+	departments := map[int]entity.Department{}
+	for _, department := range saved {
+		departments[department.Id] = department
+	}
+	return departments, nil
+}
+
+func (service *departmentService) validateDepartment(ctx context.Context, department entity.Department) error {
+	if department.ManagerId != nil {
+		_, err := service.userRepository.GetUserById(ctx, *department.ManagerId)
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrManagerNotFound
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if department.ParentId == nil {
+		return nil
+	}
+
+	// Walk up from the new parent, meeting the department itself means a cycle
+	departments, err := service.departmentsById(ctx)
+	if err != nil {
+		return err
+	}
+	parentId := department.ParentId
+	if _, ok := departments[*parentId]; !ok {
+		return ErrParentNotFound
+	}
+	for steps := 0; parentId != nil && steps <= len(departments); steps++ {
+		if department.Id != 0 && *parentId == department.Id {
+			return ErrDepartmentCycle
+		}
+		parentId = departments[*parentId].ParentId
+	}
+	return nil
+}
+
+// managerOf returns the manager of the user department, a manager reports to
+// the manager of the parent department
+func managerOf(user entity.User, departments map[int]entity.Department) int {
+	departmentId := user.DepartmentId
+	for steps := 0; departmentId != nil && steps <= len(departments); steps++ {
+		department, ok := departments[*departmentId]
+		if !ok {
+			return 0
+		}
+		if department.ManagerId != nil && *department.ManagerId != user.Id {
+			return *department.ManagerId
+		}
+		departmentId = department.ParentId
+	}
+	return 0
+}
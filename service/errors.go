@@ -0,0 +1,11 @@
+package service
+
+import "armiariyan/attendances-system/repository"
+
+// Domain errors of the repositories, returned wrapped by the services so handlers
+// can answer them without knowing the repositories
+var (
+	ErrNotFound    = repository.ErrNotFound
+	ErrConflict    = repository.ErrConflict
+	ErrUnavailable = repository.ErrUnavailable
+)
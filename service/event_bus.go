@@ -0,0 +1,154 @@
+package service
+
+import (
+	"armiariyan/attendances-system/entity"
+	"armiariyan/attendances-system/logger"
+	"armiariyan/attendances-system/repository"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// outboxMaxAttempts is how often an event is dispatched before it is marked failed
+	outboxMaxAttempts = 10
+	outboxBatchSize   = 100
+)
+
+// EventAll subscribes a handler to every event type
+const EventAll = "*"
+
+// EventHandler reacts to a dispatched event. Delivery is at least once, the
+// same event comes again when the process stops before it is marked dispatched
+// or when another handler of the event failed, so handlers must be idempotent
+// (use event.EventId to drop duplicates)
+type EventHandler func(ctx context.Context, event entity.OutboxEvent) error
+
+type EventBus interface {
+	Subscribe(eventType string, handler EventHandler)
+	Dispatch(ctx context.Context, now time.Time) int
+}
+
+type eventBus struct {
+	outboxRepository repository.OutboxRepository
+	mu               sync.RWMutex
+	handlers         map[string][]EventHandler
+}
+
+func NewEventBus(repository repository.OutboxRepository) EventBus {
+	return &eventBus{
+		outboxRepository: repository,
+		handlers:         map[string][]EventHandler{},
+	}
+}
+
+func (bus *eventBus) Subscribe(eventType string, handler EventHandler) {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+	bus.handlers[eventType] = append(bus.handlers[eventType], handler)
+}
+
+// Dispatch hands the pending events to their subscribers in the order they
+// were saved and returns how many were tried, it stops early when ctx is done
+func (bus *eventBus) Dispatch(ctx context.Context, now time.Time) int {
+	events, err := bus.outboxRepository.GetPendingEvents(ctx, outboxBatchSize)
+	if err != nil {
+		logger.Log.WithError(err).Error("failed to get pending events")
+		return 0
+	}
+	for i, event := range events {
+		if ctx.Err() != nil {
+			return i
+		}
+		event.Attempts++
+
+		err := bus.handle(ctx, event)
+		switch {
+		case err == nil:
+			event.Status = entity.OutboxDispatched
+			event.DispatchedAt = now.UnixMilli()
+			event.LastError = ""
+		case event.Attempts >= outboxMaxAttempts:
+			event.Status = entity.OutboxFailed
+			event.LastError = truncate(err.Error(), 512)
+			logger.Log.WithFields(logrus.Fields{
+				"event_type": event.Type,
+				"event_id":   event.EventId,
+				"attempts":   event.Attempts,
+			}).WithError(err).Error("event failed")
+		default:
+			event.LastError = truncate(err.Error(), 512)
+		}
+		// The event stays pending when its result can't be saved, it is dispatched again
+		if _, err := bus.outboxRepository.UpdateEvent(ctx, event); err != nil {
+			logger.Log.WithFields(logrus.Fields{
+				"event_type": event.Type,
+				"event_id":   event.EventId,
+			}).WithError(err).Error("failed to save event")
+		}
+	}
+	return len(events)
+}
+
+func (bus *eventBus) handle(ctx context.Context, event entity.OutboxEvent) (err error) {
+	bus.mu.RLock()
+	handlers := append(append([]EventHandler{}, bus.handlers[event.Type]...), bus.handlers[EventAll]...)
+	bus.mu.RUnlock()
+
+	// A panicking subscriber must not stop the dispatcher
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("subscriber panicked: %v", recovered)
+		}
+	}()
+
+	for _, handler := range handlers {
+		if handlerErr := handler(ctx, event); handlerErr != nil && err == nil {
+			err = handlerErr
+		}
+	}
+	return err
+}
+
+// NewOutboxEvent encodes data as the payload of a pending event
+func NewOutboxEvent(eventType string, data interface{}) (entity.OutboxEvent, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return entity.OutboxEvent{}, fmt.Errorf("event %s: failed to encode payload: %v", eventType, err)
+	}
+	return entity.OutboxEvent{
+		EventId:    newEventId(),
+		Type:       eventType,
+		Payload:    string(payload),
+		Status:     entity.OutboxPending,
+		OccurredAt: time.Now().UnixMilli(),
+	}, nil
+}
+
+// eventRecorder is a repository that saves events, bound to the transaction of a change
+type eventRecorder interface {
+	AddEvent(ctx context.Context, event entity.OutboxEvent) error
+}
+
+// emit saves the event with the transaction of the change that caused it
+func emit(ctx context.Context, tx eventRecorder, eventType string, data interface{}) error {
+	event, err := NewOutboxEvent(eventType, data)
+	if err != nil {
+		return err
+	}
+	return tx.AddEvent(ctx, event)
+}
+
+func newEventId() string {
+	random := make([]byte, 12)
+	if _, err := rand.Read(random); err != nil {
+		panic("Failed to generate event id")
+	}
+	return "EVT-" + hex.EncodeToString(random)
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"armiariyan/attendances-system/dto"
+	"armiariyan/attendances-system/entity"
+	"armiariyan/attendances-system/repository"
+	"armiariyan/attendances-system/repository/memory"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// recordingOutbox keeps every event the bus updates, the last update of an event is its state
+type recordingOutbox struct {
+	repository.OutboxRepository
+	updates []entity.OutboxEvent
+}
+
+func (outbox *recordingOutbox) UpdateEvent(ctx context.Context, data entity.OutboxEvent) (entity.OutboxEvent, error) {
+	outbox.updates = append(outbox.updates, data)
+	return outbox.OutboxRepository.UpdateEvent(ctx, data)
+}
+
+// newTestEventBus checks in a user on a memory store, which saves one pending event
+func newTestEventBus(t *testing.T) (*eventBus, *recordingOutbox) {
+	store := memory.NewStore()
+	user, err := memory.NewUserRepository(store).RegisterUser(context.Background(), entity.User{Name: "Ana", Email: "ana@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	attendances := NewAttendanceService(memory.NewAttendanceRepository(store))
+	if _, err := attendances.SaveAttendance(context.Background(), entity.Attendance{Id: "ATD-1", UserId: user.Id, Label: entity.LabelCheckIn}); err != nil {
+		t.Fatal(err)
+	}
+
+	outbox := &recordingOutbox{OutboxRepository: memory.NewOutboxRepository(store)}
+	return NewEventBus(outbox).(*eventBus), outbox
+}
+
+func TestDispatchMarksEventsDispatched(t *testing.T) {
+	bus, outbox := newTestEventBus(t)
+	var handled, all []string
+	bus.Subscribe(entity.EventCheckedIn, func(_ context.Context, event entity.OutboxEvent) error {
+		handled = append(handled, event.Type)
+		return nil
+	})
+	bus.Subscribe(EventAll, func(_ context.Context, event entity.OutboxEvent) error {
+		all = append(all, event.Type)
+		return nil
+	})
+	bus.Subscribe(entity.EventCheckedOut, func(_ context.Context, event entity.OutboxEvent) error {
+		t.Errorf("handler of %s got %s", entity.EventCheckedOut, event.Type)
+		return nil
+	})
+
+	now := time.UnixMilli(5000)
+	if tried := bus.Dispatch(context.Background(), now); tried != 1 {
+		t.Fatalf("Dispatch tried %d events, want 1", tried)
+	}
+	if len(handled) != 1 || len(all) != 1 {
+		t.Errorf("handled %v and %v, want the check in once by each", handled, all)
+	}
+	event := outbox.updates[0]
+	if event.Status != entity.OutboxDispatched || event.Attempts != 1 || event.DispatchedAt != now.UnixMilli() {
+		t.Errorf("event = %+v, want dispatched at %d on the first attempt", event, now.UnixMilli())
+	}
+	if tried := bus.Dispatch(context.Background(), now); tried != 0 {
+		t.Errorf("second Dispatch tried %d events, want none", tried)
+	}
+}
+
+func TestDispatchRetriesThenFails(t *testing.T) {
+	bus, outbox := newTestEventBus(t)
+	bus.Subscribe(entity.EventCheckedIn, func(_ context.Context, event entity.OutboxEvent) error {
+		return errors.New("subscriber is down")
+	})
+
+	for attempt := 1; attempt < outboxMaxAttempts; attempt++ {
+		bus.Dispatch(context.Background(), time.Now())
+		event := outbox.updates[len(outbox.updates)-1]
+		if event.Status != entity.OutboxPending || event.Attempts != attempt || event.LastError != "subscriber is down" {
+			t.Fatalf("after attempt %d event = %+v, want pending with the error", attempt, event)
+		}
+	}
+
+	bus.Dispatch(context.Background(), time.Now())
+	event := outbox.updates[len(outbox.updates)-1]
+	if event.Status != entity.OutboxFailed || event.Attempts != outboxMaxAttempts {
+		t.Errorf("event = %+v, want failed after %d attempts", event, outboxMaxAttempts)
+	}
+	if tried := bus.Dispatch(context.Background(), time.Now()); tried != 0 {
+		t.Errorf("Dispatch tried %d events, want the failed event left alone", tried)
+	}
+}
+
+func TestDispatchStopsWhenContextDone(t *testing.T) {
+	bus, outbox := newTestEventBus(t)
+	bus.Subscribe(entity.EventCheckedIn, func(_ context.Context, event entity.OutboxEvent) error {
+		t.Errorf("handler got %s after the context was done", event.Type)
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if tried := bus.Dispatch(ctx, time.Now()); tried != 0 || len(outbox.updates) != 0 {
+		t.Errorf("Dispatch tried %d events and updated %+v, want the event left pending", tried, outbox.updates)
+	}
+}
+
+// unavailableWebhooks is a memory webhook repository that can't read the subscriptions while down
+type unavailableWebhooks struct {
+	repository.WebhookRepository
+	down bool
+}
+
+func (webhooks *unavailableWebhooks) GetSubscriptions(ctx context.Context) ([]entity.WebhookSubscription, error) {
+	if webhooks.down {
+		return nil, repository.ErrUnavailable
+	}
+	return webhooks.WebhookRepository.GetSubscriptions(ctx)
+}
+
+// newWebhookEventBus subscribes the webhook service to the bus like main does
+func newWebhookEventBus(t *testing.T) (*eventBus, *recordingOutbox, *unavailableWebhooks, entity.WebhookSubscription) {
+	bus, outbox := newTestEventBus(t)
+	webhooks := &unavailableWebhooks{WebhookRepository: memory.NewWebhookRepository(memory.NewStore()), down: true}
+	webhookService := NewWebhookService(webhooks)
+	subscription := subscribe(t, webhookService, dto.WebhookDTO{URL: "http://127.0.0.1", EventTypes: []string{entity.EventCheckedIn}})
+	for _, eventType := range entity.WebhookEvents {
+		bus.Subscribe(eventType, webhookService.Publish)
+	}
+	return bus, outbox, webhooks, subscription
+}
+
+func TestDispatchRetriesWebhooksUntilRepositoryRecovers(t *testing.T) {
+	bus, outbox, webhooks, subscription := newWebhookEventBus(t)
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		bus.Dispatch(context.Background(), time.Now())
+		event := outbox.updates[len(outbox.updates)-1]
+		if event.Status != entity.OutboxPending || event.Attempts != attempt || !strings.Contains(event.LastError, repository.ErrUnavailable.Error()) {
+			t.Fatalf("after attempt %d event = %+v, want pending with the repository error", attempt, event)
+		}
+	}
+
+	webhooks.down = false
+	bus.Dispatch(context.Background(), time.Now())
+	event := outbox.updates[len(outbox.updates)-1]
+	if event.Status != entity.OutboxDispatched || event.Attempts != 3 {
+		t.Errorf("event = %+v, want dispatched on the third attempt", event)
+	}
+	deliveries, err := webhooks.GetDeliveriesBySubscription(context.Background(), subscription.Id)
+	if err != nil || len(deliveries) != 1 || deliveries[0].EventId != event.EventId {
+		t.Errorf("deliveries = %+v %v, want the check in queued once", deliveries, err)
+	}
+}
+
+func TestDispatchFailsWebhooksWhenRepositoryStaysDown(t *testing.T) {
+	bus, outbox, webhooks, subscription := newWebhookEventBus(t)
+
+	for attempt := 1; attempt <= outboxMaxAttempts; attempt++ {
+		bus.Dispatch(context.Background(), time.Now())
+	}
+	event := outbox.updates[len(outbox.updates)-1]
+	if event.Status != entity.OutboxFailed || event.Attempts != outboxMaxAttempts {
+		t.Errorf("event = %+v, want failed after %d attempts", event, outboxMaxAttempts)
+	}
+	if deliveries, _ := webhooks.GetDeliveriesBySubscription(context.Background(), subscription.Id); len(deliveries) != 0 {
+		t.Errorf("deliveries = %+v, want none", deliveries)
+	}
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"armiariyan/attendances-system/dto"
+	"armiariyan/attendances-system/helper"
+	"armiariyan/attendances-system/repository"
+	"time"
+)
+
+// historyOptions turns the query of a history endpoint into repository options,
+// newest first unless sort is asc. Either end of the date range may be left open
+func historyOptions(page dto.PageDTO, dates dto.DateRangeDTO, now time.Time) (repository.HistoryOptions, helper.Pagination, error) {
+	options := repository.HistoryOptions{Limit: page.Limit, Descending: page.Sort != helper.SortAsc}
+	if options.Limit == 0 {
+		options.Limit = helper.DefaultPageLimit
+	}
+	pagination := helper.Pagination{Limit: options.Limit, Sort: helper.SortDesc}
+	if !options.Descending {
+		pagination.Sort = helper.SortAsc
+	}
+
+	if page.Cursor != "" {
+		cursorTime, cursorId, err := helper.DecodeCursor(page.Cursor)
+		if err != nil {
+			return options, pagination, err
+		}
+		options.CursorTime, options.CursorId = cursorTime, cursorId
+	}
+
+	timeRange, err := helper.ParseTimeRange(helper.RangeQuery{
+		Start:        dates.StartDate,
+		End:          dates.EndDate,
+		Range:        dates.Range,
+		TimeZone:     dates.TimeZone,
+		EndExclusive: dates.EndExclusive,
+	}, now)
+	if err != nil {
+		return options, pagination, err
+	}
+	options.StartDate, options.EndDate = timeRange.UnixMilli()
+	return options, pagination, nil
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"armiariyan/attendances-system/entity"
+	"armiariyan/attendances-system/logger"
+	"armiariyan/attendances-system/repository"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/skip2/go-qrcode"
+)
+
+// KioskTokenTTL is how long a qr code shown on the kiosk stays valid
+const KioskTokenTTL = 30 * time.Second
+
+// kioskClockSkew tolerates small clock differences between kiosk and server
+const kioskClockSkew = 5 * time.Second
+
+var (
+	ErrKioskTokenInvalid  = errors.New("QR code is invalid")
+	ErrKioskTokenExpired  = errors.New("QR code has expired, please scan the current one")
+	ErrKioskTokenReplayed = errors.New("QR code has already been used")
+)
+
+type KioskService interface {
+	GenerateToken() (token string, expiresAt time.Time)
+	GenerateQRCode(token string, size int) ([]byte, error)
+	ValidateToken(ctx context.Context, token string, user_id int) error
+	IsValidKioskKey(key string) bool
+}
+
+type kioskService struct {
+	kioskRepository repository.KioskRepository
+	secret          []byte
+	kioskKey        string
+}
+
+func NewKioskService(repository repository.KioskRepository, kioskSecret, kioskKey string) KioskService {
+	secret := []byte(kioskSecret)
+	if len(secret) == 0 {
+		// Tokens still work on a single instance but won't survive a restart
+		logger.Log.Warn("kiosk.secret is empty, using a random secret for kiosk tokens")
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			panic("Failed to generate kiosk secret")
+		}
+	}
+
+	return &kioskService{
+		kioskRepository: repository,
+		secret:          secret,
+		kioskKey:        kioskKey,
+	}
+}
+
+// GenerateToken creates a token in the form "<issued at>.<nonce>.<signature>"
+func (service *kioskService) GenerateToken() (string, time.Time) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		panic("Failed to generate kiosk token")
+	}
+
+	issuedAt := time.Now()
+	payload := fmt.Sprintf("%d.%s", issuedAt.Unix(), hex.EncodeToString(nonce))
+	return payload + "." + service.sign(payload), issuedAt.Add(KioskTokenTTL)
+}
+
+func (service *kioskService) GenerateQRCode(token string, size int) ([]byte, error) {
+	return qrcode.Encode(token, qrcode.Medium, size)
+}
+
+func (service *kioskService) ValidateToken(ctx context.Context, token string, user_id int) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return ErrKioskTokenInvalid
+	}
+
+	// Check signature
+	payload := parts[0] + "." + parts[1]
+	if !hmac.Equal([]byte(service.sign(payload)), []byte(parts[2])) {
+		return ErrKioskTokenInvalid
+	}
+
+	// Check freshness
+	issuedUnix, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return ErrKioskTokenInvalid
+	}
+	now := time.Now()
+	issuedAt := time.Unix(issuedUnix, 0)
+	if issuedAt.After(now.Add(kioskClockSkew)) || now.Sub(issuedAt) > KioskTokenTTL+kioskClockSkew {
+		return ErrKioskTokenExpired
+	}
+
+	// Tokens older than the ttl are rejected above, so their replay records can go
+	if err := service.kioskRepository.DeleteTokensUsedBefore(ctx, now.Add(-2*(KioskTokenTTL+kioskClockSkew)).UnixMilli()); err != nil {
+		return err
+	}
+
+	// Check replay, a token that can't be stored isn't a replayed one
+	used, err := service.kioskRepository.UseToken(ctx, entity.KioskToken{
+		Nonce:  parts[1],
+		UserId: user_id,
+		UsedAt: now.UnixMilli(),
+	})
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrKioskTokenReplayed
+	}
+	return nil
+}
+
+// IsValidKioskKey checks the key a kiosk device uses to fetch qr codes
+func (service *kioskService) IsValidKioskKey(key string) bool {
+	if service.kioskKey == "" {
+		return false
+	}
+	return hmac.Equal([]byte(service.kioskKey), []byte(key))
+}
+
+func (service *kioskService) sign(payload string) string {
+	mac := hmac.New(sha256.New, service.secret)
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"armiariyan/attendances-system/dto"
+	"armiariyan/attendances-system/entity"
+	"armiariyan/attendances-system/helper"
+	"armiariyan/attendances-system/repository"
+	"context"
+	"errors"
+)
+
+var (
+	ErrLeaveInvalidRange = errors.New("end_date must not be before start_date")
+	ErrLeaveReviewed     = errors.New("Leave has already been reviewed")
+)
+
+type LeaveService interface {
+	RequestLeave(ctx context.Context, user_id int, data dto.LeaveDTO) (entity.Leave, error)
+	GetLeaveById(ctx context.Context, leave_id int) entity.Leave
+	GetLeaves(ctx context.Context, user_id int) []entity.Leave
+	ReviewLeave(ctx context.Context, leave entity.Leave, reviewer_id int, status string) (entity.Leave, error)
+	CanReview(ctx context.Context, reviewer_id int, leave entity.Leave) (bool, error)
+	GetApprovedLeavesByDate(ctx context.Context, startDate, endDate string) []entity.Leave
+}
+
+type leaveService struct {
+	leaveRepository   repository.LeaveRepository
+	userRepository    repository.UserRepository
+	departmentService DepartmentService
+}
+
+func NewLeaveService(leaveRepository repository.LeaveRepository, userRepository repository.UserRepository, departmentService DepartmentService) LeaveService {
+	return &leaveService{
+		leaveRepository:   leaveRepository,
+		userRepository:    userRepository,
+		departmentService: departmentService,
+	}
+}
+
+func (service *leaveService) RequestLeave(ctx context.Context, user_id int, data dto.LeaveDTO) (entity.Leave, error) {
+	// Dates are already validated as 2006-01-02 so they compare as strings
+	if data.EndDate < data.StartDate {
+		return entity.Leave{}, ErrLeaveInvalidRange
+	}
+
+	return service.leaveRepository.CreateLeave(ctx, entity.Leave{
+		UserId:    user_id,
+		Type:      data.Type,
+		StartDate: data.StartDate,
+		EndDate:   data.EndDate,
+		Reason:    data.Reason,
+		Status:    entity.LeaveStatusPending,
+	})
+}
+
+func (service *leaveService) GetLeaveById(ctx context.Context, leave_id int) entity.Leave {
+	return service.leaveRepository.GetLeaveById(ctx, leave_id)
+}
+
+func (service *leaveService) GetLeaves(ctx context.Context, user_id int) []entity.Leave {
+	return service.leaveRepository.GetLeavesByUser(ctx, user_id)
+}
+
+func (service *leaveService) ReviewLeave(ctx context.Context, leave entity.Leave, reviewer_id int, status string) (entity.Leave, error) {
+	if leave.Status != entity.LeaveStatusPending {
+		return entity.Leave{}, ErrLeaveReviewed
+	}
+
+	leave.Status = status
+	leave.ReviewerId = &reviewer_id
+	return service.leaveRepository.UpdateLeave(ctx, leave)
+}
+
+// CanReview allows admins and any manager above the leave owner, but never the owner
+func (service *leaveService) CanReview(ctx context.Context, reviewer_id int, leave entity.Leave) (bool, error) {
+	if reviewer_id == leave.UserId {
+		return false, nil
+	}
+	reviewer, err := service.userRepository.GetUserById(ctx, reviewer_id)
+	if err != nil {
+		return false, err
+	}
+	if helper.IsAdmin(reviewer) {
+		return true, nil
+	}
+	report_ids, err := service.departmentService.GetReportIds(ctx, reviewer_id)
+	if err != nil {
+		return false, err
+	}
+	for _, report_id := range report_ids {
+		if report_id == leave.UserId {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (service *leaveService) GetApprovedLeavesByDate(ctx context.Context, startDate, endDate string) []entity.Leave {
+	return service.leaveRepository.GetApprovedLeavesByDate(ctx, startDate, endDate)
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"armiariyan/attendances-system/dto"
+	"armiariyan/attendances-system/entity"
+	"armiariyan/attendances-system/repository"
+	"context"
+	"net"
+)
+
+// NetworkService returns the domain errors of the repository wrapped, ErrNotFound for a missing network
+type NetworkService interface {
+	GetNetworks(ctx context.Context) ([]entity.OfficeNetwork, error)
+	GetNetworkById(ctx context.Context, network_id int) (entity.OfficeNetwork, error)
+	CreateNetwork(ctx context.Context, data dto.CreateNetworkDTO) (entity.OfficeNetwork, error)
+	DeleteNetwork(ctx context.Context, network entity.OfficeNetwork) error
+	IsDuplicateNetwork(ctx context.Context, cidr string) (bool, error)
+	ResolveLocation(ctx context.Context, clientIP string) (string, error)
+}
+
+type networkService struct {
+	networkRepository repository.NetworkRepository
+}
+
+func NewNetworkService(repository repository.NetworkRepository) NetworkService {
+	return &networkService{
+		networkRepository: repository,
+	}
+}
+
+func (service *networkService) GetNetworks(ctx context.Context) ([]entity.OfficeNetwork, error) {
+	return service.networkRepository.GetNetworks(ctx)
+}
+
+func (service *networkService) GetNetworkById(ctx context.Context, network_id int) (entity.OfficeNetwork, error) {
+	return service.networkRepository.GetNetworkById(ctx, network_id)
+}
+
+func (service *networkService) CreateNetwork(ctx context.Context, data dto.CreateNetworkDTO) (entity.OfficeNetwork, error) {
+	// Store the canonical form so "10.0.0.1/8" is saved as "10.0.0.0/8"
+	_, ipNet, _ := net.ParseCIDR(data.CIDR)
+	networkToCreate := entity.OfficeNetwork{
+		Name: data.Name,
+		CIDR: ipNet.String(),
+	}
+	return service.networkRepository.CreateNetwork(ctx, networkToCreate)
+}
+
+func (service *networkService) DeleteNetwork(ctx context.Context, network entity.OfficeNetwork) error {
+	return service.networkRepository.DeleteNetwork(ctx, network)
+}
+
+func (service *networkService) IsDuplicateNetwork(ctx context.Context, cidr string) (bool, error) {
+	_, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return false, nil
+	}
+	networks, err := service.networkRepository.GetNetworks(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, network := range networks {
+		if network.CIDR == ipNet.String() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ResolveLocation returns "onsite" when the client ip belongs to one of the office networks
+func (service *networkService) ResolveLocation(ctx context.Context, clientIP string) (string, error) {
+	ip := net.ParseIP(clientIP)
+	if ip == nil {
+		return entity.LocationRemote, nil
+	}
+
+	networks, err := service.networkRepository.GetNetworks(ctx)
+	if err != nil {
+		return "", err
+	}
+	for _, network := range networks {
+		_, ipNet, err := net.ParseCIDR(network.CIDR)
+		if err != nil {
+			continue
+		}
+		if ipNet.Contains(ip) {
+			return entity.LocationOnsite, nil
+		}
+	}
+	return entity.LocationRemote, nil
+}
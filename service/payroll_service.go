@@ -0,0 +1,118 @@
+package service
+
+import (
+	"armiariyan/attendances-system/export"
+	"armiariyan/attendances-system/helper"
+	"armiariyan/attendances-system/repository"
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+// defaultDailyWorkHours is used when no daily work hours are given
+const defaultDailyWorkHours = 8
+
+const (
+	PayrollFormatCSV        = "csv"
+	PayrollFormatFixedWidth = "fixed"
+)
+
+var ErrPayrollFormat = errors.New("format must be csv or fixed")
+
+type PayrollService interface {
+	GetPayrollRows(ctx context.Context, from, to time.Time) ([]export.PayrollRow, error)
+	Export(ctx context.Context, w io.Writer, from, to time.Time, format string) error
+}
+
+type payrollService struct {
+	userRepository   repository.UserRepository
+	timesheetService TimesheetService
+	calendarService  CalendarService
+	dailyWorkHours   float64
+	layout           export.FixedWidthLayout
+}
+
+func NewPayrollService(userRepository repository.UserRepository, timesheetService TimesheetService, calendarService CalendarService, dailyWorkHours float64, layoutPath string) PayrollService {
+	if dailyWorkHours <= 0 {
+		dailyWorkHours = defaultDailyWorkHours
+	}
+
+	layout, err := export.LoadFixedWidthLayout(layoutPath)
+	if err != nil {
+		panic("Failed to load payroll fixed width layout: " + err.Error())
+	}
+
+	return &payrollService{
+		userRepository:   userRepository,
+		timesheetService: timesheetService,
+		calendarService:  calendarService,
+		dailyWorkHours:   dailyWorkHours,
+		layout:           layout,
+	}
+}
+
+// Export writes the payroll file of the range in the given format
+func (service *payrollService) Export(ctx context.Context, w io.Writer, from, to time.Time, format string) error {
+	if format != PayrollFormatCSV && format != PayrollFormatFixedWidth {
+		return ErrPayrollFormat
+	}
+	rows, err := service.GetPayrollRows(ctx, from, to)
+	if err != nil {
+		return err
+	}
+	if format == PayrollFormatCSV {
+		return export.WriteCSV(w, rows)
+	}
+	return export.WriteFixedWidth(w, rows, service.layout)
+}
+
+// GetPayrollRows sums the timesheet of every employee from until to, hours above the
+// daily work hours and any hour worked on a holiday or outside the work week are overtime
+func (service *payrollService) GetPayrollRows(ctx context.Context, from, to time.Time) ([]export.PayrollRow, error) {
+	users, err := service.userRepository.GetUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	saved, err := service.calendarService.GetHolidays(ctx, from.Format("2006-01-02"), to.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	holidays := map[string]bool{}
+	for _, holiday := range saved {
+		holidays[holiday.Date] = true
+	}
+
+	rows := []export.PayrollRow{}
+	for _, user := range users {
+		row := export.PayrollRow{EmployeeId: user.Id}
+
+		days, err := service.timesheetService.GetDays(ctx, user.Id, from, to)
+		if err != nil {
+			return nil, err
+		}
+		for _, day := range days {
+			switch day.Status {
+			case helper.DayWorked:
+				date, _ := time.ParseInLocation("2006-01-02", day.Date, time.Local)
+				if holidays[day.Date] || !service.calendarService.IsWorkDay(date.Weekday()) {
+					row.OvertimeHours += day.WorkedHours
+				} else if day.WorkedHours > service.dailyWorkHours {
+					row.RegularHours += service.dailyWorkHours
+					row.OvertimeHours += day.WorkedHours - service.dailyWorkHours
+				} else {
+					row.RegularHours += day.WorkedHours
+				}
+			case helper.DayLeave:
+				row.LeaveDays++
+			case helper.DayAbsent:
+				row.Absences++
+			}
+		}
+
+		row.RegularHours = helper.RoundHours(row.RegularHours)
+		row.OvertimeHours = helper.RoundHours(row.OvertimeHours)
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"armiariyan/attendances-system/dto"
+	"armiariyan/attendances-system/entity"
+	"armiariyan/attendances-system/helper"
+	"armiariyan/attendances-system/repository"
+	"context"
+	"errors"
+	"time"
+)
+
+// PolicyService returns the domain errors of the repository wrapped, ErrNotFound for a user without policy
+type PolicyService interface {
+	GetPolicy(ctx context.Context, user_id int) (entity.WorkPolicy, error)
+	SetPolicy(ctx context.Context, user_id int, data dto.WorkPolicyDTO) (entity.WorkPolicy, error)
+	DeletePolicy(ctx context.Context, policy entity.WorkPolicy) error
+	CanWorkRemote(ctx context.Context, user_id int, now time.Time) (bool, error)
+}
+
+type policyService struct {
+	policyRepository     repository.PolicyRepository
+	attendanceRepository repository.AttendanceRepository
+}
+
+func NewPolicyService(policyRepository repository.PolicyRepository, attendanceRepository repository.AttendanceRepository) PolicyService {
+	return &policyService{
+		policyRepository:     policyRepository,
+		attendanceRepository: attendanceRepository,
+	}
+}
+
+func (service *policyService) GetPolicy(ctx context.Context, user_id int) (entity.WorkPolicy, error) {
+	return service.policyRepository.GetPolicyByUserId(ctx, user_id)
+}
+
+func (service *policyService) SetPolicy(ctx context.Context, user_id int, data dto.WorkPolicyDTO) (entity.WorkPolicy, error) {
+	return service.policyRepository.SavePolicy(ctx, entity.WorkPolicy{
+		UserId:               user_id,
+		MaxRemoteDaysPerWeek: *data.MaxRemoteDaysPerWeek,
+	})
+}
+
+func (service *policyService) DeletePolicy(ctx context.Context, policy entity.WorkPolicy) error {
+	return service.policyRepository.DeletePolicy(ctx, policy)
+}
+
+// CanWorkRemote checks the remote days already used this week against the user policy
+func (service *policyService) CanWorkRemote(ctx context.Context, user_id int, now time.Time) (bool, error) {
+	policy, err := service.policyRepository.GetPolicyByUserId(ctx, user_id)
+	if errors.Is(err, repository.ErrNotFound) {
+		// No policy, no limit
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	startOfWeek := helper.StartOfWeek(now)
+	attendances, err := service.attendanceRepository.GetAttendancesByDate(ctx, user_id, startOfWeek.UnixMilli(), now.UnixMilli())
+	if err != nil {
+		return false, err
+	}
+
+	today := now.Format("2006-01-02")
+	remoteDays := map[string]bool{}
+	for _, attendance := range attendances {
+		if attendance.Label == "check in" && attendance.WorkMode == entity.WorkModeRemote {
+			remoteDays[helper.UnixMilliToString(attendance.Date, "date")] = true
+		}
+	}
+
+	// A second remote check in on the same day doesn't use another day
+	if remoteDays[today] {
+		return true, nil
+	}
+	return len(remoteDays) < policy.MaxRemoteDaysPerWeek, nil
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"armiariyan/attendances-system/entity"
+	"armiariyan/attendances-system/helper"
+	"armiariyan/attendances-system/repository"
+	"context"
+	"sort"
+	"time"
+)
+
+type PresenceService interface {
+	GetPresenceBoard(ctx context.Context, user_ids []int, department_id *int) ([]helper.ResponsePresence, error)
+}
+
+type presenceService struct {
+	userRepository       repository.UserRepository
+	attendanceRepository repository.AttendanceRepository
+	leaveRepository      repository.LeaveRepository
+	departmentService    DepartmentService
+}
+
+func NewPresenceService(userRepository repository.UserRepository, attendanceRepository repository.AttendanceRepository, leaveRepository repository.LeaveRepository, departmentService DepartmentService) PresenceService {
+	return &presenceService{
+		userRepository:       userRepository,
+		attendanceRepository: attendanceRepository,
+		leaveRepository:      leaveRepository,
+		departmentService:    departmentService,
+	}
+}
+
+// GetPresenceBoard returns today's status of every user, user_ids limits the board to
+// a team and department_id to a department with its sub departments, nil means no limit
+func (service *presenceService) GetPresenceBoard(ctx context.Context, user_ids []int, department_id *int) ([]helper.ResponsePresence, error) {
+	now := time.Now()
+	startDate, endDate := helper.DayRange(now)
+	today := now.Format("2006-01-02")
+
+	users, err := service.userRepository.GetUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	attendances, err := service.attendanceRepository.GetAllAttendancesByDate(ctx, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	// Group today's punches and leaves by user
+	attendancesOf := map[int][]entity.Attendance{}
+	for _, attendance := range attendances {
+		attendancesOf[attendance.UserId] = append(attendancesOf[attendance.UserId], attendance)
+	}
+	onLeave := map[int]bool{}
+	for _, leave := range service.leaveRepository.GetApprovedLeavesByDate(ctx, today, today) {
+		onLeave[leave.UserId] = true
+	}
+
+	scoped, err := service.departmentService.ScopeUsers(ctx, users, user_ids, department_id)
+	if err != nil {
+		return nil, err
+	}
+
+	board := []helper.ResponsePresence{}
+	for _, user := range scoped {
+		presence := helper.ResponsePresence{
+			UserId:       user.Id,
+			Name:         user.Name,
+			DepartmentId: user.DepartmentId,
+			Status:       helper.PresenceOf(attendancesOf[user.Id]),
+		}
+
+		// Punches win over leave, someone on leave may still come in
+		sessions := helper.PairAttendances(attendancesOf[user.Id])
+		if len(sessions) > 0 {
+			punches := attendancesOf[user.Id]
+			presence.WorkMode = sessions[len(sessions)-1].CheckIn.WorkMode
+			presence.CheckedInAt = helper.UnixMilliToString(sessions[0].CheckIn.Time, "time")
+			presence.LastPunchAt = helper.UnixMilliToString(punches[len(punches)-1].Time, "time")
+		} else if onLeave[user.Id] {
+			presence.Status = helper.PresenceOnLeave
+		}
+		board = append(board, presence)
+	}
+
+	sort.SliceStable(board, func(i, j int) bool {
+		return board[i].Name < board[j].Name
+	})
+	return board, nil
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"armiariyan/attendances-system/entity"
+	"armiariyan/attendances-system/helper"
+	"armiariyan/attendances-system/repository"
+	"context"
+)
+
+type ReportService interface {
+	GetWorkModeReport(ctx context.Context, user_id int, startDate, endDate int64) (helper.ResponseWorkModeReport, error)
+}
+
+type reportService struct {
+	attendanceRepository repository.AttendanceRepository
+}
+
+func NewReportService(attendanceRepository repository.AttendanceRepository) ReportService {
+	return &reportService{
+		attendanceRepository: attendanceRepository,
+	}
+}
+
+// GetWorkModeReport breaks the worked hours in range down by the work mode of each check in
+func (service *reportService) GetWorkModeReport(ctx context.Context, user_id int, startDate, endDate int64) (helper.ResponseWorkModeReport, error) {
+	attendances, err := service.attendanceRepository.GetAttendancesByDate(ctx, user_id, startDate, endDate)
+	if err != nil {
+		return helper.ResponseWorkModeReport{}, err
+	}
+
+	modes := map[string]*helper.ResponseWorkModeHours{}
+	days := map[string]map[string]bool{}
+	for _, mode := range entity.WorkModes {
+		modes[mode] = &helper.ResponseWorkModeHours{WorkMode: mode}
+		days[mode] = map[string]bool{}
+	}
+
+	var totalHours float64
+	for _, session := range helper.PairAttendances(attendances) {
+		mode := session.CheckIn.WorkMode
+		if mode == "" {
+			// Attendances made before work modes existed
+			mode = "unspecified"
+		}
+		if modes[mode] == nil {
+			modes[mode] = &helper.ResponseWorkModeHours{WorkMode: mode}
+			days[mode] = map[string]bool{}
+		}
+
+		hours := session.Duration().Hours()
+		modes[mode].Hours += hours
+		days[mode][helper.UnixMilliToString(session.CheckIn.Date, "date")] = true
+		totalHours += hours
+	}
+
+	report := helper.ResponseWorkModeReport{
+		UserId:     user_id,
+		StartDate:  helper.UnixMilliToString(startDate, "date"),
+		EndDate:    helper.UnixMilliToString(endDate, "date"),
+		TotalHours: helper.RoundHours(totalHours),
+	}
+	for _, mode := range append(entity.WorkModes, "unspecified") {
+		if modes[mode] == nil {
+			continue
+		}
+		modes[mode].Days = len(days[mode])
+		modes[mode].Hours = helper.RoundHours(modes[mode].Hours)
+		report.Modes = append(report.Modes, *modes[mode])
+	}
+	return report, nil
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"armiariyan/attendances-system/entity"
+	"armiariyan/attendances-system/helper"
+	"armiariyan/attendances-system/repository"
+	"context"
+	"time"
+)
+
+type TimesheetService interface {
+	GetTimesheet(ctx context.Context, user_id int, month time.Time) (helper.ResponseTimesheet, error)
+	GetDays(ctx context.Context, user_id int, from, to time.Time) ([]helper.ResponseTimesheetDay, error)
+}
+
+type timesheetService struct {
+	attendanceRepository repository.AttendanceRepository
+	activityRepository   repository.ActivityRepository
+	leaveRepository      repository.LeaveRepository
+	calendarService      CalendarService
+}
+
+func NewTimesheetService(attendanceRepository repository.AttendanceRepository, activityRepository repository.ActivityRepository, leaveRepository repository.LeaveRepository, calendarService CalendarService) TimesheetService {
+	return &timesheetService{
+		attendanceRepository: attendanceRepository,
+		activityRepository:   activityRepository,
+		leaveRepository:      leaveRepository,
+		calendarService:      calendarService,
+	}
+}
+
+// GetTimesheet returns one row for every day of the month with the totals of the month
+func (service *timesheetService) GetTimesheet(ctx context.Context, user_id int, month time.Time) (helper.ResponseTimesheet, error) {
+	from := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.Local)
+	to := from.AddDate(0, 1, -1)
+
+	days, err := service.GetDays(ctx, user_id, from, to)
+	if err != nil {
+		return helper.ResponseTimesheet{}, err
+	}
+	timesheet := helper.ResponseTimesheet{
+		UserId: user_id,
+		Month:  from.Format("2006-01"),
+		Days:   days,
+	}
+
+	var totalBreak, totalWorked float64
+	for _, day := range timesheet.Days {
+		switch day.Status {
+		case helper.DayWorked:
+			timesheet.Totals.WorkedDays++
+		case helper.DayLeave:
+			timesheet.Totals.LeaveDays++
+		case helper.DayHoliday:
+			timesheet.Totals.Holidays++
+		case helper.DayAbsent:
+			timesheet.Totals.AbsentDays++
+		case helper.DayWeekend:
+			timesheet.Totals.WeekendDays++
+		}
+		totalBreak += day.BreakHours
+		totalWorked += day.WorkedHours
+		timesheet.Totals.ActivityCount += len(day.Activities)
+	}
+	timesheet.Totals.BreakHours = helper.RoundHours(totalBreak)
+	timesheet.Totals.WorkedHours = helper.RoundHours(totalWorked)
+	return timesheet, nil
+}
+
+// GetDays builds a timesheet row for every day from until to, both dates included
+func (service *timesheetService) GetDays(ctx context.Context, user_id int, from, to time.Time) ([]helper.ResponseTimesheetDay, error) {
+	startDate, _ := helper.DayRange(from)
+	_, endDate := helper.DayRange(to)
+	firstDay := from.Format("2006-01-02")
+	lastDay := to.Format("2006-01-02")
+	today := time.Now().Format("2006-01-02")
+
+	attendances, err := service.attendanceRepository.GetAttendancesByDate(ctx, user_id, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	activities, err := service.activityRepository.GetActivitiesByDate(ctx, user_id, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	// Sessions and activities belong to the day they started
+	sessionsOf := map[string][]helper.WorkSession{}
+	for _, session := range helper.PairAttendances(attendances) {
+		date := helper.UnixMilliToString(session.CheckIn.Date, "date")
+		sessionsOf[date] = append(sessionsOf[date], session)
+	}
+	activitiesOf := map[string][]entity.Activity{}
+	for _, activity := range activities {
+		date := helper.UnixMilliToString(activity.DateCreated, "date")
+		activitiesOf[date] = append(activitiesOf[date], activity)
+	}
+
+	saved, err := service.calendarService.GetHolidays(ctx, firstDay, lastDay)
+	if err != nil {
+		return nil, err
+	}
+	holidays := map[string]bool{}
+	for _, holiday := range saved {
+		holidays[holiday.Date] = true
+	}
+	var leaves []entity.Leave
+	for _, leave := range service.leaveRepository.GetApprovedLeavesByDate(ctx, firstDay, lastDay) {
+		if leave.UserId == user_id {
+			leaves = append(leaves, leave)
+		}
+	}
+
+	days := []helper.ResponseTimesheetDay{}
+	for current := from; current.Format("2006-01-02") <= lastDay; current = current.AddDate(0, 0, 1) {
+		date := current.Format("2006-01-02")
+		day := helper.ResponseTimesheetDay{
+			Date:       date,
+			Weekday:    current.Weekday().String(),
+			Activities: helper.CreateActivityResponses(activitiesOf[date]),
+		}
+		if day.Activities == nil {
+			day.Activities = []helper.ResponseActivity{}
+		}
+
+		var breakTime, worked time.Duration
+		for i, session := range sessionsOf[date] {
+			if i == 0 {
+				day.FirstIn = helper.UnixMilliToString(session.CheckIn.Time, "time")
+			}
+			if !session.Open {
+				day.LastOut = helper.UnixMilliToString(session.CheckOut.Time, "time")
+			}
+			breakTime += session.BreakDuration()
+			worked += session.Duration()
+		}
+		day.BreakHours = helper.RoundHours(breakTime.Hours())
+		day.WorkedHours = helper.RoundHours(worked.Hours())
+
+		// Working wins, then the calendar, then leave
+		switch {
+		case len(sessionsOf[date]) > 0:
+			day.Status = helper.DayWorked
+		case holidays[date]:
+			day.Status = helper.DayHoliday
+		case !service.calendarService.IsWorkDay(current.Weekday()):
+			day.Status = helper.DayWeekend
+		case isOnLeave(leaves, date):
+			day.Status = helper.DayLeave
+		case date >= today:
+			day.Status = helper.DayUpcoming
+		default:
+			day.Status = helper.DayAbsent
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+func isOnLeave(leaves []entity.Leave, date string) bool {
+	for _, leave := range leaves {
+		if leave.StartDate <= date && leave.EndDate >= date {
+			return true
+		}
+	}
+	return false
+}
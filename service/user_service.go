@@ -3,26 +3,22 @@ package service
 import (
 	"armiariyan/attendances-system/dto"
 	"armiariyan/attendances-system/entity"
-	"armiariyan/attendances-system/helper"
 	"armiariyan/attendances-system/repository"
-	"log"
+	"context"
+	"errors"
+	"fmt"
 
 	"github.com/mashingan/smapping"
 )
 
+// UserService returns the domain errors of the repository wrapped, ErrNotFound for a missing user
 type UserService interface {
-	CreateUser(user dto.RegisterDTO) entity.User
-	VerifyCredential(email string) interface{}
-	ChangeStatusLogin(data entity.User) entity.User
-	GetUserById(user_id int) entity.User
-	GetActivityById(act_id string) entity.Activity
-	CheckIn(data entity.Attendance) entity.Attendance
-	CreateActivity(data entity.Activity) entity.Activity
-	UpdateActivity(data entity.Activity) entity.Activity
-	DeleteActivity(data entity.Activity)
-	GetActivityHistoryByDate(startDate, endDate int64) []entity.Activity
-	GetAttendancesHistory(user_id int) []entity.Attendance
-	IsDuplicateEmail(email string) bool
+	CreateUser(ctx context.Context, user dto.RegisterDTO) (entity.User, error)
+	VerifyCredential(ctx context.Context, email string) (entity.User, error)
+	ChangeStatusLogin(ctx context.Context, data entity.User) (entity.User, error)
+	GetUserById(ctx context.Context, user_id int) (entity.User, error)
+	GetUsers(ctx context.Context) ([]entity.User, error)
+	IsDuplicateEmail(ctx context.Context, email string) (bool, error)
 }
 
 type userService struct {
@@ -35,64 +31,52 @@ func NewUserService(repository repository.UserRepository) UserService {
 	}
 }
 
-func (service *userService) CreateUser(user dto.RegisterDTO) entity.User {
+func (service *userService) CreateUser(ctx context.Context, user dto.RegisterDTO) (entity.User, error) {
 	userToCreate := entity.User{}
 	err := smapping.FillStruct(&userToCreate, smapping.MapFields(&user))
 	if err != nil {
-		log.Fatalf("Failed map %v", err)
+		return entity.User{}, fmt.Errorf("map user: %w", err)
 	}
-	res := service.userRepository.RegisterUser(userToCreate)
-	return res
-}
-
-func (service *userService) IsDuplicateEmail(email string) bool {
-	res := service.userRepository.GetDataByEmail(email)
-	// Return false if data empty, true if there is one
-	return !helper.IsUserEmpty(res)
-}
-
-func (service *userService) VerifyCredential(email string) interface{} {
-	res := service.userRepository.VerifyCredential(email)
-	if res == nil {
-		return nil
-	} else {
-		return res
+	var res entity.User
+	err = service.userRepository.Transaction(ctx, func(tx repository.UserRepository) error {
+		var err error
+		if res, err = tx.RegisterUser(ctx, userToCreate); err != nil {
+			return err
+		}
+		return emit(ctx, tx, entity.EventUserRegistered, map[string]interface{}{
+			"id":    res.Id,
+			"name":  res.Name,
+			"email": res.Email,
+			"role":  res.Role,
+		})
+	})
+	if err != nil {
+		return entity.User{}, fmt.Errorf("register user %s: %w", userToCreate.Email, err)
 	}
+	return res, nil
 }
 
-func (service *userService) ChangeStatusLogin(data entity.User) entity.User {
-	res := service.userRepository.ChangeStatusLogin(data)
-	return res
-}
-
-func (service *userService) GetUserById(user_id int) entity.User {
-	return service.userRepository.GetUserById(user_id)
-}
-
-func (service *userService) GetActivityById(act_id string) entity.Activity {
-	return service.userRepository.GetActivityById(act_id)
-}
-
-func (service *userService) CheckIn(data entity.Attendance) entity.Attendance {
-	return service.userRepository.CheckIn(data)
-}
-
-func (service *userService) CreateActivity(data entity.Activity) entity.Activity {
-	return service.userRepository.CreateActivity(data)
+func (service *userService) IsDuplicateEmail(ctx context.Context, email string) (bool, error) {
+	_, err := service.userRepository.GetDataByEmail(ctx, email)
+	if errors.Is(err, repository.ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
 }
 
-func (service *userService) UpdateActivity(data entity.Activity) entity.Activity {
-	return service.userRepository.UpdateActivity(data)
+// VerifyCredential returns ErrNotFound for an unknown email
+func (service *userService) VerifyCredential(ctx context.Context, email string) (entity.User, error) {
+	return service.userRepository.VerifyCredential(ctx, email)
 }
 
-func (service *userService) DeleteActivity(data entity.Activity) {
-	service.userRepository.DeleteActivity(data)
+func (service *userService) ChangeStatusLogin(ctx context.Context, data entity.User) (entity.User, error) {
+	return service.userRepository.ChangeStatusLogin(ctx, data)
 }
 
-func (service *userService) GetActivityHistoryByDate(startDate, endDate int64) []entity.Activity {
-	return service.userRepository.GetActivityHistoryByDate(startDate, endDate)
+func (service *userService) GetUserById(ctx context.Context, user_id int) (entity.User, error) {
+	return service.userRepository.GetUserById(ctx, user_id)
 }
 
-func (service *userService) GetAttendancesHistory(user_id int) []entity.Attendance {
-	return service.userRepository.GetAttendancesHistory(user_id)
+func (service *userService) GetUsers(ctx context.Context) ([]entity.User, error) {
+	return service.userRepository.GetUsers(ctx)
 }
@@ -0,0 +1,281 @@
+package service
+
+import (
+	"armiariyan/attendances-system/dto"
+	"armiariyan/attendances-system/entity"
+	"armiariyan/attendances-system/logger"
+	"armiariyan/attendances-system/repository"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// webhookMaxAttempts is how often a delivery is tried before it goes to the dead letter state
+	webhookMaxAttempts = 8
+	webhookBaseBackoff = 30 * time.Second
+	webhookMaxBackoff  = 2 * time.Hour
+	webhookBatchSize   = 50
+	webhookTimeout     = 10 * time.Second
+)
+
+// WebhookPayload is the json body sent to subscribers
+type WebhookPayload struct {
+	Id         string      `json:"id"`
+	Type       string      `json:"type"`
+	OccurredAt string      `json:"occurred_at"`
+	Data       interface{} `json:"data"`
+}
+
+// WebhookService returns the domain errors of the repository wrapped, ErrNotFound for a
+// missing subscription or delivery
+type WebhookService interface {
+	GetSubscriptions(ctx context.Context) ([]entity.WebhookSubscription, error)
+	GetSubscriptionById(ctx context.Context, subscription_id int) (entity.WebhookSubscription, error)
+	CreateSubscription(ctx context.Context, data dto.WebhookDTO) (entity.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, subscription entity.WebhookSubscription) error
+	GetDeliveries(ctx context.Context, subscription_id int) ([]entity.WebhookDelivery, error)
+	GetDeliveryById(ctx context.Context, delivery_id int) (entity.WebhookDelivery, error)
+	RetryDelivery(ctx context.Context, delivery entity.WebhookDelivery) (entity.WebhookDelivery, error)
+	Publish(ctx context.Context, event entity.OutboxEvent) error
+	DeliverDue(ctx context.Context, now time.Time) int
+}
+
+type webhookService struct {
+	webhookRepository repository.WebhookRepository
+	client            *http.Client
+	maxAttempts       int
+	baseBackoff       time.Duration
+	maxBackoff        time.Duration
+}
+
+func NewWebhookService(repository repository.WebhookRepository) WebhookService {
+	return &webhookService{
+		webhookRepository: repository,
+		client:            &http.Client{Timeout: webhookTimeout},
+		maxAttempts:       webhookMaxAttempts,
+		baseBackoff:       webhookBaseBackoff,
+		maxBackoff:        webhookMaxBackoff,
+	}
+}
+
+func (service *webhookService) GetSubscriptions(ctx context.Context) ([]entity.WebhookSubscription, error) {
+	return service.webhookRepository.GetSubscriptions(ctx)
+}
+
+func (service *webhookService) GetSubscriptionById(ctx context.Context, subscription_id int) (entity.WebhookSubscription, error) {
+	return service.webhookRepository.GetSubscriptionById(ctx, subscription_id)
+}
+
+// CreateSubscription generates a secret when the subscriber didn't bring one
+func (service *webhookService) CreateSubscription(ctx context.Context, data dto.WebhookDTO) (entity.WebhookSubscription, error) {
+	secret := data.Secret
+	if secret == "" {
+		random := make([]byte, 32)
+		if _, err := rand.Read(random); err != nil {
+			return entity.WebhookSubscription{}, fmt.Errorf("failed to generate webhook secret: %w", err)
+		}
+		secret = hex.EncodeToString(random)
+	}
+
+	return service.webhookRepository.CreateSubscription(ctx, entity.WebhookSubscription{
+		URL:        data.URL,
+		Secret:     secret,
+		EventTypes: strings.Join(data.EventTypes, ","),
+		Active:     true,
+	})
+}
+
+func (service *webhookService) DeleteSubscription(ctx context.Context, subscription entity.WebhookSubscription) error {
+	return service.webhookRepository.DeleteSubscription(ctx, subscription)
+}
+
+func (service *webhookService) GetDeliveries(ctx context.Context, subscription_id int) ([]entity.WebhookDelivery, error) {
+	return service.webhookRepository.GetDeliveriesBySubscription(ctx, subscription_id)
+}
+
+func (service *webhookService) GetDeliveryById(ctx context.Context, delivery_id int) (entity.WebhookDelivery, error) {
+	return service.webhookRepository.GetDeliveryById(ctx, delivery_id)
+}
+
+// RetryDelivery puts a delivery back in the queue with a fresh set of attempts
+func (service *webhookService) RetryDelivery(ctx context.Context, delivery entity.WebhookDelivery) (entity.WebhookDelivery, error) {
+	delivery.Status = entity.DeliveryPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = time.Now().UnixMilli()
+	delivery.LastError = ""
+	return service.webhookRepository.UpdateDelivery(ctx, delivery)
+}
+
+// Publish queues a delivery of the event for every active subscription, the
+// worker sends them. Publishing the same event twice doesn't queue it twice, so
+// the first failure is returned and the dispatcher hands the whole event again
+func (service *webhookService) Publish(ctx context.Context, event entity.OutboxEvent) error {
+	payload, err := json.Marshal(WebhookPayload{
+		Id:         event.EventId,
+		Type:       event.Type,
+		OccurredAt: time.UnixMilli(event.OccurredAt).Format(time.RFC3339),
+		Data:       json.RawMessage(event.Payload),
+	})
+	if err != nil {
+		return fmt.Errorf("webhook %s: failed to encode payload: %v", event.Type, err)
+	}
+
+	subscriptions, err := service.webhookRepository.GetSubscriptions(ctx)
+	if err != nil {
+		return fmt.Errorf("webhook %s: get subscriptions: %w", event.Type, err)
+	}
+	for _, subscription := range subscriptions {
+		if !subscription.Active || !isSubscribed(subscription, event.Type) {
+			continue
+		}
+		_, err := service.webhookRepository.CreateDelivery(ctx, entity.WebhookDelivery{
+			SubscriptionId: subscription.Id,
+			EventId:        event.EventId,
+			EventType:      event.Type,
+			Payload:        string(payload),
+			Status:         entity.DeliveryPending,
+			NextAttemptAt:  time.Now().UnixMilli(),
+		})
+		if err != nil {
+			return fmt.Errorf("webhook %s: queue delivery for subscription %d: %w", event.Type, subscription.Id, err)
+		}
+	}
+	return nil
+}
+
+// DeliverDue sends the deliveries that are due and returns how many were tried,
+// it stops early when ctx is done
+func (service *webhookService) DeliverDue(ctx context.Context, now time.Time) int {
+	deliveries, err := service.webhookRepository.GetDueDeliveries(ctx, now.UnixMilli(), webhookBatchSize)
+	if err != nil {
+		logger.Log.WithError(err).Error("failed to get due webhook deliveries")
+		return 0
+	}
+	for i, delivery := range deliveries {
+		if ctx.Err() != nil {
+			return i
+		}
+		service.deliver(ctx, delivery, now)
+	}
+	return len(deliveries)
+}
+
+func (service *webhookService) deliver(ctx context.Context, delivery entity.WebhookDelivery, now time.Time) {
+	// A subscription that can't be read isn't an attempt either, only a deleted one kills the delivery
+	subscription, err := service.webhookRepository.GetSubscriptionById(ctx, delivery.SubscriptionId)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		logger.Log.WithFields(logrus.Fields{
+			"delivery_id": delivery.Id,
+			"event_id":    delivery.EventId,
+		}).WithError(err).Error("failed to get webhook subscription")
+		return
+	}
+	delivery.Attempts++
+
+	status, err := service.send(ctx, subscription, delivery, now)
+	// A request cut by the shutdown isn't an attempt, the delivery stays due
+	if err != nil && ctx.Err() != nil {
+		return
+	}
+	delivery.ResponseStatus = status
+	switch {
+	case err == nil:
+		delivery.Status = entity.DeliverySucceeded
+		delivery.LastError = ""
+	case delivery.Attempts >= service.maxAttempts || subscription.Id == 0:
+		delivery.Status = entity.DeliveryDead
+		delivery.LastError = truncate(err.Error(), 512)
+	default:
+		delivery.NextAttemptAt = now.Add(service.backoff(delivery.Attempts)).UnixMilli()
+		delivery.LastError = truncate(err.Error(), 512)
+	}
+	// The delivery stays due when its result can't be saved, it is sent again
+	if _, err := service.webhookRepository.UpdateDelivery(ctx, delivery); err != nil {
+		logger.Log.WithFields(logrus.Fields{
+			"delivery_id": delivery.Id,
+			"event_id":    delivery.EventId,
+		}).WithError(err).Error("failed to save webhook delivery")
+	}
+}
+
+func (service *webhookService) send(ctx context.Context, subscription entity.WebhookSubscription, delivery entity.WebhookDelivery, now time.Time) (int, error) {
+	if subscription.Id == 0 {
+		return 0, fmt.Errorf("webhook subscription %d no longer exists", delivery.SubscriptionId)
+	}
+
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewBufferString(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "attendances-system-webhook")
+	request.Header.Set("X-Webhook-Event", delivery.EventType)
+	request.Header.Set("X-Webhook-Event-Id", delivery.EventId)
+	request.Header.Set("X-Webhook-Delivery", strconv.Itoa(delivery.Id))
+	request.Header.Set("X-Webhook-Timestamp", timestamp)
+	request.Header.Set("X-Webhook-Signature", "sha256="+SignWebhook(subscription.Secret, timestamp, []byte(delivery.Payload)))
+
+	response, err := service.client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(response.Body, 64<<10))
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return response.StatusCode, fmt.Errorf("subscriber answered %s", response.Status)
+	}
+	return response.StatusCode, nil
+}
+
+// backoff doubles the wait after every failed attempt up to maxBackoff
+func (service *webhookService) backoff(attempts int) time.Duration {
+	wait := service.baseBackoff
+	for i := 1; i < attempts && wait < service.maxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > service.maxBackoff {
+		wait = service.maxBackoff
+	}
+	return wait
+}
+
+// SignWebhook returns the hex hmac sha256 of "<timestamp>.<body>", subscribers
+// compute the same value with their secret to verify X-Webhook-Signature
+func SignWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func isSubscribed(subscription entity.WebhookSubscription, eventType string) bool {
+	for _, subscribed := range strings.Split(subscription.EventTypes, ",") {
+		if subscribed == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func truncate(value string, length int) string {
+	if len(value) > length {
+		return value[:length]
+	}
+	return value
+}
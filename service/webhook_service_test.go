@@ -0,0 +1,364 @@
+package service
+
+import (
+	"armiariyan/attendances-system/dto"
+	"armiariyan/attendances-system/entity"
+	"armiariyan/attendances-system/repository"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// webhookMemory keeps subscriptions and deliveries in memory, createErr fails every
+// CreateDelivery and getErr every GetSubscriptionById
+type webhookMemory struct {
+	subscriptions []entity.WebhookSubscription
+	deliveries    []entity.WebhookDelivery
+	createErr     error
+	getErr        error
+}
+
+func (m *webhookMemory) GetSubscriptions(_ context.Context) ([]entity.WebhookSubscription, error) {
+	return m.subscriptions, nil
+}
+
+func (m *webhookMemory) GetSubscriptionById(_ context.Context, subscription_id int) (entity.WebhookSubscription, error) {
+	if m.getErr != nil {
+		return entity.WebhookSubscription{}, m.getErr
+	}
+	for _, subscription := range m.subscriptions {
+		if subscription.Id == subscription_id {
+			return subscription, nil
+		}
+	}
+	return entity.WebhookSubscription{}, repository.ErrNotFound
+}
+
+func (m *webhookMemory) CreateSubscription(_ context.Context, data entity.WebhookSubscription) (entity.WebhookSubscription, error) {
+	data.Id = len(m.subscriptions) + 1
+	m.subscriptions = append(m.subscriptions, data)
+	return data, nil
+}
+
+func (m *webhookMemory) DeleteSubscription(_ context.Context, subscription entity.WebhookSubscription) error {
+	for i, current := range m.subscriptions {
+		if current.Id == subscription.Id {
+			m.subscriptions = append(m.subscriptions[:i], m.subscriptions[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (m *webhookMemory) CreateDelivery(_ context.Context, data entity.WebhookDelivery) (entity.WebhookDelivery, error) {
+	if m.createErr != nil {
+		return data, m.createErr
+	}
+	for _, delivery := range m.deliveries {
+		if delivery.SubscriptionId == data.SubscriptionId && delivery.EventId == data.EventId {
+			return delivery, nil
+		}
+	}
+	data.Id = len(m.deliveries) + 1
+	m.deliveries = append(m.deliveries, data)
+	return data, nil
+}
+
+func (m *webhookMemory) UpdateDelivery(_ context.Context, data entity.WebhookDelivery) (entity.WebhookDelivery, error) {
+	m.deliveries[data.Id-1] = data
+	return data, nil
+}
+
+func (m *webhookMemory) GetDeliveryById(_ context.Context, delivery_id int) (entity.WebhookDelivery, error) {
+	return m.deliveries[delivery_id-1], nil
+}
+
+func (m *webhookMemory) GetDeliveriesBySubscription(_ context.Context, subscription_id int) ([]entity.WebhookDelivery, error) {
+	var deliveries []entity.WebhookDelivery
+	for _, delivery := range m.deliveries {
+		if delivery.SubscriptionId == subscription_id {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	return deliveries, nil
+}
+
+func (m *webhookMemory) GetDueDeliveries(_ context.Context, now int64, limit int) ([]entity.WebhookDelivery, error) {
+	var deliveries []entity.WebhookDelivery
+	for _, delivery := range m.deliveries {
+		if delivery.Status == entity.DeliveryPending && delivery.NextAttemptAt <= now && len(deliveries) < limit {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	return deliveries, nil
+}
+
+// receiver records every request and answers with the next status of statuses
+type receiver struct {
+	mu       sync.Mutex
+	statuses []int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, request *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	body, _ := io.ReadAll(request.Body)
+	r.requests = append(r.requests, request)
+	r.bodies = append(r.bodies, body)
+
+	status := http.StatusOK
+	if len(r.statuses) > 0 {
+		status = r.statuses[0]
+		r.statuses = r.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+func newTestWebhookService(memory *webhookMemory) *webhookService {
+	service := NewWebhookService(memory).(*webhookService)
+	service.baseBackoff = time.Second
+	service.maxBackoff = 4 * time.Second
+	service.maxAttempts = 3
+	return service
+}
+
+func subscribe(t *testing.T, service WebhookService, data dto.WebhookDTO) entity.WebhookSubscription {
+	subscription, err := service.CreateSubscription(context.Background(), data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return subscription
+}
+
+func publish(t *testing.T, service WebhookService, eventType string, data interface{}) entity.OutboxEvent {
+	event, err := NewOutboxEvent(eventType, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := service.Publish(context.Background(), event); err != nil {
+		t.Fatal(err)
+	}
+	return event
+}
+
+func TestWebhookDeliverySigned(t *testing.T) {
+	target := &receiver{}
+	server := httptest.NewServer(target)
+	defer server.Close()
+
+	memory := &webhookMemory{}
+	service := newTestWebhookService(memory)
+	subscription := subscribe(t, service, dto.WebhookDTO{
+		URL:        server.URL,
+		Secret:     "0123456789abcdef",
+		EventTypes: []string{entity.EventCheckedIn},
+	})
+
+	event := publish(t, service, entity.EventCheckedIn, map[string]int{"id_user": 7})
+	publish(t, service, entity.EventCheckedOut, map[string]int{"id_user": 7})
+
+	// The dispatcher hands an event again after a crash, it is queued once
+	if err := service.Publish(context.Background(), event); err != nil {
+		t.Fatal(err)
+	}
+	if sent := service.DeliverDue(context.Background(), time.Now()); sent != 1 {
+		t.Fatalf("expected 1 delivery for the subscribed event, got %d", sent)
+	}
+
+	if len(target.requests) != 1 {
+		t.Fatalf("expected receiver to get 1 request, got %d", len(target.requests))
+	}
+	request := target.requests[0]
+	if request.Header.Get("X-Webhook-Event") != entity.EventCheckedIn {
+		t.Errorf("unexpected event header %q", request.Header.Get("X-Webhook-Event"))
+	}
+	// Receivers drop duplicates by the event id, the same for every retry of the event
+	if request.Header.Get("X-Webhook-Event-Id") != event.EventId {
+		t.Errorf("event id header %q, want %q", request.Header.Get("X-Webhook-Event-Id"), event.EventId)
+	}
+
+	// The receiver verifies the signature the same way a subscriber would
+	expected := "sha256=" + SignWebhook(subscription.Secret, request.Header.Get("X-Webhook-Timestamp"), target.bodies[0])
+	if request.Header.Get("X-Webhook-Signature") != expected {
+		t.Errorf("signature %q doesn't match %q", request.Header.Get("X-Webhook-Signature"), expected)
+	}
+
+	var payload WebhookPayload
+	if err := json.Unmarshal(target.bodies[0], &payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload.Type != entity.EventCheckedIn || payload.Id != event.EventId {
+		t.Errorf("unexpected payload %+v", payload)
+	}
+
+	delivery := memory.deliveries[0]
+	if delivery.Status != entity.DeliverySucceeded || delivery.Attempts != 1 || delivery.ResponseStatus != http.StatusOK {
+		t.Errorf("unexpected delivery %+v", delivery)
+	}
+}
+
+func TestWebhookDeliveryRetriesWithBackoff(t *testing.T) {
+	target := &receiver{statuses: []int{http.StatusInternalServerError, http.StatusBadGateway}}
+	server := httptest.NewServer(target)
+	defer server.Close()
+
+	memory := &webhookMemory{}
+	service := newTestWebhookService(memory)
+	subscribe(t, service, dto.WebhookDTO{URL: server.URL, EventTypes: []string{entity.EventActivityCreated}})
+	publish(t, service, entity.EventActivityCreated, map[string]string{"id": "ACT-1"})
+
+	now := time.Now()
+	service.DeliverDue(context.Background(), now)
+	if next := memory.deliveries[0].NextAttemptAt; next != now.Add(time.Second).UnixMilli() {
+		t.Fatalf("expected first retry after 1s, got %dms", next-now.UnixMilli())
+	}
+
+	// Nothing is due before the backoff passed
+	if sent := service.DeliverDue(context.Background(), now.Add(500*time.Millisecond)); sent != 0 {
+		t.Fatalf("expected no delivery during backoff, got %d", sent)
+	}
+
+	now = now.Add(time.Second)
+	service.DeliverDue(context.Background(), now)
+	if next := memory.deliveries[0].NextAttemptAt; next != now.Add(2*time.Second).UnixMilli() {
+		t.Fatalf("expected second retry after 2s, got %dms", next-now.UnixMilli())
+	}
+
+	now = now.Add(2 * time.Second)
+	service.DeliverDue(context.Background(), now)
+	delivery := memory.deliveries[0]
+	if delivery.Status != entity.DeliverySucceeded || delivery.Attempts != 3 {
+		t.Errorf("expected success on third attempt, got %+v", delivery)
+	}
+}
+
+func TestWebhookDeliveryDeadLetter(t *testing.T) {
+	target := &receiver{statuses: []int{500, 500, 500, 500}}
+	server := httptest.NewServer(target)
+	defer server.Close()
+
+	memory := &webhookMemory{}
+	service := newTestWebhookService(memory)
+	subscribe(t, service, dto.WebhookDTO{URL: server.URL, EventTypes: []string{entity.EventCheckedOut}})
+	publish(t, service, entity.EventCheckedOut, nil)
+
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		service.DeliverDue(context.Background(), now)
+		now = now.Add(time.Hour)
+	}
+
+	delivery := memory.deliveries[0]
+	if delivery.Status != entity.DeliveryDead || delivery.Attempts != 3 || delivery.LastError == "" {
+		t.Fatalf("expected dead delivery after 3 attempts, got %+v", delivery)
+	}
+	if len(target.requests) != 3 {
+		t.Errorf("expected 3 requests, got %d", len(target.requests))
+	}
+
+	// A dead delivery can be queued again by hand
+	if _, err := service.RetryDelivery(context.Background(), delivery); err != nil {
+		t.Fatal(err)
+	}
+	target.statuses = nil
+	service.DeliverDue(context.Background(), time.Now())
+	if memory.deliveries[0].Status != entity.DeliverySucceeded {
+		t.Errorf("expected retried delivery to succeed, got %+v", memory.deliveries[0])
+	}
+}
+
+func TestWebhookDeliveryWaitsForUnreadableSubscription(t *testing.T) {
+	target := &receiver{}
+	server := httptest.NewServer(target)
+	defer server.Close()
+
+	memory := &webhookMemory{}
+	service := newTestWebhookService(memory)
+	subscribe(t, service, dto.WebhookDTO{URL: server.URL, EventTypes: []string{entity.EventCheckedIn}})
+	publish(t, service, entity.EventCheckedIn, nil)
+
+	// An outage isn't a deleted subscription, the delivery stays due without using an attempt
+	memory.getErr = repository.ErrUnavailable
+	service.DeliverDue(context.Background(), time.Now())
+	if delivery := memory.deliveries[0]; delivery.Status != entity.DeliveryPending || delivery.Attempts != 0 || len(target.requests) != 0 {
+		t.Fatalf("delivery = %+v after %d requests, want pending and unsent", delivery, len(target.requests))
+	}
+
+	memory.getErr = nil
+	service.DeliverDue(context.Background(), time.Now())
+	if delivery := memory.deliveries[0]; delivery.Status != entity.DeliverySucceeded || delivery.Attempts != 1 {
+		t.Errorf("delivery = %+v, want sent once the subscription can be read", delivery)
+	}
+}
+
+func TestWebhookPublishFailureKeepsEventPending(t *testing.T) {
+	bus, outbox := newTestEventBus(t)
+	memory := &webhookMemory{createErr: repository.ErrUnavailable}
+	service := newTestWebhookService(memory)
+	subscribe(t, service, dto.WebhookDTO{URL: "http://127.0.0.1", EventTypes: []string{entity.EventCheckedIn}})
+	bus.Subscribe(entity.EventCheckedIn, service.Publish)
+
+	// The delivery can't be queued, the event waits for the next dispatch
+	bus.Dispatch(context.Background(), time.Now())
+	event := outbox.updates[len(outbox.updates)-1]
+	if event.Status != entity.OutboxPending || event.Attempts != 1 || !strings.Contains(event.LastError, repository.ErrUnavailable.Error()) {
+		t.Fatalf("event = %+v, want pending with the error of CreateDelivery", event)
+	}
+	if len(memory.deliveries) != 0 {
+		t.Fatalf("deliveries = %+v, want none", memory.deliveries)
+	}
+
+	memory.createErr = nil
+	bus.Dispatch(context.Background(), time.Now())
+	event = outbox.updates[len(outbox.updates)-1]
+	if event.Status != entity.OutboxDispatched || event.Attempts != 2 {
+		t.Errorf("event = %+v, want dispatched on the second attempt", event)
+	}
+	if len(memory.deliveries) != 1 || memory.deliveries[0].EventId != event.EventId {
+		t.Errorf("deliveries = %+v, want the event queued once", memory.deliveries)
+	}
+}
+
+func TestWebhookDeliveryStopsWhenContextDone(t *testing.T) {
+	started := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The request is only cut once its body was read
+		io.ReadAll(r.Body)
+		started <- struct{}{}
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	memory := &webhookMemory{}
+	service := newTestWebhookService(memory)
+	subscribe(t, service, dto.WebhookDTO{URL: server.URL, EventTypes: []string{entity.EventCheckedIn}})
+	subscribe(t, service, dto.WebhookDTO{URL: server.URL, EventTypes: []string{entity.EventCheckedIn}})
+	publish(t, service, entity.EventCheckedIn, nil)
+
+	// The shutdown cuts the first request and the second delivery isn't sent
+	ctx, cancel := context.WithCancel(context.Background())
+	tried := make(chan int, 1)
+	go func() {
+		tried <- service.DeliverDue(ctx, time.Now())
+	}()
+	<-started
+	cancel()
+
+	if sent := <-tried; sent != 1 {
+		t.Errorf("DeliverDue tried %d deliveries, want 1", sent)
+	}
+	for _, delivery := range memory.deliveries {
+		if delivery.Status != entity.DeliveryPending || delivery.Attempts != 0 {
+			t.Errorf("delivery = %+v, want pending without an attempt", delivery)
+		}
+	}
+}